@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	tui "cobra-cli/internal/tui"
+	"cobra-cli/internal/vault"
+)
+
+var activityWeeksFlag int
+var activityJSONFlag bool
+
+// activityCmd represents the activity command
+var activityCmd = &cobra.Command{
+	Use:   "activity",
+	Short: "Show a heatmap of writing activity and streaks",
+	Long: `Show a calendar heatmap of writing activity in the current vault, with the
+current and longest streaks of consecutive active days and words written per day.
+
+Activity comes from the vault's history log and the modification times of its notes.
+Notes edited outside noted only leave their last modification time, so their whole
+word count is credited to that day.
+
+On a terminal the heatmap is drawn; otherwise, or with --json, the activity is
+printed as JSON.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showActivity(cmd.OutOrStdout(), activityWeeksFlag, activityJSONFlag || !isTerminal(cmd.OutOrStdout()))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(activityCmd)
+	activityCmd.Flags().IntVarP(&activityWeeksFlag, "weeks", "w", 26, "Number of weeks shown in the heatmap")
+	activityCmd.Flags().BoolVar(&activityJSONFlag, "json", false, "Print JSON even on a terminal")
+}
+
+func showActivity(out io.Writer, weeks int, asJSON bool) error {
+	if weeks < 1 || weeks > 104 {
+		return fmt.Errorf("--weeks must be between 1 and 104")
+	}
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	activity, err := vault.CollectActivity(v, now)
+	if err != nil {
+		return fmt.Errorf("collect activity: %w", err)
+	}
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(activity)
+	}
+	fmt.Fprintln(out, tui.RenderHeatmap(activity, weeks, now))
+	return nil
+}
@@ -0,0 +1,270 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/journal"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/notes"
+	tui "cobra-cli/internal/tui"
+	"cobra-cli/internal/vault"
+)
+
+var (
+	boardFolderFlag  string
+	boardFieldFlag   string
+	boardColumnsFlag []string
+)
+
+// boardCmd represents the board command
+var boardCmd = &cobra.Command{
+	Use:   "board [note]",
+	Short: "Show a kanban board of a board note or a folder",
+	Long: `Show a kanban board and move its cards between columns.
+
+The board is either one note, whose ## headings are the columns and the list items
+under them the cards:
+
+  noted board sprint
+
+or the notes of a folder, put in columns by a frontmatter field:
+
+  noted board --folder sprint                       # Columns from 'status:'
+  noted board --folder sprint --columns todo,doing,done
+  noted board --folder bugs --field stage
+
+Notes without the field go in the first column. Without --columns, the columns are
+the values found, in alphabetical order. Values that differ only in case, such as Todo
+and todo, share a column.
+
+Moving a card rewrites the note it comes from: the list item moves under another
+heading, or the note's field changes. Each move is recorded in the vault's journal;
+reverse it with 'noted undo'. Enter opens the card's note.`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: paletteEntry{Title: "Kanban board", Prompt: "Board note"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		v, err := openCurrentVault()
+		if err != nil {
+			return err
+		}
+		switch {
+		case boardFolderFlag != "" && len(args) > 0:
+			return fmt.Errorf("give either a board note or --folder, not both")
+		case boardFolderFlag != "":
+			return folderBoard(cmd.OutOrStdout(), v, boardFolderFlag)
+		case len(args) == 0:
+			return fmt.Errorf("give a board note, or a folder with --folder")
+		}
+		rel, err := findNote(v, args[0])
+		if err != nil {
+			return err
+		}
+		return noteBoard(cmd.OutOrStdout(), v, rel)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(boardCmd)
+	boardCmd.Flags().StringVar(&boardFolderFlag, "folder", "", "Show the notes of this folder as cards")
+	boardCmd.Flags().StringVar(&boardFieldFlag, "field", "status", "Frontmatter field that puts folder notes in columns")
+	boardCmd.Flags().StringSliceVar(&boardColumnsFlag, "columns", nil, "Columns of a folder board, in order")
+}
+
+// readNote reads a note of v by its vault-relative path
+func readNote(v models.Vault, rel string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(v.Path, filepath.FromSlash(rel)))
+}
+
+// noteBoard shows the board written in the note rel
+func noteBoard(out io.Writer, v models.Vault, rel string) error {
+	data, err := readNote(v, rel)
+	if err != nil {
+		return err
+	}
+	columns := notes.ParseBoard(string(data))
+	if len(columns) == 0 {
+		return fmt.Errorf("%s has no headings to use as columns", rel)
+	}
+	result, err := tui.LaunchBoard(tui.BoardOptions{
+		Title:   rel,
+		Columns: noteBoardColumns(columns),
+		Move: func(from, card, to int) ([]tui.BoardColumn, error) {
+			data, err := readNote(v, rel)
+			if err != nil {
+				return nil, err
+			}
+			columns := notes.ParseBoard(string(data))
+			updated, err := notes.MoveCard(string(data), from, card, to)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := journal.Apply(v.Path, journal.Operation{
+				Kind:        journal.KindEdit,
+				Description: fmt.Sprintf("move card %q to %s in %s", columns[from].Cards[card].Text, columns[to].Title, rel),
+				Changes:     []journal.Change{{Path: rel, Before: journal.Contents(data), After: journal.Contents([]byte(updated))}},
+			}); err != nil {
+				return nil, err
+			}
+			return noteBoardColumns(notes.ParseBoard(updated)), nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error launching board: %w", err)
+	}
+	reportMoves(out, result)
+	if result.Open {
+		return editNote(out, v, rel)
+	}
+	return nil
+}
+
+func noteBoardColumns(columns []notes.BoardColumn) []tui.BoardColumn {
+	board := make([]tui.BoardColumn, len(columns))
+	for i, c := range columns {
+		board[i].Title = c.Title
+		for _, card := range c.Cards {
+			board[i].Cards = append(board[i].Cards, tui.BoardCard{Title: card.Text, Detail: fmt.Sprintf("line %d", card.Line), Done: card.Done})
+		}
+	}
+	return board
+}
+
+// folderCard is a note of a folder board
+type folderCard struct {
+	rel   string
+	title string
+}
+
+// folderBoard shows the notes of the folder dir as a board, in columns by their field
+func folderBoard(out io.Writer, v models.Vault, dir string) error {
+	dir = strings.Trim(filepath.ToSlash(dir), "/")
+	columns, cards, err := folderBoardColumns(v, dir)
+	if err != nil {
+		return err
+	}
+	if len(columns) == 0 {
+		return fmt.Errorf("no notes in folder %s", dir)
+	}
+	result, err := tui.LaunchBoard(tui.BoardOptions{
+		Title:   fmt.Sprintf("%s by %s", dir, boardFieldFlag),
+		Columns: folderTUIColumns(columns, cards),
+		Move: func(from, card, to int) ([]tui.BoardColumn, error) {
+			rel := cards[from][card].rel
+			data, err := readNote(v, rel)
+			if err != nil {
+				return nil, err
+			}
+			updated := notes.SetFrontmatterField(string(data), boardFieldFlag, columns[to])
+			if _, err := journal.Apply(v.Path, journal.Operation{
+				Kind:        journal.KindEdit,
+				Description: fmt.Sprintf("set %s of %s to %s", boardFieldFlag, rel, columns[to]),
+				Changes:     []journal.Change{{Path: rel, Before: journal.Contents(data), After: journal.Contents([]byte(updated))}},
+			}); err != nil {
+				return nil, err
+			}
+			moved := cards[from][card]
+			cards[from] = append(cards[from][:card:card], cards[from][card+1:]...)
+			cards[to] = append(cards[to], moved)
+			return folderTUIColumns(columns, cards), nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error launching board: %w", err)
+	}
+	reportMoves(out, result)
+	if result.Open {
+		return editNote(out, v, cards[result.Column][result.Card].rel)
+	}
+	return nil
+}
+
+// folderBoardColumns sorts the notes of dir into columns by the value of their board field.
+// Values that differ only in case share a column, titled as the first one seen.
+func folderBoardColumns(v models.Vault, dir string) ([]string, [][]folderCard, error) {
+	found := map[string][]folderCard{} // By lower-cased value
+	titles := map[string]string{}
+	var unset []folderCard
+	err := eachTextNote(v, func(e vault.Entry, content string) error {
+		if dir != "" && !strings.HasPrefix(e.Rel, dir+"/") {
+			return nil
+		}
+		fm, _ := notes.Frontmatter(content)
+		card := folderCard{rel: e.Rel, title: strings.TrimSuffix(path.Base(e.Rel), path.Ext(e.Rel))}
+		if title, ok := fm["title"].(string); ok && title != "" {
+			card.title = title
+		}
+		value, ok := fm[boardFieldFlag]
+		if !ok || value == nil || fmt.Sprint(value) == "" {
+			unset = append(unset, card)
+			return nil
+		}
+		status := fmt.Sprint(value)
+		key := strings.ToLower(status)
+		if _, ok := titles[key]; !ok {
+			titles[key] = status
+		}
+		found[key] = append(found[key], card)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	var columns []string
+	seen := map[string]bool{}
+	for _, c := range boardColumnsFlag {
+		if key := strings.ToLower(c); c != "" && !seen[key] {
+			seen[key] = true
+			columns = append(columns, c)
+		}
+	}
+	// The values found that are not among the columns given follow them
+	var extra []string
+	for key := range found {
+		if !seen[key] {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+	for _, key := range extra {
+		columns = append(columns, titles[key])
+	}
+	if len(columns) == 0 && len(unset) > 0 {
+		columns = []string{"todo"}
+	}
+	cards := make([][]folderCard, len(columns))
+	for i, c := range columns {
+		cards[i] = found[strings.ToLower(c)]
+	}
+	if len(columns) > 0 {
+		cards[0] = append(unset, cards[0]...)
+	}
+	for _, list := range cards {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].rel < list[j].rel })
+	}
+	return columns, cards, nil
+}
+
+func folderTUIColumns(columns []string, cards [][]folderCard) []tui.BoardColumn {
+	board := make([]tui.BoardColumn, len(columns))
+	for i, c := range columns {
+		board[i].Title = c
+		for _, card := range cards[i] {
+			board[i].Cards = append(board[i].Cards, tui.BoardCard{Title: card.title, Detail: card.rel})
+		}
+	}
+	return board
+}
+
+func reportMoves(out io.Writer, result tui.BoardResult) {
+	if result.Moved > 0 {
+		fmt.Fprintf(out, "✓ Moved %d %s\n", result.Moved, plural(result.Moved, "card", "cards"))
+	}
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/vault"
+)
+
+func TestFolderBoardColumns(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"sprint/a.md":  "---\nstatus: Todo\n---\n",
+		"sprint/b.md":  "---\nstatus: todo\n---\n",
+		"sprint/c.md":  "---\nstatus: DONE\ntitle: Shipped\n---\n",
+		"sprint/d.md":  "No frontmatter\n",
+		"sprint/e.md":  "---\nstatus: blocked\n---\n",
+		"elsewhere.md": "---\nstatus: todo\n---\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v := models.Vault{Name: "test", Path: dir, Config: vault.DefaultConfig(dir, "test")}
+	defer func(field string, columns []string) { boardFieldFlag, boardColumnsFlag = field, columns }(boardFieldFlag, boardColumnsFlag)
+	boardFieldFlag = "status"
+
+	tests := []struct {
+		name        string
+		flag        []string
+		wantColumns []string
+		wantCards   [][]string
+	}{
+		{
+			name:        "values found",
+			wantColumns: []string{"blocked", "DONE", "Todo"},
+			wantCards:   [][]string{{"sprint/d.md", "sprint/e.md"}, {"sprint/c.md"}, {"sprint/a.md", "sprint/b.md"}},
+		},
+		{
+			name:        "columns given",
+			flag:        []string{"todo", "Doing", "done", "TODO"},
+			wantColumns: []string{"todo", "Doing", "done", "blocked"},
+			wantCards:   [][]string{{"sprint/a.md", "sprint/b.md", "sprint/d.md"}, nil, {"sprint/c.md"}, {"sprint/e.md"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boardColumnsFlag = tt.flag
+			columns, cards, err := folderBoardColumns(v, "sprint")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(columns, tt.wantColumns) {
+				t.Errorf("columns = %q, want %q", columns, tt.wantColumns)
+			}
+			var got [][]string
+			for _, list := range cards {
+				var rels []string
+				for _, c := range list {
+					rels = append(rels, c.rel)
+				}
+				got = append(got, rels)
+			}
+			if !reflect.DeepEqual(got, tt.wantCards) {
+				t.Errorf("cards = %q, want %q", got, tt.wantCards)
+			}
+		})
+	}
+}
@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"cobra-cli/internal/config"
+	"cobra-cli/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var configVaultFlag bool
+var configTypeFlag string
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and edit noted settings",
+	Long: `Inspect and edit the global config (config.yaml) or, with --vault, the
+current vault's vault.json.
+
+  noted config list                       # Show every setting
+  noted config get <key>                  # Show one setting
+  noted config set <key> <value>          # Change a setting
+  noted config unset <key>                # Remove a setting
+  noted config edit                       # Open the file in your editor
+  noted config explain <key>              # Show which layer set a setting
+
+Keys are dotted, e.g. settings.daily.folder. Values are checked against the
+known schema: lists are written as "a,b" or "[a, b]", bools as true/false and
+durations as 90m, 12h or 30d. Use --type to force the type of free-form keys.
+
+Layered settings (editor, date_format, log_level) resolve in this order, later
+layers winning: built-in defaults, config.yaml, the vault's vault.json
+"settings", NOTED_* environment variables, then command-line flags.`,
+}
+
+var configListCmd = &cobra.Command{
+	Use:         "list",
+	Short:       "List all settings",
+	Args:        cobra.NoArgs,
+	Annotations: paletteEntry{Title: "Show settings"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listConfig(cmd.OutOrStdout())
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Show the value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return getConfig(cmd.OutOrStdout(), args[0])
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change the value of a setting",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setConfig(cmd.OutOrStdout(), args[0], args[1])
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return unsetConfig(cmd.OutOrStdout(), args[0])
+	},
+}
+
+var configEditCmd = &cobra.Command{
+	Use:         "edit",
+	Short:       "Open the config file in your editor",
+	Long:        `Open config.yaml (or vault.json with --vault) in the configured editor. The edited file is validated before it replaces the original.`,
+	Args:        cobra.NoArgs,
+	Annotations: paletteEntry{Title: "Edit settings"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editConfig(cmd.OutOrStdout())
+	},
+}
+
+var configExplainCmd = &cobra.Command{
+	Use:   "explain <key>",
+	Short: "Show which layer set the effective value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return explainConfig(cmd.OutOrStdout(), args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configListCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configExplainCmd)
+
+	configCmd.PersistentFlags().BoolVar(&configVaultFlag, "vault", false, "Operate on the current vault's vault.json")
+	configSetCmd.Flags().StringVar(&configTypeFlag, "type", "", "Force the value type of a free-form key (string, bool, int, duration, list, map or any)")
+}
+
+// configTarget is the file a config subcommand reads and writes.
+type configTarget struct {
+	keys   []config.Key
+	path   string
+	read   func() (map[string]any, error)
+	update func(func(map[string]any) error) error
+}
+
+func currentConfigTarget() (configTarget, error) {
+	if !configVaultFlag {
+		return configTarget{
+			keys:   config.GlobalKeys,
+			path:   notedApp.ConfigFile,
+			read:   func() (map[string]any, error) { return config.ReadGlobalDoc(notedApp.ConfigFile) },
+			update: func(fn func(map[string]any) error) error { return config.UpdateGlobalDoc(notedApp.ConfigFile, fn) },
+		}, nil
+	}
+	vaultPath := notedApp.CurrentVault()
+	if vaultPath == "" {
+		return configTarget{}, fmt.Errorf("no current vault set. Run 'noted vault' to select one")
+	}
+	if _, err := os.Stat(config.VaultConfigPath(vaultPath)); err != nil {
+		return configTarget{}, fmt.Errorf("vault %s has no %s", getVaultName(vaultPath), config.VaultConfigFile)
+	}
+	return configTarget{
+		keys:   config.VaultKeys,
+		path:   config.VaultConfigPath(vaultPath),
+		read:   func() (map[string]any, error) { return config.ReadVaultDoc(vaultPath) },
+		update: func(fn func(map[string]any) error) error { return config.UpdateVaultDoc(vaultPath, fn) },
+	}, nil
+}
+
+func listConfig(out io.Writer) error {
+	target, err := currentConfigTarget()
+	if err != nil {
+		return err
+	}
+	doc, err := target.read()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", target.path, err)
+	}
+	keys, flat := config.Flatten(doc)
+	fmt.Fprintf(out, "# %s\n", target.path)
+	for _, k := range keys {
+		fmt.Fprintf(out, "%s = %s\n", k, config.FormatValue(flat[k]))
+	}
+	return nil
+}
+
+func getConfig(out io.Writer, name string) error {
+	target, err := currentConfigTarget()
+	if err != nil {
+		return err
+	}
+	if _, err := config.LookupKey(target.keys, name); err != nil {
+		return err
+	}
+	doc, err := target.read()
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", target.path, err)
+	}
+	value, ok := config.GetPath(doc, name)
+	if !ok {
+		fmt.Fprintf(out, "%s is not set\n", name)
+		return nil
+	}
+	fmt.Fprintln(out, config.FormatValue(value))
+	return nil
+}
+
+func setConfig(out io.Writer, name, raw string) error {
+	target, err := currentConfigTarget()
+	if err != nil {
+		return err
+	}
+	k, err := config.LookupKey(target.keys, name)
+	if err != nil {
+		return err
+	}
+	if k.ReadOnly {
+		return fmt.Errorf("%s is managed by noted and cannot be set by hand", name)
+	}
+	typ := k.Type
+	if configTypeFlag != "" {
+		forced, err := config.ParseValueType(configTypeFlag)
+		if err != nil {
+			return err
+		}
+		if k.Type != config.TypeAny && forced != k.Type {
+			return fmt.Errorf("%s is a %s; --type %s does not apply", name, k.Type, forced)
+		}
+		typ = forced
+	}
+	value, err := config.ParseValue(typ, raw)
+	if err != nil {
+		return fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+	if !configVaultFlag && name == "current_vault" {
+		if value, err = registeredVaultPath(raw); err != nil {
+			return err
+		}
+	}
+	err = target.update(func(doc map[string]any) error {
+		config.SetPath(doc, name, value)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", target.path, err)
+	}
+	fmt.Fprintf(out, "✓ %s = %s\n", name, config.FormatValue(value))
+	return nil
+}
+
+// registeredVaultPath returns the path of the registered vault given by name or path, the
+// only values current_vault may hold
+func registeredVaultPath(input string) (string, error) {
+	vaults := notedApp.Vaults()
+	if v, ok := models.FindVault(vaults, input); ok {
+		return v.Path, nil
+	}
+	if expanded, err := expandPath(input); err == nil {
+		abs, _ := filepath.Abs(expanded)
+		for _, v := range vaults {
+			if v.Path == expanded || v.Path == abs {
+				return v.Path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("%s is not a registered vault. Run 'noted vault create %s' to add it", input, input)
+}
+
+func unsetConfig(out io.Writer, name string) error {
+	target, err := currentConfigTarget()
+	if err != nil {
+		return err
+	}
+	k, err := config.LookupKey(target.keys, name)
+	if err != nil {
+		return err
+	}
+	if k.ReadOnly {
+		return fmt.Errorf("%s is managed by noted and cannot be unset", name)
+	}
+	found := false
+	err = target.update(func(doc map[string]any) error {
+		found = config.UnsetPath(doc, name)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", target.path, err)
+	}
+	if !found {
+		fmt.Fprintf(out, "%s is not set\n", name)
+		return nil
+	}
+	fmt.Fprintf(out, "✓ Unset %s\n", name)
+	return nil
+}
+
+func explainConfig(out io.Writer, name string) error {
+	if _, err := notedApp.VaultSettings(); err != nil {
+		return err
+	}
+	res, err := notedApp.Settings().Explain(name)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "%s = %s (from %s)\n\n", name, config.FormatValue(res.Value), res.From)
+	for _, l := range res.Layers {
+		value := "(not set)"
+		if l.Set {
+			value = config.FormatValue(l.Value)
+		}
+		marker := ""
+		if l.Set && l.Layer == res.From {
+			marker = "  ← effective"
+		}
+		fmt.Fprintf(out, "  %-8s %-20s %s%s\n", l.Layer, l.Source, value, marker)
+	}
+	return nil
+}
+
+// editConfig edits a copy of the config file and only replaces the original once the copy parses.
+func editConfig(out io.Writer) error {
+	target, err := currentConfigTarget()
+	if err != nil {
+		return err
+	}
+	original, err := os.ReadFile(target.path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", target.path, err)
+	}
+	tmp, err := os.CreateTemp("", "noted-*"+filepath.Ext(target.path))
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	_, err = tmp.Write(original)
+	tmp.Close()
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+
+	if err := runEditor(notedApp.Settings().GetString("editor"), tmp.Name()); err != nil {
+		return fmt.Errorf("editor failed: %w", err)
+	}
+	edited, err := os.ReadFile(tmp.Name())
+	if err != nil {
+		return fmt.Errorf("failed to read edited file: %w", err)
+	}
+	if string(edited) == string(original) {
+		fmt.Fprintln(out, "No changes.")
+		return nil
+	}
+	if err := validateConfigFile(tmp.Name()); err != nil {
+		return fmt.Errorf("edited file is invalid, %s left unchanged: %w", target.path, err)
+	}
+	if err := config.ReplaceFile(target.path, original, edited); err != nil {
+		return fmt.Errorf("failed to update %s: %w", target.path, err)
+	}
+	fmt.Fprintf(out, "✓ Updated %s\n", target.path)
+	return nil
+}
+
+// validateConfigFile checks that an edited copy still parses as the target kind of config.
+func validateConfigFile(path string) error {
+	if configVaultFlag {
+		return config.ValidateVaultFile(path)
+	}
+	return config.ValidateGlobalFile(path)
+}
+
+// runEditor runs editor on path, attached to the terminal. The editor may carry arguments, e.g. "code --wait".
+func runEditor(editor, path string) error {
+	fields := strings.Fields(editor)
+	if len(fields) == 0 {
+		return fmt.Errorf("no editor configured")
+	}
+	c := exec.Command(fields[0], append(fields[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	return c.Run()
+}
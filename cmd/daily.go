@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var dailyNoEditFlag bool
+
+// dailyCmd represents the daily command
+var dailyCmd = &cobra.Command{
+	Use:   "daily",
+	Short: "Open today's daily note, creating it if needed",
+	Long: `Open today's daily note in your editor, creating it first if it does not exist.
+
+Daily notes are named after the date, formatted with the date_format setting, and
+live in the daily_folder setting's folder. A new daily note is filled in from the
+daily_template setting's template, relative to the vault root, if one is set:
+
+  noted config set --vault settings.daily_folder journal
+  noted config set --vault settings.daily_template templates/daily.md`,
+	Args:        cobra.NoArgs,
+	Annotations: paletteEntry{Title: "Daily note"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return openDailyNote(cmd.OutOrStdout(), time.Now(), !dailyNoEditFlag)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dailyCmd)
+	dailyCmd.Flags().BoolVar(&dailyNoEditFlag, "no-edit", false, "Create the note without opening the editor")
+}
+
+func openDailyNote(out io.Writer, day time.Time, edit bool) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	settings := notedApp.Settings()
+	title := day.Format(settings.GetString("date_format"))
+	rel, err := vaultRel(path.Join(filepath.ToSlash(settings.GetString("daily_folder")), title+".md"))
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(v.Path, filepath.FromSlash(rel))); errors.Is(err, os.ErrNotExist) {
+		content := ""
+		template := settings.GetString("daily_template")
+		if template != "" {
+			data, err := os.ReadFile(filepath.Join(v.Path, filepath.FromSlash(template)))
+			if err != nil {
+				return fmt.Errorf("daily template: %w", err)
+			}
+			content = fillTemplate(string(data), title)
+		}
+		if err := createNote(v, rel, content, template); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "✓ Created %s\n", rel)
+	} else if err != nil {
+		return err
+	}
+	if !edit {
+		return nil
+	}
+	return editNote(out, v, rel)
+}
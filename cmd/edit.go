@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/history"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/notes"
+	tui "cobra-cli/internal/tui"
+	"cobra-cli/internal/vault"
+)
+
+// editCmd represents the edit command
+var editCmd = &cobra.Command{
+	Use:   "edit <note|->",
+	Short: "Edit a note without leaving noted",
+	Long: `Edit a note of the current vault in noted's own editor, for quick changes without
+starting your editor.
+
+  noted edit ideas    # Edit ideas.md, found by name or path
+  noted edit -        # Edit the last note you worked on
+
+Typing [[ offers the vault's notes to link to and #, its tags. ctrl+s saves and esc
+closes the editor, asking first if there are unsaved changes. If the note changed on
+disk while it was open, saving asks before overwriting it. See 'noted keys' for the
+key bindings.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: paletteEntry{Title: "Edit note here", Prompt: "Note name or path"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !isTerminal(cmd.OutOrStdout()) {
+			return fmt.Errorf("the editor needs a terminal")
+		}
+		v, err := openCurrentVault()
+		if err != nil {
+			return err
+		}
+		rel, err := findNote(v, args[0])
+		if err != nil {
+			return err
+		}
+		return editNoteInline(v, rel)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editCmd)
+}
+
+// editNoteInline edits a note in the built-in editor and records the open, and the edit
+// if the note was saved with changes
+func editNoteInline(v models.Vault, rel string) error {
+	if !vault.IsText(rel) {
+		return fmt.Errorf("%s is not a text note", rel)
+	}
+	file := filepath.Join(v.Path, filepath.FromSlash(rel))
+	before, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	counts, err := tagCounts(v)
+	if err != nil {
+		return err
+	}
+	links, err := linkTargets(v)
+	if err != nil {
+		return err
+	}
+	recordHistory(v, history.Entry{Action: history.ActionOpen, Note: rel})
+
+	// last is the note as the editor last saw it on disk
+	last := before
+	result, err := tui.LaunchEditor(tui.EditorOptions{
+		Title:   rel,
+		Content: string(before),
+		Links:   links,
+		Tags:    sortedTags(counts),
+		Save: func(content string, force bool) error {
+			current, err := os.ReadFile(file)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if !force && !bytes.Equal(current, last) {
+				return tui.ErrChangedOnDisk
+			}
+			info, err := os.Stat(file)
+			mode := os.FileMode(0644)
+			if err == nil {
+				mode = info.Mode().Perm()
+			}
+			if err := os.WriteFile(file, []byte(content), mode); err != nil {
+				return err
+			}
+			last = []byte(content)
+			return nil
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error launching editor: %w", err)
+	}
+	if !result.Saved || result.Content == string(before) {
+		return nil
+	}
+	words := notes.CountWords(result.Content) - notes.CountWords(string(before))
+	recordHistory(v, history.Entry{Action: history.ActionEdit, Note: rel, Words: words})
+	return nil
+}
+
+// linkTargets returns how to link to each note of v: by name when it is unique in the
+// vault, by path otherwise, without the .md extension
+func linkTargets(v models.Vault) ([]string, error) {
+	entries, err := vault.Notes(v.Path, v.Config)
+	if err != nil {
+		return nil, err
+	}
+	names := map[string]int{}
+	for _, e := range entries {
+		names[strings.ToLower(linkName(path.Base(e.Rel)))]++
+	}
+	targets := make([]string, len(entries))
+	for i, e := range entries {
+		targets[i] = linkName(e.Rel)
+		if names[strings.ToLower(linkName(path.Base(e.Rel)))] == 1 {
+			targets[i] = linkName(path.Base(e.Rel))
+		}
+	}
+	return targets, nil
+}
+
+// linkName drops the .md extension, which links leave out
+func linkName(rel string) string {
+	return strings.TrimSuffix(rel, ".md")
+}
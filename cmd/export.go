@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"cobra-cli/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export <dest>",
+	Short: "Copy the notes of a vault to another directory",
+	Long: `Copy every note of the current vault into <dest>, keeping the folder layout.
+
+With --group, each vault of the group is exported into <dest>/<vault name>.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return exportVaults(cmd.OutOrStdout(), args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	addGroupFlag(exportCmd)
+}
+
+func exportVaults(out io.Writer, dest string) error {
+	dest, err := expandPath(dest)
+	if err != nil {
+		return fmt.Errorf("error expanding path: %w", err)
+	}
+	vaults, err := targetVaults()
+	if err != nil {
+		return err
+	}
+	// Registries from before vault names had to be unique may still hold clashing names
+	targets := map[string]string{}
+	for _, v := range vaults {
+		if other, ok := targets[strings.ToLower(v.Name)]; ok && other != v.Path && groupFlag != "" {
+			return fmt.Errorf("vaults %s and %s would both be exported to %s", other, v.Path, filepath.Join(dest, v.Name))
+		}
+		targets[strings.ToLower(v.Name)] = v.Path
+	}
+	for _, v := range vaults {
+		target := dest
+		if groupFlag != "" {
+			target = filepath.Join(dest, v.Name)
+		}
+		n, err := vault.Export(v, target)
+		if err != nil {
+			return fmt.Errorf("export %s: %w", v.Name, err)
+		}
+		fmt.Fprintf(out, "✓ [%s] exported %d notes to %s\n", v.Name, n, target)
+	}
+	return nil
+}
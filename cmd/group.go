@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"cobra-cli/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var groupFlag string
+var allVaultsFlag bool
+
+var vaultGroupCmd = &cobra.Command{
+	Use:   "group",
+	Short: "Manage named groups of vaults",
+	Long: `Group vaults (e.g. work, personal, research) to act on several at once.
+
+  noted vault group list                      # Show all groups and their vaults
+  noted vault group add <group> <vault>...    # Add vaults to a group, creating it if needed
+  noted vault group remove <group> <vault>... # Remove vaults from a group
+  noted vault group delete <group>            # Delete a group (the vaults are kept)
+
+Commands that accept --group (vault list, search, export) then run across
+every vault in the group and label their results with the vault name.`,
+}
+
+var vaultGroupListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List vault groups",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listGroups(cmd.OutOrStdout())
+	},
+}
+
+var vaultGroupAddCmd = &cobra.Command{
+	Use:   "add <group> <vault>...",
+	Short: "Add vaults to a group",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := notedApp.AddToGroup(args[0], args[1:]...); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "✓ Added %s to group %s\n", strings.Join(args[1:], ", "), args[0])
+		return nil
+	},
+}
+
+var vaultGroupRemoveCmd = &cobra.Command{
+	Use:   "remove <group> <vault>...",
+	Short: "Remove vaults from a group",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := notedApp.RemoveFromGroup(args[0], args[1:]...); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "✓ Removed %s from group %s\n", strings.Join(args[1:], ", "), args[0])
+		return nil
+	},
+}
+
+var vaultGroupDeleteCmd = &cobra.Command{
+	Use:   "delete <group>",
+	Short: "Delete a vault group",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := notedApp.DeleteGroup(args[0]); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "✓ Deleted group %s\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	vaultCmd.AddCommand(vaultGroupCmd)
+	vaultGroupCmd.AddCommand(vaultGroupListCmd)
+	vaultGroupCmd.AddCommand(vaultGroupAddCmd)
+	vaultGroupCmd.AddCommand(vaultGroupRemoveCmd)
+	vaultGroupCmd.AddCommand(vaultGroupDeleteCmd)
+}
+
+// addGroupFlag registers the shared --group flag on a command that can run across a vault group
+func addGroupFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&groupFlag, "group", "g", "", "Run across every vault in the named group")
+}
+
+func listGroups(out io.Writer) error {
+	groups := notedApp.Groups()
+	if len(groups) == 0 {
+		fmt.Fprintln(out, "No vault groups configured. Run 'noted vault group add <group> <vault>' to create one.")
+		return nil
+	}
+	fmt.Fprintln(out, "Vault groups:")
+	for _, name := range notedApp.GroupNames() {
+		members := groups[name]
+		if len(members) == 0 {
+			fmt.Fprintf(out, "  %s (empty)\n", name)
+			continue
+		}
+		fmt.Fprintf(out, "  %s: %s\n", name, strings.Join(members, ", "))
+	}
+	return nil
+}
+
+// addAllVaultsFlag registers the shared --all-vaults flag on a command that can run across every vault
+func addAllVaultsFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&allVaultsFlag, "all-vaults", false, "Run across every registered vault")
+}
+
+// targetVaults returns the vaults a command should act on: every vault with --all-vaults,
+// the members of --group, or just the current vault. Each vault comes with its config loaded.
+func targetVaults() ([]models.Vault, error) {
+	var vaults []models.Vault
+	if allVaultsFlag {
+		vaults = notedApp.Vaults()
+		if len(vaults) == 0 {
+			return nil, fmt.Errorf("no vaults configured. Run 'noted vault' to create one")
+		}
+	} else if groupFlag != "" {
+		members, err := notedApp.GroupVaults(groupFlag)
+		if err != nil {
+			return nil, err
+		}
+		vaults = members
+	} else {
+		current, err := currentVault()
+		if err != nil {
+			return nil, err
+		}
+		vaults = []models.Vault{current}
+	}
+	return openVaults(vaults)
+}
+
+// openVaults loads the config of every vault.
+func openVaults(vaults []models.Vault) ([]models.Vault, error) {
+	opened := make([]models.Vault, len(vaults))
+	for i, v := range vaults {
+		o, err := notedApp.OpenVault(v)
+		if err != nil {
+			return nil, err
+		}
+		opened[i] = o
+	}
+	return opened, nil
+}
+
+// currentVault returns the registered vault at the current vault path.
+func currentVault() (models.Vault, error) {
+	path := notedApp.CurrentVault()
+	if path == "" {
+		return models.Vault{}, fmt.Errorf("no current vault set. Run 'noted vault' to select one")
+	}
+	for _, v := range notedApp.Vaults() {
+		if v.Path == path {
+			return v, nil
+		}
+	}
+	return models.Vault{Name: getVaultName(path), Path: path}, nil
+}
@@ -9,9 +9,10 @@ var helpCmd = &cobra.Command{
 	Use:   "help",
 	Short: "Show a beautiful TUI help screen",
 	Long:  `Displays a styled, interactive help screen using Bubble Tea and Lip Gloss.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// TODO: Launch Bubble Tea TUI for help
 		// This will use the model and view logic for a scrollable, styled help screen
+		return nil
 	},
 }
 
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	tui "cobra-cli/internal/tui"
+)
+
+// keysCmd represents the keys command
+var keysCmd = &cobra.Command{
+	Use:   "keys [keymap]",
+	Short: "Show the key bindings of the TUI",
+	Long: `Show the keys bound to each TUI action in the active keymap, or in the named
+built-in keymap.
+
+The keymap is chosen with the layered keymap setting: default, vim or emacs, e.g.
+'noted config set keymap vim', NOTED_KEYMAP or --keymap. Individual actions can be
+rebound in config.yaml under keys, which replaces the keymap's keys for that action:
+
+  noted config set keys.quit "[q, ctrl+q]"
+
+Screens that take typed text, like the note filter, ignore single-character keys
+so they can still be typed; their help bars only list the keys that work there.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		km := tui.ActiveKeyMap()
+		if len(args) == 1 {
+			var err error
+			if km, err = tui.LoadKeyMap(args[0], nil); err != nil {
+				return err
+			}
+		}
+		printKeyMap(cmd.OutOrStdout(), km)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(keysCmd)
+}
+
+func printKeyMap(out io.Writer, km tui.KeyMap) {
+	fmt.Fprintf(out, "Keymap: %s\n\n", km.Name)
+	for _, b := range km.Bindings() {
+		keys := strings.Join(b.Keys, ", ")
+		if keys == "" {
+			keys = "(unbound)"
+		}
+		fmt.Fprintf(out, "  %-14s %-24s %s\n", b.Action, keys, b.Description)
+	}
+}
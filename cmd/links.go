@@ -0,0 +1,169 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/vault"
+	"cobra-cli/internal/vaultlog"
+	"github.com/spf13/cobra"
+)
+
+// linksCmd represents the links command
+var linksCmd = &cobra.Command{
+	Use:   "links [note]",
+	Short: "Show the links and backlinks of a note",
+	Long: `Show the outgoing links and the backlinks of a note in the current vault.
+
+Notes can link into other registered vaults with [[vault:<vault name>/<note>]],
+e.g. [[vault:Research/Papers/Attention]]. Such links are resolved through the
+vault registry and show up in the reports of both vaults.
+
+Without a note, list every cross-vault link leaving or entering the current vault.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return showCrossVaultLinks(cmd.OutOrStdout())
+		}
+		return showNoteLinks(cmd.OutOrStdout(), args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(linksCmd)
+}
+
+// linkRegistry opens every registered vault, plus the current one if it is unregistered.
+func linkRegistry() (*vault.Registry, models.Vault, error) {
+	current, err := currentVault()
+	if err != nil {
+		return nil, current, err
+	}
+	vaults := notedApp.Vaults()
+	found := false
+	for _, v := range vaults {
+		if v.Path == current.Path {
+			found = true
+		}
+	}
+	if !found {
+		vaults = append(vaults, current)
+	}
+	opened, err := openVaults(vaults)
+	if err != nil {
+		return nil, current, err
+	}
+	for _, v := range opened {
+		if v.Path == current.Path {
+			current = v
+		}
+	}
+	registry := vault.NewRegistry(opened)
+	registry.OnIndex = func(v models.Vault, notes int, took time.Duration) {
+		logVaultEvent(v, vaultlog.EventIndex, "link index rebuilt", "notes", notes, "took", took.Round(time.Millisecond).String())
+	}
+	return registry, current, nil
+}
+
+func showNoteLinks(out io.Writer, note string) error {
+	registry, current, err := linkRegistry()
+	if err != nil {
+		return err
+	}
+	ix, err := registry.Index(current)
+	if err != nil {
+		return err
+	}
+	rel, ok := ix.Resolve(note)
+	if !ok {
+		return fmt.Errorf("note %q not found in vault %s", note, current.Name)
+	}
+
+	outgoing, err := registry.NoteLinks(current, vault.Entry{Path: filepath.Join(current.Path, filepath.FromSlash(rel)), Rel: rel})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Links from %s:\n", rel)
+	if len(outgoing) == 0 {
+		fmt.Fprintln(out, "  (none)")
+	}
+	for _, ref := range outgoing {
+		fmt.Fprintf(out, "  %s\n", formatTarget(ref, current))
+	}
+
+	backlinks, err := registry.Backlinks(current, rel)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nBacklinks to %s:\n", rel)
+	if len(backlinks) == 0 {
+		fmt.Fprintln(out, "  (none)")
+	}
+	for _, ref := range backlinks {
+		fmt.Fprintf(out, "  %s\n", formatSource(ref, current))
+	}
+	return nil
+}
+
+func showCrossVaultLinks(out io.Writer) error {
+	registry, current, err := linkRegistry()
+	if err != nil {
+		return err
+	}
+	var outgoing, incoming []vault.Ref
+	for _, v := range registry.Vaults() {
+		refs, err := registry.Links(v)
+		if err != nil {
+			return err
+		}
+		for _, ref := range refs {
+			if !ref.Link.CrossVault() {
+				continue
+			}
+			if v.Path == current.Path {
+				outgoing = append(outgoing, ref)
+			} else if ref.ToVault == current.Name {
+				incoming = append(incoming, ref)
+			}
+		}
+	}
+	fmt.Fprintf(out, "Cross-vault links from %s:\n", current.Name)
+	if len(outgoing) == 0 {
+		fmt.Fprintln(out, "  (none)")
+	}
+	for _, ref := range outgoing {
+		fmt.Fprintf(out, "  %s → %s\n", ref.FromRel, formatTarget(ref, current))
+	}
+	fmt.Fprintf(out, "\nCross-vault links into %s:\n", current.Name)
+	if len(incoming) == 0 {
+		fmt.Fprintln(out, "  (none)")
+	}
+	for _, ref := range incoming {
+		fmt.Fprintf(out, "  %s → %s\n", formatSource(ref, current), ref.ToRel)
+	}
+	return nil
+}
+
+// formatTarget renders where a link points, labelling targets in other vaults
+func formatTarget(ref vault.Ref, current models.Vault) string {
+	label := ""
+	if ref.ToVault != current.Name {
+		label = "[" + ref.ToVault + "] "
+	}
+	if !ref.Resolved() {
+		return fmt.Sprintf("%s%s (unresolved, line %d)", label, ref.Link.Target, ref.Link.Line)
+	}
+	return fmt.Sprintf("%s%s (line %d)", label, ref.ToRel, ref.Link.Line)
+}
+
+// formatSource renders where a link comes from, labelling sources in other vaults
+func formatSource(ref vault.Ref, current models.Vault) string {
+	label := ""
+	if ref.FromVault != current.Name {
+		label = "[" + ref.FromVault + "] "
+	}
+	return fmt.Sprintf("%s%s:%d", label, ref.FromRel, ref.Link.Line)
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/config"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/vaultlog"
+)
+
+var (
+	logLinesFlag  int
+	logLevelFlag  string
+	logEventFlag  string
+	logSinceFlag  string
+	logFollowFlag bool
+	logJSONFlag   bool
+)
+
+// logCmd represents the log command
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the current vault's activity log",
+	Long: `Show the structured activity log of the current vault, kept at its log_path.
+
+Vault events such as creating and deleting vaults, renaming notes, using templates,
+rebuilding the link index and command errors are recorded as JSON lines. The log
+is rotated once it reaches log_max_size KiB, keeping log_keep older files.
+
+  noted log                      # Last 20 records
+  noted log -n 100 --level warn  # Last 100 warnings and errors
+  noted log --event create       # Only create events
+  noted log --since 2d           # Records from the last two days
+  noted log -f                   # Keep printing new records`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showLog(cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(logCmd)
+	logCmd.Flags().IntVarP(&logLinesFlag, "lines", "n", 20, "Number of records to show, 0 for all")
+	logCmd.Flags().StringVar(&logLevelFlag, "level", "debug", "Minimum level to show (debug, info, warn, error)")
+	logCmd.Flags().StringVar(&logEventFlag, "event", "", "Only show this event (create, delete, rename, template, index, remind, error)")
+	logCmd.Flags().StringVar(&logSinceFlag, "since", "", "Only show records newer than this, e.g. 2h or 7d")
+	logCmd.Flags().BoolVarP(&logFollowFlag, "follow", "f", false, "Keep printing records as they are written")
+	logCmd.Flags().BoolVar(&logJSONFlag, "json", false, "Print records as JSON lines")
+}
+
+func showLog(out io.Writer) error {
+	level, err := log.ParseLevel(logLevelFlag)
+	if err != nil {
+		return err
+	}
+	var since time.Time
+	if logSinceFlag != "" {
+		d, err := config.ParseDuration(logSinceFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+		since = time.Now().Add(-d)
+	}
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	logger := notedApp.VaultLog(v)
+	if logger.Path == "" {
+		return fmt.Errorf("vault %s has no log_path", v.Name)
+	}
+
+	match := func(r vaultlog.Record) bool {
+		return r.Matches(level, logEventFlag) && !r.Time.Before(since)
+	}
+	records, err := vaultlog.Read(logger.Path, logger.Keep)
+	if err != nil {
+		return err
+	}
+	var shown []vaultlog.Record
+	for _, r := range records {
+		if match(r) {
+			shown = append(shown, r)
+		}
+	}
+	if logLinesFlag > 0 && len(shown) > logLinesFlag {
+		shown = shown[len(shown)-logLinesFlag:]
+	}
+	for _, r := range shown {
+		printRecord(out, r)
+	}
+	if !logFollowFlag {
+		if len(shown) == 0 {
+			fmt.Fprintln(out, "No log records.")
+		}
+		return nil
+	}
+	return followLog(out, logger.Path, match)
+}
+
+// followLog polls the log for appended records until interrupted, starting over when it is rotated.
+func followLog(out io.Writer, path string, match func(vaultlog.Record) bool) error {
+	follower := vaultlog.NewFollower(path)
+	for {
+		time.Sleep(500 * time.Millisecond)
+		records, err := follower.Next()
+		if err != nil {
+			return err
+		}
+		for _, r := range records {
+			if match(r) {
+				printRecord(out, r)
+			}
+		}
+	}
+}
+
+func printRecord(out io.Writer, r vaultlog.Record) {
+	if logJSONFlag {
+		fields := map[string]any{"time": r.Time.Format(time.RFC3339), "level": r.Level.String(), "event": r.Event, "msg": r.Msg}
+		for k, v := range r.Fields {
+			fields[k] = v
+		}
+		b, _ := json.Marshal(fields)
+		fmt.Fprintln(out, string(b))
+		return
+	}
+	keys := make([]string, 0, len(r.Fields))
+	for k := range r.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s %-8s %s", r.Time.Local().Format("2006-01-02 15:04:05"), strings.ToUpper(r.Level.String()), r.Event, r.Msg)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, r.Fields[k])
+	}
+	fmt.Fprintln(out, b.String())
+}
+
+// logVaultEvent records an event in the log of v; failing to log never fails a command
+func logVaultEvent(v models.Vault, event, msg string, keyvals ...any) {
+	if err := notedApp.VaultLog(v).Info(event, msg, keyvals...); err != nil {
+		log.Warn("could not write vault log", "path", v.Config.LogPath, "err", err)
+	}
+}
+
+// logCommandError records a failed command in the current vault's log
+func logCommandError(cmd *cobra.Command, cmdErr error) {
+	if notedApp == nil || notedApp.CurrentVault() == "" {
+		return
+	}
+	v, err := openCurrentVault()
+	if err != nil {
+		return
+	}
+	if err := notedApp.VaultLog(v).Error(vaultlog.EventError, cmdErr, "command", cmd.CommandPath()); err != nil {
+		log.Warn("could not write vault log", "path", v.Config.LogPath, "err", err)
+	}
+}
+
+// openCurrentVault returns the current vault with its config loaded
+func openCurrentVault() (models.Vault, error) {
+	v, err := currentVault()
+	if err != nil {
+		return v, err
+	}
+	return notedApp.OpenVault(v)
+}
@@ -0,0 +1,221 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/journal"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/notes"
+	"cobra-cli/internal/vault"
+	"cobra-cli/internal/vaultlog"
+)
+
+// mvCmd represents the mv command
+var mvCmd = &cobra.Command{
+	Use:     "mv <note> <destination>",
+	Aliases: []string{"rename"},
+	Short:   "Rename or move a note and update the links to it",
+	Long: `Rename or move a note of the current vault. Wiki links to the note from other
+notes of the vault, and [[vault:...]] links to it from the other registered vaults,
+are rewritten to point at its new location, keeping their headings and aliases.
+
+  noted mv ideas brainstorm           # ideas.md -> brainstorm.md
+  noted mv ideas archive/             # ideas.md -> archive/ideas.md
+  noted mv projects/plan plans/2025   # projects/plan.md -> plans/2025.md
+
+The move is recorded in the vault's journal; reverse it with 'noted undo'. Links
+updated in another vault are recorded in that vault's journal.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return moveNote(cmd.OutOrStdout(), args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(mvCmd)
+}
+
+func moveNote(out io.Writer, name, dest string) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	ix, err := vault.BuildIndex(v)
+	if err != nil {
+		return err
+	}
+	src, ok := ix.Resolve(name)
+	if !ok {
+		return fmt.Errorf("note %q not found in vault %s", name, v.Name)
+	}
+
+	rel, err := vaultRel(dest)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stat(filepath.Join(v.Path, filepath.FromSlash(rel))); strings.HasSuffix(dest, "/") || (err == nil && info.IsDir()) {
+		rel = path.Join(rel, path.Base(src))
+	} else if !vault.IsNote(v.Config, rel) {
+		rel += path.Ext(src)
+	}
+	if rel == src {
+		return fmt.Errorf("%s is already at %s", name, rel)
+	}
+	if _, err := os.Stat(filepath.Join(v.Path, filepath.FromSlash(rel))); !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s already exists", rel)
+	}
+
+	changes, err := moveChanges(v, ix, src, rel)
+	if err != nil {
+		return err
+	}
+	op, err := journal.Apply(v.Path, journal.Operation{
+		Kind:        journal.KindRename,
+		Description: fmt.Sprintf("move %s to %s", src, rel),
+		Changes:     changes,
+	})
+	if err != nil {
+		return err
+	}
+	logVaultEvent(v, vaultlog.EventRename, "note renamed", "from", src, "to", rel, "links_updated", len(op.Changes)-2)
+	fmt.Fprintf(out, "✓ Moved %s to %s\n", src, rel)
+	if n := len(op.Changes) - 2; n > 0 {
+		fmt.Fprintf(out, "  Updated links in %d %s\n", n, plural(n, "note", "notes"))
+	}
+	moveCrossVaultLinks(out, v, ix, src, rel)
+	return nil
+}
+
+// moveChanges plans moving src to dest: the note itself, plus every note whose links to
+// src need rewriting. The moved note's own links to itself are rewritten too.
+func moveChanges(v models.Vault, ix *vault.Index, src, dest string) ([]journal.Change, error) {
+	rewrite := movedLinkRewriter(ix, src, dest, func(l notes.Link) bool {
+		return !l.CrossVault() || strings.EqualFold(l.Vault, v.Name)
+	})
+
+	var changes []journal.Change
+	for _, e := range ix.Notes() {
+		data, err := os.ReadFile(e.Path)
+		if err != nil {
+			return nil, err
+		}
+		content := string(data)
+		if strings.EqualFold(path.Ext(e.Rel), ".md") {
+			content, _ = notes.RewriteLinks(content, rewrite)
+		}
+		switch {
+		case e.Rel == src:
+			changes = append(changes,
+				journal.Change{Path: src, Before: journal.Contents(data)},
+				journal.Change{Path: dest, After: journal.Contents([]byte(content)), Mode: e.Info.Mode().Perm()})
+		case content != string(data):
+			changes = append(changes, journal.Change{Path: e.Rel, Before: journal.Contents(data), After: journal.Contents([]byte(content))})
+		}
+	}
+	return changes, nil
+}
+
+// movedLinkRewriter returns a RewriteLinks function pointing the links to src, resolved in
+// ix, at dest. ours tells whether a link is meant for the vault of ix at all.
+func movedLinkRewriter(ix *vault.Index, src, dest string, ours func(l notes.Link) bool) func(l notes.Link) (string, bool) {
+	target := linkTarget(ix, src, dest)
+	return func(l notes.Link) (string, bool) {
+		if !ours(l) {
+			return "", false
+		}
+		if rel, ok := ix.Resolve(l.Target); !ok || rel != src {
+			return "", false
+		}
+		if strings.Contains(l.Target, "/") {
+			return strings.TrimSuffix(dest, path.Ext(dest)), true
+		}
+		return target, true
+	}
+}
+
+// moveCrossVaultLinks rewrites the [[vault:...]] links from the other registered vaults to
+// src of v, now moved to dest. Each vault's notes are updated through its own journal. A
+// vault that cannot be updated is reported, with the notes left holding broken links.
+func moveCrossVaultLinks(out io.Writer, v models.Vault, ix *vault.Index, src, dest string) {
+	rewrite := movedLinkRewriter(ix, src, dest, func(l notes.Link) bool {
+		return strings.EqualFold(l.Vault, v.Name)
+	})
+	for _, other := range notedApp.Vaults() {
+		if other.Path == v.Path {
+			continue
+		}
+		var changes []journal.Change
+		other, err := notedApp.OpenVault(other)
+		if err == nil {
+			changes, err = linkChanges(other, rewrite)
+		}
+		if err == nil && len(changes) > 0 {
+			_, err = journal.Apply(other.Path, journal.Operation{
+				Kind:        journal.KindEdit,
+				Description: fmt.Sprintf("update links to %s/%s, moved to %s", v.Name, src, dest),
+				Changes:     changes,
+			})
+		}
+		if err != nil {
+			fmt.Fprintf(out, "  ✗ Could not update the links from vault %s: %v\n", other.Name, err)
+			for _, c := range changes {
+				fmt.Fprintf(out, "    %s still links to %s\n", c.Path, src)
+			}
+			continue
+		}
+		if n := len(changes); n > 0 {
+			logVaultEvent(other, vaultlog.EventRename, "links to a moved note updated", "vault", v.Name, "from", src, "to", dest, "links_updated", n)
+			fmt.Fprintf(out, "  Updated links in %d %s of vault %s\n", n, plural(n, "note", "notes"), other.Name)
+		}
+	}
+}
+
+// linkChanges plans rewriting the links of the markdown notes of v with rewrite.
+func linkChanges(v models.Vault, rewrite func(l notes.Link) (string, bool)) ([]journal.Change, error) {
+	entries, err := vault.Notes(v.Path, v.Config)
+	if err != nil {
+		return nil, err
+	}
+	var changes []journal.Change
+	for _, e := range entries {
+		if !strings.EqualFold(path.Ext(e.Rel), ".md") {
+			continue
+		}
+		data, err := os.ReadFile(e.Path)
+		if err != nil {
+			return nil, err
+		}
+		if content, changed := notes.RewriteLinks(string(data), rewrite); changed {
+			changes = append(changes, journal.Change{Path: e.Rel, Before: journal.Contents(data), After: journal.Contents([]byte(content))})
+		}
+	}
+	return changes, nil
+}
+
+// linkTarget is how short links should name dest once src moved there: by base name,
+// unless another note shares it and only the full path is unambiguous.
+func linkTarget(ix *vault.Index, src, dest string) string {
+	name := strings.TrimSuffix(path.Base(dest), path.Ext(dest))
+	for _, e := range ix.Notes() {
+		other := strings.TrimSuffix(path.Base(e.Rel), path.Ext(e.Rel))
+		if e.Rel != src && strings.EqualFold(other, name) {
+			return strings.TrimSuffix(dest, path.Ext(dest))
+		}
+	}
+	return name
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
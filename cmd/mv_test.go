@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cobra-cli/internal/journal"
+	"cobra-cli/internal/models"
+)
+
+func TestMoveNoteUpdatesCrossVaultLinks(t *testing.T) {
+	dir := testVault(t)
+	other := t.TempDir()
+	for _, v := range []models.Vault{{Name: "other", Path: other}, {Name: "test", Path: dir}} {
+		if err := notedApp.SelectVault(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := map[string]string{
+		filepath.Join(dir, "ideas.md"):   "# Ideas\n",
+		filepath.Join(dir, "index.md"):   "[[ideas]] and [[vault:test/ideas]]\n",
+		filepath.Join(other, "links.md"): "See [[vault:Test/ideas#Top|the ideas]] and [[vault:test/index]].\n",
+		filepath.Join(other, "ideas.md"): "Not the moved one\n",
+		filepath.Join(other, "local.md"): "[[ideas]]\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out bytes.Buffer
+	if err := moveNote(&out, "ideas", "brainstorm"); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		filepath.Join(dir, "index.md"):   "[[brainstorm]] and [[vault:test/brainstorm]]\n",
+		filepath.Join(other, "links.md"): "See [[vault:Test/brainstorm#Top|the ideas]] and [[vault:test/index]].\n",
+		filepath.Join(other, "local.md"): "[[ideas]]\n",
+	}
+	for path, content := range want {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", path, data, content)
+		}
+	}
+	if !strings.Contains(out.String(), "Updated links in 1 note of vault other") {
+		t.Errorf("output does not report the other vault:\n%s", out.String())
+	}
+
+	// The other vault's journal can undo its part of the move
+	if _, err := journal.Undo(other); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(other, "links.md")); string(data) != files[filepath.Join(other, "links.md")] {
+		t.Errorf("undo left links.md = %q", data)
+	}
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/config"
+	"cobra-cli/internal/history"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/notes"
+	"cobra-cli/internal/vault"
+	"cobra-cli/internal/vaultlog"
+)
+
+var (
+	newTemplateFlag string
+	newNoEditFlag   bool
+)
+
+// newCmd represents the new command
+var newCmd = &cobra.Command{
+	Use:   "new <title>",
+	Short: "Create a note, optionally from a template",
+	Long: `Create a note in the current vault and open it in your editor.
+
+  noted new ideas                        # Create ideas.md
+  noted new projects/launch -t project   # Create projects/launch.md from templates/project.md
+
+Templates live in the vault's templates_path. {{title}}, {{date}} and {{time}} are
+filled in, with dates formatted by the date_format setting.`,
+	Args:        cobra.ExactArgs(1),
+	Annotations: paletteEntry{Title: "New note", Prompt: "Title", Choices: "template", ChoiceTitle: "New note from template"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return newNote(cmd.OutOrStdout(), args[0], newTemplateFlag, !newNoEditFlag)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(newCmd)
+	newCmd.Flags().StringVarP(&newTemplateFlag, "template", "t", "", "Template to start the note from")
+	newCmd.Flags().BoolVar(&newNoEditFlag, "no-edit", false, "Create the note without opening the editor")
+	newCmd.RegisterFlagCompletionFunc("template", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		v, err := openCurrentVault()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		return templateNames(v), cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func newNote(out io.Writer, title, template string, edit bool) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	rel, err := vaultRel(title)
+	if err != nil {
+		return err
+	}
+	if !vault.IsNote(v.Config, rel) {
+		rel += ".md"
+	}
+
+	content := ""
+	if template != "" {
+		content, err = renderTemplate(v, template, strings.TrimSuffix(path.Base(rel), path.Ext(rel)))
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := createNote(v, rel, content, template); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "✓ Created %s\n", rel)
+	if !edit {
+		return nil
+	}
+	return editNote(out, v, rel)
+}
+
+// createNote writes a new note to v and records its creation; template names the
+// template it was filled from, if any
+func createNote(v models.Vault, rel, content, template string) error {
+	notePath := filepath.Join(v.Path, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(notePath), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(notePath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("note %s already exists. Run 'noted open %s' to edit it", rel, rel)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	recordHistory(v, history.Entry{Action: history.ActionCreate, Note: rel, Words: notes.CountWords(content)})
+	logVaultEvent(v, vaultlog.EventCreate, "note created", "note", rel)
+	if template != "" {
+		logVaultEvent(v, vaultlog.EventTemplate, "template used", "template", template, "note", rel)
+	}
+	return nil
+}
+
+// vaultRel cleans a user-given note path into a vault-relative one, outside of the
+// folders noted keeps for itself
+func vaultRel(name string) (string, error) {
+	rel := path.Clean(filepath.ToSlash(name))
+	if strings.HasPrefix(rel, "../") || rel == ".." || path.IsAbs(rel) || rel == "." {
+		return "", fmt.Errorf("note %q must be inside the vault", name)
+	}
+	first, _, _ := strings.Cut(rel, "/")
+	for _, dir := range []string{config.VaultDataDir, config.VaultTrashDir} {
+		if strings.EqualFold(first, dir) {
+			return "", fmt.Errorf("note %q cannot be in the %s folder, which noted manages", name, dir)
+		}
+	}
+	return rel, nil
+}
+
+// renderTemplate loads a template from the vault's templates folder and fills it in
+func renderTemplate(v models.Vault, name, title string) (string, error) {
+	dir := v.Config.TemplatesPath
+	candidates := []string{filepath.Join(dir, name)}
+	if filepath.Ext(name) == "" {
+		candidates = append(candidates, filepath.Join(dir, name+".md"))
+	}
+	for _, p := range candidates {
+		data, err := os.ReadFile(p)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return fillTemplate(string(data), title), nil
+	}
+	return "", fmt.Errorf("template %q not found in %s", name, dir)
+}
+
+// fillTemplate fills in the {{title}}, {{date}} and {{time}} of a template
+func fillTemplate(tmpl, title string) string {
+	now := time.Now()
+	return notes.RenderTemplate(tmpl, map[string]string{
+		"title": title,
+		"date":  now.Format(notedApp.Settings().GetString("date_format")),
+		"time":  now.Format("15:04"),
+	})
+}
+
+// templateNames lists the templates of v by the name 'noted new --template' takes
+func templateNames(v models.Vault) []string {
+	var names []string
+	filepath.WalkDir(v.Config.TemplatesPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(v.Config.TemplatesPath, p)
+		if err != nil {
+			return nil
+		}
+		names = append(names, strings.TrimSuffix(filepath.ToSlash(rel), ".md"))
+		return nil
+	})
+	return names
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import "testing"
+
+func TestVaultRel(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{name: "ideas.md", want: "ideas.md"},
+		{name: "projects/./plan.md", want: "projects/plan.md"},
+		{name: "notes/.trash/old.md", want: "notes/.trash/old.md"},
+		{name: ".notes.md", want: ".notes.md"},
+		{name: "../outside.md", wantErr: true},
+		{name: "/etc/passwd", wantErr: true},
+		{name: ".", wantErr: true},
+		{name: ".noted/journal.md", wantErr: true},
+		{name: ".trash/old.md", wantErr: true},
+		{name: ".Trash/old.md", wantErr: true},
+		{name: "projects/../.noted/x.md", wantErr: true},
+		{name: ".trash", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := vaultRel(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("vaultRel(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("vaultRel(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
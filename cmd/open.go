@@ -0,0 +1,209 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	log "github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/history"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/notes"
+	tui "cobra-cli/internal/tui"
+	"cobra-cli/internal/vault"
+)
+
+// openCmd represents the open command
+var openCmd = &cobra.Command{
+	Use:   "open [note|-]",
+	Short: "Open a note in your editor",
+	Long: `Open a note of the current vault in your editor.
+
+  noted open                 # Pick a note in the vault viewer
+  noted open ideas           # Open ideas.md, found by name or path
+  noted open projects/plan   # Open projects/plan.md
+  noted open -               # Open the last note you worked on
+
+In the vault viewer, ctrl+p (see 'noted keys') opens the command palette, which
+fuzzy-searches actions such as creating a note from a template, switching vaults
+and browsing tags. The mouse works as well: the wheel scrolls the list or the preview
+under it, and clicking a note highlights it and clicking it again opens it.
+
+The editor comes from the layered editor setting (see 'noted config explain editor').`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: paletteEntry{Title: "Open note", Prompt: "Note name or path"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		v, err := openCurrentVault()
+		if err != nil {
+			return err
+		}
+		if len(args) == 0 {
+			if !isTerminal(cmd.OutOrStdout()) {
+				return fmt.Errorf("no note given")
+			}
+			return browseVault(cmd.OutOrStdout(), v)
+		}
+		rel, err := findNote(v, args[0])
+		if err != nil {
+			return err
+		}
+		return editNote(cmd.OutOrStdout(), v, rel)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+}
+
+// findNote resolves a note name or path in v; "-" is the last note worked on that still exists.
+func findNote(v models.Vault, name string) (string, error) {
+	if name == "-" {
+		recent := recentNotes(v, 1)
+		if len(recent) == 0 {
+			return "", fmt.Errorf("no recent notes in vault %s", v.Name)
+		}
+		return recent[0].Note, nil
+	}
+	ix, err := vault.BuildIndex(v)
+	if err != nil {
+		return "", err
+	}
+	rel, ok := ix.Resolve(name)
+	if !ok {
+		return "", fmt.Errorf("note %q not found in vault %s. Run 'noted new %s' to create it", name, v.Name, name)
+	}
+	return rel, nil
+}
+
+// editNote opens a note in the editor and records the open, and the edit if the note changed.
+// Without a terminal, as in a pipe or a cron job, the editor is skipped.
+func editNote(out io.Writer, v models.Vault, rel string) error {
+	if !isTerminal(out) {
+		fmt.Fprintf(out, "Not on a terminal; not opening %s in the editor\n", rel)
+		return nil
+	}
+	path := filepath.Join(v.Path, filepath.FromSlash(rel))
+	before, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	recordHistory(v, history.Entry{Action: history.ActionOpen, Note: rel})
+	if err := runEditor(notedApp.Settings().GetString("editor"), path); err != nil {
+		return fmt.Errorf("failed to run editor: %w", err)
+	}
+	after, err := os.ReadFile(path)
+	if err != nil || bytes.Equal(before, after) {
+		return nil
+	}
+	words := notes.CountWords(string(after)) - notes.CountWords(string(before))
+	recordHistory(v, history.Entry{Action: history.ActionEdit, Note: rel, Words: words})
+	return nil
+}
+
+// browseVault shows the vault viewer and opens the note picked in it
+func browseVault(out io.Writer, v models.Vault) error {
+	entries, err := vault.Notes(v.Path, v.Config)
+	if err != nil {
+		return err
+	}
+	all := make([]tui.ViewerNote, len(entries))
+	for i, e := range entries {
+		all[i] = tui.ViewerNote{Rel: e.Rel}
+	}
+	var recent []tui.ViewerNote
+	for _, e := range recentNotes(v, 5) {
+		recent = append(recent, tui.ViewerNote{Rel: e.Note, Detail: describeEntry(e)})
+	}
+	actions := paletteActions(rootCmd)
+	result, err := tui.LaunchVaultViewer(tui.VaultViewerOptions{
+		Title:   v.Name,
+		Recent:  recent,
+		Notes:   all,
+		Actions: paletteChoices(actions),
+		Preview: notePreview(v),
+	})
+	if err != nil {
+		return fmt.Errorf("error launching vault viewer: %w", err)
+	}
+	if result.Action != "" {
+		return runPaletteAction(actions, result.Action, result.ActionArg)
+	}
+	if result.Cancelled || result.Note == "" {
+		return nil
+	}
+	return editNote(out, v, result.Note)
+}
+
+// notePreview loads the notes of v for the viewer's preview pane
+func notePreview(v models.Vault) func(rel string) (string, error) {
+	return func(rel string) (string, error) {
+		if !vault.IsText(rel) {
+			return "", fmt.Errorf("no preview for %s files", path.Ext(rel))
+		}
+		data, err := os.ReadFile(filepath.Join(v.Path, filepath.FromSlash(rel)))
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+}
+
+// recentNotes returns the latest notes worked on in v that still exist
+func recentNotes(v models.Vault, n int) []history.Entry {
+	entries, err := history.Read(v.Config.HistoryPath)
+	if err != nil {
+		return nil
+	}
+	var recent []history.Entry
+	for _, e := range history.RecentNotes(entries, 0) {
+		if _, err := os.Stat(filepath.Join(v.Path, filepath.FromSlash(e.Note))); err != nil {
+			continue
+		}
+		recent = append(recent, e)
+		if len(recent) == n {
+			break
+		}
+	}
+	return recent
+}
+
+// recordHistory appends to the history log of v; failing to record never fails a command
+func recordHistory(v models.Vault, e history.Entry) {
+	if v.Config.HistoryPath == "" {
+		return
+	}
+	if err := history.Append(v.Config.HistoryPath, e); err != nil {
+		log.Warn("could not write history", "path", v.Config.HistoryPath, "err", err)
+	}
+}
+
+// describeEntry renders what happened to a note and when, e.g. "edited 5m ago"
+func describeEntry(e history.Entry) string {
+	verb := map[string]string{
+		history.ActionOpen:   "opened",
+		history.ActionCreate: "created",
+		history.ActionEdit:   "edited",
+	}[e.Action]
+	return verb + " " + timeAgo(e.Time)
+}
+
+func timeAgo(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 7*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+	return t.Local().Format("2006-01-02")
+}
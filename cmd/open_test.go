@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cobra-cli/internal/history"
+)
+
+func TestFindRecentNoteSkipsMissing(t *testing.T) {
+	dir := testVault(t)
+	v, err := openCurrentVault()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := findNote(v, "-"); err == nil {
+		t.Error("findNote(-) found a note without any history")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "kept.md"), []byte("kept"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []history.Entry{
+		{Action: history.ActionOpen, Note: "kept.md"},
+		{Action: history.ActionCreate, Note: "gone.md"},
+	} {
+		if err := history.Append(v.Config.HistoryPath, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := findNote(v, "-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "kept.md" {
+		t.Errorf("findNote(-) = %q, want kept.md, the latest note that still exists", got)
+	}
+}
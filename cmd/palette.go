@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	tui "cobra-cli/internal/tui"
+)
+
+// Annotations of the commands offered by the vault viewer's command palette
+const (
+	paletteTitleKey       = "noted_palette_title"
+	palettePromptKey      = "noted_palette_prompt"
+	paletteChoicesKey     = "noted_palette_choices"
+	paletteChoiceTitleKey = "noted_palette_choice_title"
+)
+
+// paletteEntry describes how a command shows up in the command palette. The palette is
+// built from the commands themselves, so the CLI and the TUI offer the same actions.
+type paletteEntry struct {
+	Title  string
+	Prompt string // Asks for the command's argument before running it
+	// Choices adds an action per shell completion of the command's argument ("args") or
+	// of a flag (its name), titled ChoiceTitle, or Title, followed by the value.
+	Choices     string
+	ChoiceTitle string
+}
+
+func (e paletteEntry) annotations() map[string]string {
+	a := map[string]string{paletteTitleKey: e.Title}
+	if e.Prompt != "" {
+		a[palettePromptKey] = e.Prompt
+	}
+	if e.Choices != "" {
+		a[paletteChoicesKey] = e.Choices
+	}
+	if e.ChoiceTitle != "" {
+		a[paletteChoiceTitleKey] = e.ChoiceTitle
+	}
+	return a
+}
+
+// paletteAction is a palette action along with the command line it runs.
+type paletteAction struct {
+	tui.PaletteAction
+	cmd   *cobra.Command
+	args  []string
+	flags map[string]string
+}
+
+// paletteActions returns the actions of every command under root that has a palette
+// entry, in command order, with the actions for completions after all the others.
+func paletteActions(root *cobra.Command) []paletteAction {
+	var actions, choices []paletteAction
+	var visit func(c *cobra.Command)
+	visit = func(c *cobra.Command) {
+		if title, ok := c.Annotations[paletteTitleKey]; ok && !c.Hidden {
+			prompt := c.Annotations[palettePromptKey]
+			actions = append(actions, paletteAction{
+				PaletteAction: tui.PaletteAction{Title: title, Description: c.Short, Prompt: prompt},
+				cmd:           c,
+			})
+			choiceTitle := c.Annotations[paletteChoiceTitleKey]
+			if choiceTitle == "" {
+				choiceTitle = title
+			}
+			from := c.Annotations[paletteChoicesKey]
+			for _, choice := range completions(c, from) {
+				value, desc, _ := strings.Cut(choice, "\t")
+				if desc == "" {
+					desc = c.Short
+				}
+				a := paletteAction{
+					PaletteAction: tui.PaletteAction{Title: choiceTitle + ": " + value, Description: desc, Prompt: prompt},
+					cmd:           c,
+				}
+				if from == "args" {
+					a.args = []string{value}
+				} else {
+					a.flags = map[string]string{from: value}
+				}
+				choices = append(choices, a)
+			}
+		}
+		for _, sub := range c.Commands() {
+			visit(sub)
+		}
+	}
+	visit(root)
+	actions = append(actions, choices...)
+	for i := range actions {
+		actions[i].ID = strconv.Itoa(i)
+	}
+	return actions
+}
+
+// completions returns the shell completions of c's argument or of one of its flags.
+func completions(c *cobra.Command, from string) []string {
+	var fn cobra.CompletionFunc
+	switch from {
+	case "":
+		return nil
+	case "args":
+		fn = c.ValidArgsFunction
+	default:
+		fn, _ = c.GetFlagCompletionFunc(from)
+	}
+	if fn == nil {
+		return nil
+	}
+	values, directive := fn(c, nil, "")
+	if directive&cobra.ShellCompDirectiveError != 0 {
+		return nil
+	}
+	return values
+}
+
+// paletteChoices converts actions for the palette.
+func paletteChoices(actions []paletteAction) []tui.PaletteAction {
+	choices := make([]tui.PaletteAction, len(actions))
+	for i, a := range actions {
+		choices[i] = a.PaletteAction
+	}
+	return choices
+}
+
+// runPaletteAction runs the command of the action with ID id, adding arg to its arguments
+// if the action asked for one.
+func runPaletteAction(actions []paletteAction, id, arg string) error {
+	for _, a := range actions {
+		if a.ID != id {
+			continue
+		}
+		args := append([]string(nil), a.args...)
+		if arg != "" {
+			args = append(args, arg)
+		}
+		for name, value := range a.flags {
+			if err := a.cmd.Flags().Set(name, value); err != nil {
+				return err
+			}
+		}
+		if err := a.cmd.ValidateArgs(args); err != nil {
+			return err
+		}
+		if a.cmd.RunE == nil {
+			return a.cmd.Help()
+		}
+		return a.cmd.RunE(a.cmd, args)
+	}
+	return fmt.Errorf("unknown palette action %q", id)
+}
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/history"
+)
+
+var (
+	recentLimitFlag    int
+	recentCommandsFlag bool
+)
+
+// recentCmd represents the recent command
+var recentCmd = &cobra.Command{
+	Use:   "recent",
+	Short: "List recently opened, created and edited notes",
+	Long: `List the notes of the current vault you opened, created or edited most recently,
+as recorded in the vault's history log. With --commands, list the noted commands
+run in the vault instead.
+
+Open the most recent note again with 'noted open -'.`,
+	Annotations: paletteEntry{Title: "Recent notes"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showRecent(cmd.OutOrStdout(), recentLimitFlag, recentCommandsFlag)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(recentCmd)
+	recentCmd.Flags().IntVarP(&recentLimitFlag, "limit", "n", 10, "Number of entries to show, 0 for all")
+	recentCmd.Flags().BoolVar(&recentCommandsFlag, "commands", false, "List recent commands instead of notes")
+}
+
+func showRecent(out io.Writer, limit int, commands bool) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	if commands {
+		entries, err := history.Read(v.Config.HistoryPath)
+		if err != nil {
+			return err
+		}
+		recent := history.RecentCommands(entries, limit)
+		if len(recent) == 0 {
+			fmt.Fprintln(out, "No commands recorded yet.")
+			return nil
+		}
+		for _, e := range recent {
+			fmt.Fprintf(out, "  %-12s %s\n", timeAgo(e.Time), e.Command)
+		}
+		return nil
+	}
+	recent := recentNotes(v, limit)
+	if len(recent) == 0 {
+		fmt.Fprintln(out, "No recent notes yet. Open one with 'noted open <note>'.")
+		return nil
+	}
+	fmt.Fprintf(out, "Recent notes in %s:\n", v.Name)
+	for i, e := range recent {
+		fmt.Fprintf(out, "  %d. %-40s %s\n", i+1, e.Note, describeEntry(e))
+	}
+	return nil
+}
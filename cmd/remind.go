@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/config"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/vault"
+	"cobra-cli/internal/vaultlog"
+)
+
+// remindCatchUp is how late a reminder may still be sent, so a first run, or one after a
+// long pause, does not bring up every overdue task at once.
+const remindCatchUp = 24 * time.Hour
+
+// remindCommandTimeout is how long remind_command may run before it is killed, so a command
+// that hangs cannot hold up the reminders after it.
+const remindCommandTimeout = 30 * time.Second
+
+var (
+	remindWatchFlag    bool
+	remindIntervalFlag string
+	remindWithinFlag   string
+)
+
+// remindCmd represents the remind command
+var remindCmd = &cobra.Command{
+	Use:   "remind",
+	Short: "Send reminders of tasks coming due",
+	Long: `Bring up the open tasks of the current vault whose reminder time has come. A task is
+reminded of on its due date at the remind_time setting (09:00 unless set), or at the
+time of its remind:
+
+  - [ ] Call the plumber due:2026-10-20 remind:14:30
+
+Each reminder is sent once. Reminders more than a day late are skipped. With the
+remind_command setting, reminders run that shell command with the task's text as last
+argument, and NOTED_TASK, NOTED_NOTE, NOTED_LINE, NOTED_DUE and NOTED_VAULT in its
+environment; otherwise they are printed. A command still running after 30 seconds is
+stopped.
+
+  noted remind                 # Send the reminders that are due, e.g. from cron
+  noted remind --watch         # Keep sending them as they come due, until interrupted
+  noted remind list            # Reminders of the coming week
+
+  noted config set remind_command 'notify-send "noted reminder"'`,
+	Args:        cobra.NoArgs,
+	Annotations: paletteEntry{Title: "Send due reminders"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		v, err := openCurrentVault()
+		if err != nil {
+			return err
+		}
+		if !remindWatchFlag {
+			_, err := sendReminders(cmd.OutOrStdout(), cmd.ErrOrStderr(), v, time.Now())
+			return err
+		}
+		interval, err := config.ParseDuration(remindIntervalFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --interval: %w", err)
+		}
+		if interval <= 0 {
+			return fmt.Errorf("invalid --interval: must be positive")
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Watching the tasks of %s every %s; press Ctrl+C to stop.\n", v.Name, config.FormatDuration(interval))
+		for {
+			if _, err := sendReminders(cmd.OutOrStdout(), cmd.ErrOrStderr(), v, time.Now()); err != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "✗ %v\n", err)
+			}
+			time.Sleep(interval)
+		}
+	},
+}
+
+// remindListCmd represents the remind list command
+var remindListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List upcoming reminders",
+	Long: `List the reminders of open tasks coming due, and of those due in the past day, with
+the ones already sent marked.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		within, err := config.ParseDuration(remindWithinFlag)
+		if err != nil {
+			return fmt.Errorf("invalid --within: %w", err)
+		}
+		return listReminders(cmd.OutOrStdout(), time.Now(), within)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(remindCmd)
+	remindCmd.AddCommand(remindListCmd)
+	remindCmd.Flags().BoolVarP(&remindWatchFlag, "watch", "w", false, "Keep running and send reminders as they come due")
+	remindCmd.Flags().StringVar(&remindIntervalFlag, "interval", "1m", "How often --watch checks the tasks, e.g. 30s or 5m")
+	remindListCmd.Flags().StringVar(&remindWithinFlag, "within", "7d", "How far ahead to list reminders, e.g. 12h or 30d")
+}
+
+// vaultReminders returns the reminders of the open tasks of v
+func vaultReminders(v models.Vault) ([]vault.Reminder, error) {
+	def, err := vault.ParseClock(notedApp.Settings().GetString("remind_time"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid remind_time: %w", err)
+	}
+	tasks, err := vault.CollectTasks(v)
+	if err != nil {
+		return nil, err
+	}
+	return vault.Reminders(tasks, def), nil
+}
+
+// sendReminders sends the reminders of v that came due by now and were not sent yet, and
+// returns how many it sent
+func sendReminders(out, errOut io.Writer, v models.Vault, now time.Time) (int, error) {
+	reminders, err := vaultReminders(v)
+	if err != nil {
+		return 0, err
+	}
+	sent, err := vault.ReadReminded(v.Path)
+	if err != nil {
+		return 0, err
+	}
+	command := notedApp.Settings().GetString("remind_command")
+	var done []vault.Reminder
+	for _, r := range reminders {
+		if r.At.After(now) || now.Sub(r.At) > remindCatchUp {
+			continue
+		}
+		if _, ok := sent[r.Key()]; ok {
+			continue
+		}
+		if err := sendReminder(out, errOut, v, r, command); err != nil {
+			fmt.Fprintf(errOut, "✗ Reminder of %s:%d failed: %v\n", r.Rel, r.Line, err)
+			continue
+		}
+		done = append(done, r)
+	}
+	if len(done) == 0 {
+		return 0, nil
+	}
+	logVaultEvent(v, vaultlog.EventRemind, "reminders sent", "count", len(done))
+	return len(done), vault.MarkReminded(v.Path, done, now)
+}
+
+// sendReminder prints r, or runs command for it through sh with the task's text as last
+// argument
+func sendReminder(out, errOut io.Writer, v models.Vault, r vault.Reminder, command string) error {
+	if strings.TrimSpace(command) == "" {
+		fmt.Fprintf(out, "⏰ %s  (%s)  %s:%d\n", r.Text, r.At.Format("2006-01-02 15:04"), r.Rel, r.Line)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), remindCommandTimeout)
+	defer cancel()
+	c := exec.CommandContext(ctx, "sh", "-c", command+` "$@"`, "noted", r.Text)
+	c.Env = append(os.Environ(),
+		"NOTED_TASK="+r.Text,
+		"NOTED_NOTE="+r.Rel,
+		"NOTED_LINE="+strconv.Itoa(r.Line),
+		"NOTED_DUE="+r.Due.Format("2006-01-02"),
+		"NOTED_VAULT="+v.Name,
+	)
+	c.Stdout = out
+	c.Stderr = errOut
+	// Don't wait on processes the command left running with its output still open
+	c.WaitDelay = time.Second
+	err := c.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return fmt.Errorf("remind_command did not finish within %s", remindCommandTimeout)
+	}
+	return err
+}
+
+func listReminders(out io.Writer, now time.Time, within time.Duration) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	reminders, err := vaultReminders(v)
+	if err != nil {
+		return err
+	}
+	sent, err := vault.ReadReminded(v.Path)
+	if err != nil {
+		return err
+	}
+	shown := 0
+	for _, r := range reminders {
+		if now.Sub(r.At) > remindCatchUp || r.At.Sub(now) > within {
+			continue
+		}
+		state := "   "
+		if _, ok := sent[r.Key()]; ok {
+			state = " ✓ " // Already sent
+		}
+		fmt.Fprintf(out, "%s%s  %s  %s:%d\n", state, r.At.Format("Mon 2006-01-02 15:04"), r.Text, r.Rel, r.Line)
+		shown++
+	}
+	if shown == 0 {
+		fmt.Fprintln(out, "No upcoming reminders.")
+		return nil
+	}
+	fmt.Fprintf(out, "\n%d %s\n", shown, plural(shown, "reminder", "reminders"))
+	return nil
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/journal"
+	"cobra-cli/internal/vault"
+)
+
+var replaceDryRunFlag bool
+
+// replaceCmd represents the replace command
+var replaceCmd = &cobra.Command{
+	Use:   "replace <text> <replacement>",
+	Short: "Replace text in every note",
+	Long: `Replace every occurrence of a piece of text in the notes of the current vault.
+The match is literal and case-sensitive. Use --dry-run to see which notes would
+change first.
+
+The edit is recorded in the vault's journal; reverse it with 'noted undo'.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return replaceText(cmd.OutOrStdout(), args[0], args[1], replaceDryRunFlag)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(replaceCmd)
+	replaceCmd.Flags().BoolVarP(&replaceDryRunFlag, "dry-run", "n", false, "List the notes that would change without changing them")
+}
+
+func replaceText(out io.Writer, text, replacement string, dryRun bool) error {
+	if text == "" {
+		return fmt.Errorf("nothing to replace")
+	}
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	var changes []journal.Change
+	total := 0
+	err = eachTextNote(v, func(e vault.Entry, content string) error {
+		n := strings.Count(content, text)
+		if n == 0 {
+			return nil
+		}
+		total += n
+		changes = append(changes, journal.Change{
+			Path:   e.Rel,
+			Before: journal.Contents([]byte(content)),
+			After:  journal.Contents([]byte(strings.ReplaceAll(content, text, replacement))),
+		})
+		if dryRun {
+			fmt.Fprintf(out, "  %s (%d)\n", e.Rel, n)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return fmt.Errorf("%q does not occur in any note", text)
+	}
+	summary := fmt.Sprintf("%d %s in %d %s", total, plural(total, "occurrence", "occurrences"), len(changes), plural(len(changes), "note", "notes"))
+	if dryRun {
+		fmt.Fprintf(out, "Would replace %s\n", summary)
+		return nil
+	}
+	if _, err := journal.Apply(v.Path, journal.Operation{
+		Kind:        journal.KindEdit,
+		Description: fmt.Sprintf("replace %q with %q", text, replacement),
+		Changes:     changes,
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "✓ Replaced %s\n", summary)
+	return nil
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/journal"
+	"cobra-cli/internal/vault"
+	"cobra-cli/internal/vaultlog"
+)
+
+// rmCmd represents the rm command
+var rmCmd = &cobra.Command{
+	Use:   "rm <note>...",
+	Short: "Move notes to the trash",
+	Long: `Delete notes of the current vault, found by name or path, by moving them into
+the vault's .trash folder. Trashed notes are kept for trash_retention days and
+can be restored with 'noted trash restore' until then.
+
+The deletion is also recorded in the vault's journal, so 'noted undo' brings
+the notes straight back.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return removeNotes(cmd.OutOrStdout(), args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rmCmd)
+}
+
+func removeNotes(out io.Writer, names []string) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	purgeTrash(v)
+	ix, err := vault.BuildIndex(v)
+	if err != nil {
+		return err
+	}
+	var changes []journal.Change
+	var rels []string
+	seen := map[string]bool{}
+	now := time.Now()
+	for _, name := range names {
+		rel, ok := ix.Resolve(name)
+		if !ok {
+			return fmt.Errorf("note %q not found in vault %s", name, v.Name)
+		}
+		if seen[rel] {
+			continue
+		}
+		seen[rel] = true
+		path := filepath.Join(v.Path, filepath.FromSlash(rel))
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		// Every note gets its own deletion time, which names its place in the trash
+		_, trashed, err := vault.TrashChanges(rel, data, info.Mode().Perm(), now.Add(time.Duration(len(rels))))
+		if err != nil {
+			return err
+		}
+		changes = append(changes, trashed...)
+		rels = append(rels, rel)
+	}
+	if _, err := journal.Apply(v.Path, journal.Operation{
+		Kind:        journal.KindDelete,
+		Description: "delete " + strings.Join(rels, ", "),
+		Changes:     changes,
+	}); err != nil {
+		return err
+	}
+	for _, rel := range rels {
+		logVaultEvent(v, vaultlog.EventDelete, "note moved to trash", "note", rel)
+		fmt.Fprintf(out, "✓ Moved %s to the trash\n", rel)
+	}
+	return nil
+}
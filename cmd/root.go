@@ -6,20 +6,21 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
+	log "github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 
+	"cobra-cli/internal/app"
+	"cobra-cli/internal/history"
 	tui "cobra-cli/internal/tui"
-	"cobra-cli/internal/models"
-	"encoding/json"
 )
 
-var configDir string
-var configFile string
-var notedConfig *viper.Viper
+// notedApp is built once, before any command runs, by initApp
+var notedApp *app.App
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
@@ -29,206 +30,240 @@ var rootCmd = &cobra.Command{
 It supports vaults, templates, and fast search, with configuration stored in $XDG_CONFIG_HOME/noted or ~/.config/noted.
 
 Run 'noted' without arguments to see the tutorial menu with all available commands.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		initConfigDir()
-		initConfigFile()
-		
+	SilenceUsage:      true,
+	PersistentPreRunE: initApp,
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		recordCommand(cmd)
+		printNotices(cmd)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Show tutorial menu
-		showTutorialMenu()
+		showTutorialMenu(cmd.OutOrStdout())
+		return nil
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	cmd, err := rootCmd.ExecuteC()
 	if err != nil {
+		logCommandError(cmd, err)
 		os.Exit(1)
 	}
 }
 
 func init() {
-	cobra.OnInitialize(initConfigDir, initConfigFile)
-	
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
+
+	// Persistent flags form the highest settings layer
+	rootCmd.PersistentFlags().String("editor", "", "Editor used to open notes and config files")
+	rootCmd.PersistentFlags().String("log-level", "", "Minimum log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().String("theme", "", "Color theme of the TUI (see 'noted theme list')")
+	rootCmd.PersistentFlags().String("keymap", "", "Key bindings of the TUI: default, vim or emacs (see 'noted keys')")
+}
+
+// initApp builds the shared app state once per process
+func initApp(cmd *cobra.Command, args []string) error {
+	if notedApp != nil {
+		return nil
+	}
+	a, err := app.New(app.Options{Flags: cmd.Root().PersistentFlags()})
+	if err != nil {
+		return err
+	}
+	notedApp = a
+	if level, err := log.ParseLevel(notedApp.Settings().GetString("log_level")); err == nil {
+		log.SetLevel(level)
+	}
+	if t, err := notedApp.Theme(); err == nil {
+		tui.SetTheme(t)
+	} else {
+		log.Warn("using the default theme", "err", err)
+	}
+	if km, err := tui.LoadKeyMap(notedApp.Settings().GetString("keymap"), notedApp.KeyOverrides()); err == nil {
+		tui.SetKeyMap(km)
+	} else {
+		log.Warn("using the default keymap", "err", err)
+	}
+	printNotices(cmd)
+	return nil
+}
+
+// printNotices shows messages collected by the app, such as config migrations, on stderr
+func printNotices(cmd *cobra.Command) {
+	if notedApp == nil {
+		return
+	}
+	for _, notice := range notedApp.DrainNotices() {
+		fmt.Fprintln(cmd.ErrOrStderr(), notice)
+	}
 }
 
-func showTutorialMenu() {
-	currentVault := notedConfig.GetString("current_vault")
-	vaults := notedConfig.GetStringSlice("vaults")
+// recordCommand adds the command line to the current vault's history. Hidden commands,
+// such as the shell completion requests made on every Tab press, are not recorded.
+func recordCommand(cmd *cobra.Command) {
+	if cmd == cmd.Root() || notedApp == nil || notedApp.CurrentVault() == "" || isInternalCommand(cmd) {
+		return
+	}
+	v, err := openCurrentVault()
+	if err != nil {
+		return
+	}
+	recordHistory(v, history.Entry{Action: history.ActionCommand, Command: strings.Join(append([]string{"noted"}, os.Args[1:]...), " ")})
+}
+
+// isInternalCommand reports whether cmd is a hidden command or a shell completion request
+func isInternalCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Hidden {
+			return true
+		}
+	}
+	switch cmd.Name() {
+	case cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+		return true
+	}
+	return false
+}
+
+func showTutorialMenu(out io.Writer) {
+	currentVault := notedApp.CurrentVault()
+	vaults := notedApp.Vaults()
 	
-	fmt.Println("╔═══════════════════════════════════════════════════════════════════════════════╗")
-	fmt.Println("║                                   📝 NOTED                                    ║")
-	fmt.Println("║                    A CLI tool for managing notes and vaults                   ║")
-	fmt.Println("╚═══════════════════════════════════════════════════════════════════════════════╝")
-	fmt.Println()
+	fmt.Fprintln(out, "╔═══════════════════════════════════════════════════════════════════════════════╗")
+	fmt.Fprintln(out, "║                                   📝 NOTED                                    ║")
+	fmt.Fprintln(out, "║                    A CLI tool for managing notes and vaults                   ║")
+	fmt.Fprintln(out, "╚═══════════════════════════════════════════════════════════════════════════════╝")
+	fmt.Fprintln(out)
 	
 	// Show current vault status
 	if currentVault != "" {
-		fmt.Printf("📂 Current vault: %s\n", getVaultName(currentVault))
-		fmt.Printf("   Path: %s\n", currentVault)
+		fmt.Fprintf(out, "📂 Current vault: %s\n", getVaultName(currentVault))
+		fmt.Fprintf(out, "   Path: %s\n", currentVault)
 	} else {
-		fmt.Println("⚠️  No vault selected. Use 'noted vault' to select or create one.")
+		fmt.Fprintln(out, "⚠️  No vault selected. Use 'noted vault' to select or create one.")
 	}
-	fmt.Println()
+	fmt.Fprintln(out)
 	
 	// Show available commands
-	fmt.Println("🔧 Available Commands:")
-	fmt.Println()
+	fmt.Fprintln(out, "🔧 Available Commands:")
+	fmt.Fprintln(out)
 	
-	fmt.Println("  📁 VAULT MANAGEMENT:")
-	fmt.Println("    noted vault                    # Interactive vault selection/creation menu")
-	fmt.Println("    noted vault --open <name>      # Open vault by name")
-	fmt.Println("    noted vault --open <index>     # Open vault by index (1-based)")
-	fmt.Println("    noted vault list               # List all configured vaults")
-	fmt.Println("    noted vault current            # Show current vault")
-	fmt.Println("    noted vault create <path>      # Create new vault at specified path")
-	fmt.Println()
+	fmt.Fprintln(out, "  📁 VAULT MANAGEMENT:")
+	fmt.Fprintln(out, "    noted vault                    # Interactive vault selection/creation menu")
+	fmt.Fprintln(out, "    noted vault --open <name>      # Open vault by name")
+	fmt.Fprintln(out, "    noted vault --open <index>     # Open vault by index (1-based)")
+	fmt.Fprintln(out, "    noted vault list               # List all configured vaults")
+	fmt.Fprintln(out, "    noted vault group              # Manage groups of vaults")
+	fmt.Fprintln(out, "    noted vault current            # Show current vault")
+	fmt.Fprintln(out, "    noted vault create <path>      # Create new vault at specified path")
+	fmt.Fprintln(out, "    noted vault create <path> -p <preset>  # Create a vault from a preset")
+	fmt.Fprintln(out)
 	
-	fmt.Println("  🔍 SEARCH & NAVIGATION:")
-	fmt.Println("    noted search                   # Search through files and directories")
-	fmt.Println("    noted search --files           # Search files only")
-	fmt.Println("    noted search --dirs            # Search directories only")
-	fmt.Println()
+	fmt.Fprintln(out, "  🔍 SEARCH & NAVIGATION:")
+	fmt.Fprintln(out, "    noted search                   # Search through files and directories")
+	fmt.Fprintln(out, "    noted search --files           # Search files only")
+	fmt.Fprintln(out, "    noted search --dirs            # Search directories only")
+	fmt.Fprintln(out, "    noted search --group <group>   # Search every vault in a group")
+	fmt.Fprintln(out, "    noted search --all-vaults      # Search every registered vault")
+	fmt.Fprintln(out, "    noted search -i <query>        # Pick a match with a rendered preview")
+	fmt.Fprintln(out, "    noted links <note>             # Show links and backlinks, across vaults")
+	fmt.Fprintln(out, "    noted export <dest>            # Copy a vault's notes elsewhere")
+	fmt.Fprintln(out)
 	
-	fmt.Println("  📋 TEMPLATES:")
-	fmt.Println("    noted templates                # Manage and browse templates")
-	fmt.Println("    noted templates list           # List available templates")
-	fmt.Println("    noted templates create         # Create new file from template")
-	fmt.Println()
+	fmt.Fprintln(out, "  📊 INSIGHTS:")
+	fmt.Fprintln(out, "    noted stats                    # Vault statistics dashboard (JSON when piped)")
+	fmt.Fprintln(out, "    noted activity                 # Writing heatmap, streaks and words per day")
+	fmt.Fprintln(out, "    noted log                      # Tail and filter the vault's activity log")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "  📝 NOTES:")
+	fmt.Fprintln(out, "    noted open [note]              # Open a note, or pick one in the vault viewer")
+	fmt.Fprintln(out, "    noted open -                   # Jump back to the last note")
+	fmt.Fprintln(out, "    noted edit <note>              # Edit a note without leaving noted")
+	fmt.Fprintln(out, "    noted new <title>              # Create a note")
+	fmt.Fprintln(out, "    noted daily                    # Open today's daily note")
+	fmt.Fprintln(out, "    noted recent                   # List recently used notes")
+	fmt.Fprintln(out, "    noted mv <note> <dest>         # Rename or move a note, updating links")
+	fmt.Fprintln(out, "    noted rm <note>                # Move a note to the vault's trash")
+	fmt.Fprintln(out, "    noted trash                    # List, restore or empty trashed notes")
+	fmt.Fprintln(out, "    noted tasks                    # Agenda of open - [ ] tasks, with filters")
+	fmt.Fprintln(out, "    noted tasks toggle <note:line> # Check off or reopen a task; every: tasks recur")
+	fmt.Fprintln(out, "    noted remind [--watch]         # Send reminders of tasks coming due")
+	fmt.Fprintln(out, "    noted board <note>             # Kanban board of a note's headings, or --folder by status")
+	fmt.Fprintln(out, "    noted tags [tag]               # List tags, or the notes carrying one")
+	fmt.Fprintln(out, "    noted tags rename <old> <new>  # Rename a tag in every note")
+	fmt.Fprintln(out, "    noted replace <text> <new>     # Replace text in every note")
+	fmt.Fprintln(out, "    noted undo / noted redo        # Undo or redo the last of those changes")
+	fmt.Fprintln(out)
+
+	fmt.Fprintln(out, "  📋 TEMPLATES:")
+	fmt.Fprintln(out, "    noted templates                # Manage and browse templates")
+	fmt.Fprintln(out, "    noted templates list           # List available templates")
+	fmt.Fprintln(out, "    noted templates create         # Create new file from template")
+	fmt.Fprintln(out, "    noted new <title> -t <name>    # Create a note from a template")
+	fmt.Fprintln(out)
 	
-	fmt.Println("  ℹ️  HELP & INFO:")
-	fmt.Println("    noted help                     # Show this help menu")
-	fmt.Println("    noted version                  # Show version information")
-	fmt.Println()
+	fmt.Fprintln(out, "  ℹ️  HELP & INFO:")
+	fmt.Fprintln(out, "    noted help                     # Show this help menu")
+	fmt.Fprintln(out, "    noted version                  # Show version information")
+	fmt.Fprintln(out, "    noted theme                    # List, preview and create color themes")
+	fmt.Fprintln(out, "    noted keys                     # Show the key bindings of the TUI")
+	fmt.Fprintln(out)
 	
 	// Show quick start guide
 	if len(vaults) == 0 {
-		fmt.Println("🚀 Quick Start:")
-		fmt.Println("   1. Run 'noted vault' to create your first vault")
-		fmt.Println("   2. Select a directory to use as your notes vault")
-		fmt.Println("   3. Start organizing your notes!")
-		fmt.Println()
+		fmt.Fprintln(out, "🚀 Quick Start:")
+		fmt.Fprintln(out, "   1. Run 'noted vault' to create your first vault")
+		fmt.Fprintln(out, "   2. Select a directory to use as your notes vault")
+		fmt.Fprintln(out, "   3. Start organizing your notes!")
+		fmt.Fprintln(out)
 	} else {
-		fmt.Println("🚀 Quick Actions:")
-		fmt.Println("   • 'noted vault' - Switch or create vaults")
-		fmt.Println("   • 'noted search' - Find files and notes")
-		fmt.Println("   • 'noted templates' - Use templates for new files")
-		fmt.Println()
+		fmt.Fprintln(out, "🚀 Quick Actions:")
+		fmt.Fprintln(out, "   • 'noted vault' - Switch or create vaults")
+		fmt.Fprintln(out, "   • 'noted search' - Find files and notes")
+		fmt.Fprintln(out, "   • 'noted templates' - Use templates for new files")
+		fmt.Fprintln(out)
 	}
 	
-	fmt.Println("💡 Tip: Most commands support interactive menus for easy navigation!")
-	fmt.Println()
+	fmt.Fprintln(out, "💡 Tip: Most commands support interactive menus for easy navigation!")
+	fmt.Fprintln(out)
 	
+	// Show recent notes of the current vault
+	if currentVault != "" {
+		if v, err := openCurrentVault(); err == nil {
+			if recent := recentNotes(v, 5); len(recent) > 0 {
+				fmt.Fprintln(out, "🕒 Recent Notes:")
+				for _, e := range recent {
+					fmt.Fprintf(out, "   • %s (%s)\n", e.Note, describeEntry(e))
+				}
+				fmt.Fprintln(out)
+			}
+		}
+	}
+
 	// Show vault list if any exist
 	if len(vaults) > 0 {
-		fmt.Println("📋 Your Vaults:")
+		fmt.Fprintln(out, "📋 Your Vaults:")
 		for i, vault := range vaults {
 			current := ""
-			if vault == currentVault {
+			if vault.Path == currentVault {
 				current = " (current)"
 			}
-			fmt.Printf("   %d. %s%s\n", i+1, getVaultName(vault), current)
+			fmt.Fprintf(out, "   %d. %s%s\n", i+1, vault.Name, current)
 		}
-		fmt.Println()
+		fmt.Fprintln(out)
 	}
 	
-	fmt.Println("Run any command to get started, or 'noted help' for more information.")
-}
-
-// initConfigDir checks for $XDG_CONFIG_HOME/noted or ~/.config/noted, creates if missing
-func initConfigDir() {
-	xdgConfig := os.Getenv("XDG_CONFIG_HOME")
-	if xdgConfig == "" {
-		home, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Println("Could not determine home directory:", err)
-			os.Exit(1)
-		}
-		xdgConfig = filepath.Join(home, ".config")
-	}
-	configDir = filepath.Join(xdgConfig, "noted")
-	if _, err := os.Stat(configDir); os.IsNotExist(err) {
-		err := os.MkdirAll(configDir, 0o755)
-		if err != nil {
-			fmt.Println("Failed to create config directory:", err)
-			os.Exit(1)
-		}
-		fmt.Println("Created config directory at", configDir)
-	}
-}
-
-// initConfigFile initializes the config file using Viper
-func initConfigFile() {
-	configFile = filepath.Join(configDir, "config.yaml")
-	notedConfig = viper.New()
-	notedConfig.SetConfigFile(configFile)
-	notedConfig.SetConfigType("yaml")
-
-	if _, err := os.Stat(configFile); os.IsNotExist(err) {
-		// Create default config
-		notedConfig.Set("vaults", []string{})
-		notedConfig.Set("current_vault", "")
-		notedConfig.Set("templates_dir", "")
-		notedConfig.Set("other_settings", map[string]interface{}{})
-		err := notedConfig.WriteConfigAs(configFile)
-		if err != nil {
-			fmt.Println("Failed to write default config:", err)
-			os.Exit(1)
-		}
-		fmt.Println("Initialized new config at", configFile)
-	} else {
-		err := notedConfig.ReadInConfig()
-		if err != nil {
-			fmt.Println("Failed to read config:", err)
-			os.Exit(1)
-		}
-	}
-}
-
-// Helper to load vaults from config
-func loadVaults() []models.Vault {
-	var vaults []models.Vault
-	vaultsRaw := notedConfig.Get("vaults")
-	if vaultsRaw == nil {
-		return vaults
-	}
-	// Try to unmarshal from JSON if stored as string
-	switch v := vaultsRaw.(type) {
-	case string:
-		_ = json.Unmarshal([]byte(v), &vaults)
-	case []interface{}:
-		for _, item := range v {
-			if m, ok := item.(map[string]interface{}); ok {
-				vaults = append(vaults, models.Vault{
-					Name: m["Name"].(string),
-					Path: m["Path"].(string),
-				})
-			}
-		}
-	}
-	return vaults
-}
-
-func saveVaults(vaults []models.Vault) {
-	b, _ := json.Marshal(vaults)
-	notedConfig.Set("vaults", string(b))
+	fmt.Fprintln(out, "Run any command to get started, or 'noted help' for more information.")
 }
 
-func vaultContains(vaults []models.Vault, v models.Vault) bool {
-	for _, vault := range vaults {
-		if vault.Path == v.Path {
-			return true
-		}
-	}
-	return false
-}
-
-func ensureVault() {
-	vaults := loadVaults()
-	currentVault := notedConfig.GetString("current_vault")
+func ensureVault(out io.Writer) error {
+	vaults := notedApp.Vaults()
+	currentVault := notedApp.CurrentVault()
 	found := false
 	for _, v := range vaults {
 		if v.Path == currentVault {
@@ -237,31 +272,25 @@ func ensureVault() {
 		}
 	}
 	if currentVault == "" || !found {
-		selectedVault, err := tui.LaunchVaultTUI(vaults, currentVault)
+		selectedVault, err := runVaultTUI()
 		if err != nil {
-			fmt.Println("Error selecting vault:", err)
-			os.Exit(1)
+			return err
 		}
-		if !vaultContains(vaults, selectedVault) {
-			vaults = append(vaults, selectedVault)
-			saveVaults(vaults)
-		}
-		notedConfig.Set("current_vault", selectedVault.Path)
-		err = notedConfig.WriteConfigAs(configFile)
+		err = notedApp.SelectVault(selectedVault)
 		if err != nil {
-			fmt.Println("Failed to update config:", err)
-			os.Exit(1)
+			return fmt.Errorf("failed to update config: %w", err)
 		}
-		fmt.Println("Vault set to:", selectedVault.Name)
-		return
+		fmt.Fprintln(out, "Vault set to:", selectedVault.Name)
+		return nil
 	}
 	for _, v := range vaults {
 		if v.Path == currentVault {
-			fmt.Println("Current vault:", v.Name)
-			return
+			fmt.Fprintln(out, "Current vault:", v.Name)
+			return nil
 		}
 	}
-	fmt.Println("Current vault path:", currentVault, "(not found in vaults list)")
+	fmt.Fprintln(out, "Current vault path:", currentVault, "(not found in vaults list)")
+	return nil
 }
 
 func contains(slice []string, s string) bool {
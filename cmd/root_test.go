@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestIsInternalCommand(t *testing.T) {
+	root := &cobra.Command{Use: "noted"}
+	tasks := &cobra.Command{Use: "tasks"}
+	hidden := &cobra.Command{Use: "debug", Hidden: true}
+	hiddenChild := &cobra.Command{Use: "dump"}
+	hidden.AddCommand(hiddenChild)
+	complete := &cobra.Command{Use: cobra.ShellCompRequestCmd}
+	completeNoDesc := &cobra.Command{Use: cobra.ShellCompNoDescRequestCmd}
+	root.AddCommand(tasks, hidden, complete, completeNoDesc)
+
+	tests := []struct {
+		cmd  *cobra.Command
+		want bool
+	}{
+		{cmd: tasks, want: false},
+		{cmd: hidden, want: true},
+		{cmd: hiddenChild, want: true},
+		{cmd: complete, want: true},
+		{cmd: completeNoDesc, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd.CommandPath(), func(t *testing.T) {
+			if got := isInternalCommand(tt.cmd); got != tt.want {
+				t.Errorf("isInternalCommand(%s) = %v, want %v", tt.cmd.CommandPath(), got, tt.want)
+			}
+		})
+	}
+}
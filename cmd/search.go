@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	tui "cobra-cli/internal/tui"
+	"cobra-cli/internal/vault"
+	"github.com/spf13/cobra"
+)
+
+var searchFilesFlag bool
+var searchDirsFlag bool
+var searchInteractiveFlag bool
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search through files and directories",
+	Long: `Search the names and contents of the notes in the current vault.
+
+  noted search <query>               # Match note names and contents
+  noted search --files <query>       # Match note names only
+  noted search --dirs <query>        # Match directory names only
+  noted search --group work <query>  # Search every vault in the group
+  noted search --all-vaults <query>  # Search every registered vault
+  noted search -i <query>            # Pick a match in the vault viewer, with a preview
+
+The vault's supported_types and ignore_patterns decide which files are searched.`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: paletteEntry{Title: "Search notes", Prompt: "Search for"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := ""
+		if len(args) == 1 {
+			query = args[0]
+		}
+		q := vault.Query{Text: query, Files: searchFilesFlag, Dirs: searchDirsFlag}
+		if searchInteractiveFlag {
+			return browseSearch(cmd.OutOrStdout(), q)
+		}
+		return searchVaults(cmd.OutOrStdout(), q)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().BoolVar(&searchFilesFlag, "files", false, "Search file names only")
+	searchCmd.Flags().BoolVar(&searchDirsFlag, "dirs", false, "Search directory names only")
+	searchCmd.MarkFlagsMutuallyExclusive("files", "dirs")
+	addGroupFlag(searchCmd)
+	addAllVaultsFlag(searchCmd)
+	searchCmd.Flags().BoolVarP(&searchInteractiveFlag, "interactive", "i", false, "Pick a matching note in the vault viewer")
+	searchCmd.MarkFlagsMutuallyExclusive("group", "all-vaults")
+	searchCmd.MarkFlagsMutuallyExclusive("interactive", "group")
+	searchCmd.MarkFlagsMutuallyExclusive("interactive", "all-vaults")
+	searchCmd.MarkFlagsMutuallyExclusive("interactive", "dirs")
+}
+
+func searchVaults(out io.Writer, q vault.Query) error {
+	vaults, err := targetVaults()
+	if err != nil {
+		return err
+	}
+	labelled := len(vaults) > 1 || groupFlag != "" || allVaultsFlag
+	total := 0
+	for _, v := range vaults {
+		matches, err := vault.Search(v, q)
+		if err != nil {
+			return fmt.Errorf("search %s: %w", v.Name, err)
+		}
+		total += len(matches)
+		for _, m := range matches {
+			fmt.Fprintln(out, formatMatch(m, labelled))
+		}
+	}
+	if total == 0 {
+		fmt.Fprintln(out, "No matches.")
+	}
+	return nil
+}
+
+// browseSearch shows the notes of the current vault matching q in the vault viewer, with
+// the first matching line of each, and opens the one picked
+func browseSearch(out io.Writer, q vault.Query) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	matches, err := vault.Search(v, q)
+	if err != nil {
+		return err
+	}
+	var found []tui.ViewerNote
+	index := map[string]int{}
+	for _, m := range matches {
+		i, seen := index[m.Rel]
+		if !seen {
+			index[m.Rel] = len(found)
+			found = append(found, tui.ViewerNote{Rel: m.Rel})
+			i = len(found) - 1
+		}
+		if m.Line > 0 && found[i].Detail == "" {
+			found[i].Detail = fmt.Sprintf("%d: %s", m.Line, strings.TrimSpace(m.Text))
+		}
+	}
+	if len(found) == 0 {
+		fmt.Fprintln(out, "No matches.")
+		return nil
+	}
+	title := fmt.Sprintf("%s: %q", v.Name, q.Text)
+	result, err := tui.LaunchVaultViewer(tui.VaultViewerOptions{Title: title, Notes: found, Preview: notePreview(v)})
+	if err != nil {
+		return fmt.Errorf("error launching vault viewer: %w", err)
+	}
+	if result.Cancelled || result.Note == "" {
+		return nil
+	}
+	return editNote(out, v, result.Note)
+}
+
+func formatMatch(m vault.Match, labelled bool) string {
+	var b strings.Builder
+	if labelled {
+		fmt.Fprintf(&b, "[%s] ", m.Vault)
+	}
+	b.WriteString(m.Rel)
+	if m.IsDir {
+		b.WriteString("/")
+	}
+	if m.Line > 0 {
+		fmt.Fprintf(&b, ":%d: %s", m.Line, m.Text)
+	}
+	return b.String()
+}
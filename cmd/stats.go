@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	tui "cobra-cli/internal/tui"
+	"cobra-cli/internal/vault"
+)
+
+var statsJSONFlag bool
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show statistics about the current vault",
+	Long: `Show statistics about the current vault: note counts by folder and file type,
+total words, tags, links, orphan notes, the largest notes, and notes created or
+modified per month.
+
+On a terminal the statistics are shown as a dashboard; otherwise, or with --json,
+they are printed as JSON. The vault's supported_types and ignore_patterns decide
+which files are counted.`,
+	Annotations: paletteEntry{Title: "Vault statistics"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showStats(cmd.OutOrStdout(), statsJSONFlag || !isTerminal(cmd.OutOrStdout()))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().BoolVar(&statsJSONFlag, "json", false, "Print JSON even on a terminal")
+}
+
+func showStats(out io.Writer, asJSON bool) error {
+	registry, v, err := linkRegistry()
+	if err != nil {
+		return err
+	}
+	stats, err := vault.CollectStats(registry, v)
+	if err != nil {
+		return fmt.Errorf("collect stats: %w", err)
+	}
+	if asJSON {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+	fmt.Fprintln(out, tui.RenderStats(stats))
+	return nil
+}
+
+// isTerminal reports whether out is an interactive terminal
+func isTerminal(out io.Writer) bool {
+	f, ok := out.(*os.File)
+	return ok && (isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd()))
+}
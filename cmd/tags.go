@@ -0,0 +1,200 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/journal"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/notes"
+	"cobra-cli/internal/vault"
+)
+
+// tagsCmd represents the tags command
+var tagsCmd = &cobra.Command{
+	Use:   "tags [tag]",
+	Short: "List and rename the tags of the current vault",
+	Long: `List the tags used in the notes of the current vault with the number of notes
+carrying each, most used first. Tags come from the frontmatter tags field and from
+inline #tags outside code.
+
+With a tag, list the notes carrying it or a tag nested under it.`,
+	Args:        cobra.MaximumNArgs(1),
+	Annotations: paletteEntry{Title: "Browse tags", Choices: "args"}.annotations(),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		v, err := openCurrentVault()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		counts, err := tagCounts(v)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+		var tags []string
+		for _, tag := range sortedTags(counts) {
+			tags = append(tags, fmt.Sprintf("%s\t%d %s", tag, counts[tag], plural(counts[tag], "note", "notes")))
+		}
+		return tags, cobra.ShellCompDirectiveNoFileComp
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 1 {
+			return listTaggedNotes(cmd.OutOrStdout(), args[0])
+		}
+		return listTags(cmd.OutOrStdout())
+	},
+}
+
+// tagsRenameCmd represents the tags rename command
+var tagsRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tag in every note",
+	Long: `Rename a tag in the frontmatter and inline #tags of every note of the current
+vault. Nested tags move along, so renaming project to work turns #project/noted
+into #work/noted.
+
+The rename is recorded in the vault's journal; reverse it with 'noted undo'.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return renameTag(cmd.OutOrStdout(), args[0], args[1])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tagsCmd)
+	tagsCmd.AddCommand(tagsRenameCmd)
+}
+
+func listTags(out io.Writer) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	counts, err := tagCounts(v)
+	if err != nil {
+		return err
+	}
+	if len(counts) == 0 {
+		fmt.Fprintln(out, "No tags in this vault yet.")
+		return nil
+	}
+	for _, tag := range sortedTags(counts) {
+		fmt.Fprintf(out, "  %5d  #%s\n", counts[tag], tag)
+	}
+	return nil
+}
+
+// tagCounts counts the notes of v carrying each tag
+func tagCounts(v models.Vault) (map[string]int, error) {
+	counts := map[string]int{}
+	err := eachTextNote(v, func(e vault.Entry, content string) error {
+		for _, tag := range notes.ParseTags(content) {
+			counts[tag]++
+		}
+		return nil
+	})
+	return counts, err
+}
+
+// sortedTags orders tags by use, most used first
+func sortedTags(counts map[string]int) []string {
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	return tags
+}
+
+func listTaggedNotes(out io.Writer, tag string) error {
+	tag = strings.TrimPrefix(tag, "#")
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	found := 0
+	err = eachTextNote(v, func(e vault.Entry, content string) error {
+		for _, t := range notes.ParseTags(content) {
+			if t == tag || strings.HasPrefix(t, tag+"/") {
+				fmt.Fprintln(out, e.Rel)
+				found++
+				return nil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if found == 0 {
+		return fmt.Errorf("no notes are tagged #%s", tag)
+	}
+	return nil
+}
+
+func renameTag(out io.Writer, old, new string) error {
+	old = strings.TrimPrefix(old, "#")
+	new = strings.TrimPrefix(new, "#")
+	if old == "" || new == "" || strings.ContainsAny(new, " \t,#") {
+		return fmt.Errorf("invalid tag rename %q to %q", old, new)
+	}
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	var changes []journal.Change
+	err = eachTextNote(v, func(e vault.Entry, content string) error {
+		if renamed, ok := notes.RenameTag(content, old, new); ok {
+			changes = append(changes, journal.Change{Path: e.Rel, Before: journal.Contents([]byte(content)), After: journal.Contents([]byte(renamed))})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return fmt.Errorf("no notes are tagged #%s", old)
+	}
+	if _, err := journal.Apply(v.Path, journal.Operation{
+		Kind:        journal.KindTagRename,
+		Description: fmt.Sprintf("rename tag #%s to #%s", old, new),
+		Changes:     changes,
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "✓ Renamed #%s to #%s in %d %s\n", old, new, len(changes), plural(len(changes), "note", "notes"))
+	return nil
+}
+
+// eachTextNote calls fn with the contents of every text note of v
+func eachTextNote(v models.Vault, fn func(e vault.Entry, content string) error) error {
+	entries, err := vault.Notes(v.Path, v.Config)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if !vault.IsText(e.Rel) {
+			continue
+		}
+		data, err := os.ReadFile(e.Path)
+		if err != nil {
+			return err
+		}
+		if err := fn(e, string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,415 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/journal"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/notes"
+	tui "cobra-cli/internal/tui"
+	"cobra-cli/internal/vault"
+)
+
+var (
+	tasksDoneFlag        bool
+	tasksAllFlag         bool
+	tasksOverdueFlag     bool
+	tasksTodayFlag       bool
+	tasksTagFlag         string
+	tasksPersonFlag      string
+	tasksInteractiveFlag bool
+)
+
+// tasksCmd represents the tasks command
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "List the tasks of the vault as an agenda",
+	Long: `List the checkbox items of the notes of the current vault, such as
+
+  - [ ] Send the draft to Ana due:2026-10-20 @ana !high #work
+
+ordered by due date, then priority. Open tasks are listed unless a flag says otherwise:
+
+  noted tasks                  # Open tasks
+  noted tasks --done           # Checked-off tasks
+  noted tasks --all            # Open and checked-off tasks
+  noted tasks --overdue        # Open tasks due before today
+  noted tasks --today          # Open tasks due today
+  noted tasks --tag work       # Tasks tagged #work, or #work/...
+  noted tasks --person ana     # Tasks mentioning @ana
+  noted tasks -i               # Check tasks off in a list
+  noted tasks toggle plan:12   # Check off the task on line 12 of plan.md
+
+Tasks with every: recur, e.g. every:monday, every:day, every:2w or every:3m. Checking
+one off adds its next occurrence below it, due on the first such day after its due date
+that is not in the past. Reopening it removes that occurrence again.
+
+Tasks are checked off in the note itself. The change is recorded in the vault's
+journal; reverse it with 'noted undo'.`,
+	Args:        cobra.NoArgs,
+	Annotations: paletteEntry{Title: "Task agenda"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		filter := taskFilter{
+			done:    tasksDoneFlag,
+			all:     tasksAllFlag,
+			overdue: tasksOverdueFlag,
+			today:   tasksTodayFlag,
+			tag:     strings.ToLower(strings.TrimPrefix(tasksTagFlag, "#")),
+			person:  strings.TrimPrefix(tasksPersonFlag, "@"),
+		}
+		if tasksInteractiveFlag {
+			return browseTasks(cmd.OutOrStdout(), filter)
+		}
+		return listTasks(cmd.OutOrStdout(), filter)
+	},
+}
+
+// tasksToggleCmd represents the tasks toggle command
+var tasksToggleCmd = &cobra.Command{
+	Use:   "toggle <note:line>...",
+	Short: "Check off or reopen tasks",
+	Long: `Check off the open tasks and reopen the checked-off tasks at the given places,
+written as the note and line number 'noted tasks' prints, e.g. projects/plan.md:12.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		v, err := openCurrentVault()
+		if err != nil {
+			return err
+		}
+		toggler := newTaskToggler(v)
+		for _, ref := range args {
+			rel, line, err := parseTaskRef(v, ref)
+			if err != nil {
+				return err
+			}
+			result, err := toggler.toggle(rel, line)
+			if err != nil {
+				return err
+			}
+			if result.done {
+				fmt.Fprintf(cmd.OutOrStdout(), "✓ Checked off %s:%d\n", rel, line)
+				if !result.next.IsZero() {
+					fmt.Fprintf(cmd.OutOrStdout(), "✓ Added the next occurrence, due %s\n", result.next.Format("2006-01-02"))
+				}
+			} else {
+				fmt.Fprintf(cmd.OutOrStdout(), "✓ Reopened %s:%d\n", rel, line)
+				if result.removed {
+					fmt.Fprintln(cmd.OutOrStdout(), "✓ Removed its next occurrence")
+				}
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tasksCmd)
+	tasksCmd.AddCommand(tasksToggleCmd)
+	tasksCmd.Flags().BoolVar(&tasksDoneFlag, "done", false, "List checked-off tasks")
+	tasksCmd.Flags().BoolVar(&tasksAllFlag, "all", false, "List open and checked-off tasks")
+	tasksCmd.Flags().BoolVar(&tasksOverdueFlag, "overdue", false, "List open tasks due before today")
+	tasksCmd.Flags().BoolVar(&tasksTodayFlag, "today", false, "List open tasks due today")
+	tasksCmd.Flags().StringVar(&tasksTagFlag, "tag", "", "List tasks with this tag")
+	tasksCmd.Flags().StringVar(&tasksPersonFlag, "person", "", "List tasks mentioning this @person")
+	tasksCmd.Flags().BoolVarP(&tasksInteractiveFlag, "interactive", "i", false, "Check tasks off in a list")
+	tasksCmd.MarkFlagsMutuallyExclusive("done", "all", "overdue", "today")
+	tasksCmd.RegisterFlagCompletionFunc("tag", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return taskCompletions(func(t vault.Task) []string { return t.Tags })
+	})
+	tasksCmd.RegisterFlagCompletionFunc("person", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return taskCompletions(func(t vault.Task) []string { return t.People })
+	})
+}
+
+// taskFilter selects the tasks 'noted tasks' lists
+type taskFilter struct {
+	done    bool // Checked-off tasks instead of open ones
+	all     bool // Both
+	overdue bool
+	today   bool
+	tag     string
+	person  string
+}
+
+func (f taskFilter) match(t vault.Task, now time.Time) bool {
+	switch {
+	case f.all:
+	case f.done:
+		if !t.Done {
+			return false
+		}
+	case t.Done:
+		return false
+	}
+	if f.overdue && !t.Overdue(now) || f.today && !t.DueOn(now) {
+		return false
+	}
+	if f.tag != "" && !hasTag(t.Tags, f.tag) {
+		return false
+	}
+	if f.person != "" && !containsFold(t.People, f.person) {
+		return false
+	}
+	return true
+}
+
+// hasTag reports whether tags holds tag or a tag nested under it
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag || strings.HasPrefix(t, tag+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// agenda returns the tasks of v matching filter, in agenda order
+func agenda(v models.Vault, filter taskFilter, now time.Time) ([]vault.Task, error) {
+	tasks, err := vault.CollectTasks(v)
+	if err != nil {
+		return nil, err
+	}
+	var matched []vault.Task
+	for _, t := range tasks {
+		if filter.match(t, now) {
+			matched = append(matched, t)
+		}
+	}
+	vault.SortAgenda(matched)
+	return matched, nil
+}
+
+func listTasks(out io.Writer, filter taskFilter) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	tasks, err := agenda(v, filter, now)
+	if err != nil {
+		return err
+	}
+	if len(tasks) == 0 {
+		fmt.Fprintln(out, "No matching tasks.")
+		return nil
+	}
+	for _, t := range tasks {
+		box := "[ ]"
+		if t.Done {
+			box = "[x]"
+		}
+		fmt.Fprintf(out, "%s %s", box, t.Text)
+		if due := dueLabel(t, now); due != "" {
+			fmt.Fprintf(out, "  (%s)", due)
+		}
+		fmt.Fprintf(out, "  %s:%d\n", t.Rel, t.Line)
+	}
+	fmt.Fprintf(out, "\n%d %s\n", len(tasks), plural(len(tasks), "task", "tasks"))
+	return nil
+}
+
+// dueLabel describes when an open task is due
+func dueLabel(t vault.Task, now time.Time) string {
+	if !t.HasDue() || t.Done {
+		return ""
+	}
+	tomorrow := now.AddDate(0, 0, 1)
+	switch {
+	case t.Overdue(now):
+		return "overdue, due " + t.Due.Format("2006-01-02")
+	case t.DueOn(now):
+		return "due today"
+	case t.DueOn(tomorrow):
+		return "due tomorrow"
+	}
+	return "due " + t.Due.Format("2006-01-02")
+}
+
+// browseTasks lists the tasks matching filter in the task list, where they can be
+// checked off
+func browseTasks(out io.Writer, filter taskFilter) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	tasks, err := agenda(v, filter, now)
+	if err != nil {
+		return err
+	}
+	items := make([]tui.TaskItem, len(tasks))
+	for i, t := range tasks {
+		items[i] = tui.TaskItem{Rel: t.Rel, Line: t.Line, Text: t.Text, Done: t.Done, Detail: dueLabel(t, now), Overdue: t.Overdue(now)}
+	}
+	toggler := newTaskToggler(v)
+	result, err := tui.LaunchTaskList(tui.TaskListOptions{
+		Title: "Tasks in " + v.Name,
+		Tasks: items,
+		Toggle: func(t tui.TaskItem) (bool, error) {
+			result, err := toggler.toggle(t.Rel, t.Line)
+			return result.done, err
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error launching task list: %w", err)
+	}
+	if result.Toggled > 0 {
+		fmt.Fprintf(out, "✓ Updated %d %s\n", result.Toggled, plural(result.Toggled, "task", "tasks"))
+	}
+	return nil
+}
+
+// parseTaskRef splits a note:line reference to a task
+func parseTaskRef(v models.Vault, ref string) (string, int, error) {
+	i := strings.LastIndexByte(ref, ':')
+	if i < 0 {
+		return "", 0, fmt.Errorf("%q is not a task: want note:line", ref)
+	}
+	line, err := strconv.Atoi(ref[i+1:])
+	if err != nil || line < 1 {
+		return "", 0, fmt.Errorf("%q is not a task: want note:line", ref)
+	}
+	rel, err := findNote(v, ref[:i])
+	if err != nil {
+		return "", 0, err
+	}
+	return rel, line, nil
+}
+
+// taskToggle is what toggling a task did
+type taskToggle struct {
+	done    bool      // The task is now checked off
+	next    time.Time // Due date of the next occurrence added below a recurring task
+	removed bool      // Reopening a recurring task removed the next occurrence below it
+}
+
+// taskToggler toggles tasks by the lines they were on when listed, making up for the next
+// occurrences of recurring tasks added or removed in between
+type taskToggler struct {
+	v      models.Vault
+	shifts map[string][]lineShift // Lines added or removed in each note since it was listed
+}
+
+// lineShift is a line added (by 1) or removed (by -1) below a listed line of a note
+type lineShift struct {
+	below int
+	by    int
+}
+
+func newTaskToggler(v models.Vault) *taskToggler {
+	return &taskToggler{v: v, shifts: map[string][]lineShift{}}
+}
+
+func (t *taskToggler) toggle(rel string, line int) (taskToggle, error) {
+	actual := line
+	for _, s := range t.shifts[rel] {
+		if s.below < line {
+			actual += s.by
+		}
+	}
+	result, err := toggleTask(t.v, rel, actual)
+	switch {
+	case !result.next.IsZero():
+		t.shifts[rel] = append(t.shifts[rel], lineShift{below: line, by: 1})
+	case result.removed:
+		t.shifts[rel] = append(t.shifts[rel], lineShift{below: line, by: -1})
+	}
+	return result, err
+}
+
+// toggleTask checks off or reopens the task on a line of a note, through the journal.
+// Checking off a recurring task adds its next occurrence below it, unless it is already
+// there, and reopening one takes that occurrence away again while it is still open.
+func toggleTask(v models.Vault, rel string, line int) (taskToggle, error) {
+	var result taskToggle
+	data, err := os.ReadFile(filepath.Join(v.Path, filepath.FromSlash(rel)))
+	if err != nil {
+		return result, err
+	}
+	content := string(data)
+	var task *notes.Task
+	for _, t := range notes.ParseTasks(content) {
+		if t.Line == line {
+			task = &t
+			break
+		}
+	}
+	if task == nil {
+		return result, fmt.Errorf("%s:%d is not a task", rel, line)
+	}
+	updated, err := notes.SetTaskDone(content, line, !task.Done)
+	if err != nil {
+		return result, err
+	}
+	description := fmt.Sprintf("check off task %s:%d", rel, line)
+	switch {
+	case task.Done && task.Every != "":
+		description = fmt.Sprintf("reopen task %s:%d", rel, line)
+		if updated, result.removed, err = notes.UnrepeatTask(updated, line); err != nil {
+			return taskToggle{}, err
+		}
+		if result.removed {
+			description += " and remove its next occurrence"
+		}
+	case task.Done:
+		description = fmt.Sprintf("reopen task %s:%d", rel, line)
+	case task.Every != "":
+		if updated, result.next, err = notes.RepeatTask(updated, line, time.Now()); err != nil {
+			return taskToggle{}, err
+		}
+		if !result.next.IsZero() {
+			description += " and add its next occurrence"
+		}
+	}
+	if _, err := journal.Apply(v.Path, journal.Operation{
+		Kind:        journal.KindEdit,
+		Description: description,
+		Changes:     []journal.Change{{Path: rel, Before: journal.Contents(data), After: journal.Contents([]byte(updated))}},
+	}); err != nil {
+		return taskToggle{}, err
+	}
+	result.done = !task.Done
+	return result, nil
+}
+
+// taskCompletions completes the tags or people of the current vault's tasks
+func taskCompletions(values func(t vault.Task) []string) ([]string, cobra.ShellCompDirective) {
+	v, err := openCurrentVault()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	tasks, err := vault.CollectTasks(v)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	seen := map[string]bool{}
+	var out []string
+	for _, t := range tasks {
+		for _, value := range values(t) {
+			if !seen[value] {
+				seen[value] = true
+				out = append(out, value)
+			}
+		}
+	}
+	sort.Strings(out)
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
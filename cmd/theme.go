@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/theme"
+	tui "cobra-cli/internal/tui"
+)
+
+var themeFromFlag string
+
+// themeCmd represents the theme command
+var themeCmd = &cobra.Command{
+	Use:   "theme",
+	Short: "List, preview and create TUI color themes",
+	Long: `noted's TUIs draw their colors from the active theme, chosen with the layered
+theme setting: 'noted config set theme light', NOTED_THEME or --theme.
+
+Built-in themes are dark, light and high-contrast. Your own themes live as YAML
+files in the themes folder of the config dir; start one with 'noted theme new'.
+Colors follow the terminal's capabilities, and NO_COLOR turns them off.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listThemes(cmd.OutOrStdout())
+	},
+}
+
+// themeListCmd represents the theme list command
+var themeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the available themes",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listThemes(cmd.OutOrStdout())
+	},
+}
+
+// themePreviewCmd represents the theme preview command
+var themePreviewCmd = &cobra.Command{
+	Use:   "preview [name]",
+	Short: "Show the colors of a theme, the active one by default",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t := tui.ActiveTheme()
+		if len(args) == 1 {
+			var err error
+			if t, err = theme.Load(notedApp.ThemesDir(), args[0]); err != nil {
+				return err
+			}
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), tui.RenderThemePreview(t))
+		return nil
+	},
+}
+
+// themeNewCmd represents the theme new command
+var themeNewCmd = &cobra.Command{
+	Use:   "new <name>",
+	Short: "Create a theme file to customize",
+	Long: `Create <name>.yaml in the themes folder, extending --from (the active theme by
+default) with all of its colors spelled out, ready to edit. Activate it with
+'noted config set theme <name>'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return newTheme(cmd.OutOrStdout(), args[0], themeFromFlag)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(themeCmd)
+	themeCmd.AddCommand(themeListCmd, themePreviewCmd, themeNewCmd)
+	themeNewCmd.Flags().StringVar(&themeFromFlag, "from", "", "Theme to start from")
+}
+
+func listThemes(out io.Writer) error {
+	active := tui.ActiveTheme().Name
+	for _, info := range theme.List(notedApp.ThemesDir()) {
+		marker := "  "
+		if info.Name == active {
+			marker = "✓ "
+		}
+		switch {
+		case info.Err != nil:
+			fmt.Fprintf(out, "%s%-16s ✗ %v\n", marker, info.Name, info.Err)
+		case info.Path != "":
+			fmt.Fprintf(out, "%s%-16s %s (%s)\n", marker, info.Name, info.Description, info.Path)
+		default:
+			fmt.Fprintf(out, "%s%-16s %s\n", marker, info.Name, info.Description)
+		}
+	}
+	return nil
+}
+
+func newTheme(out io.Writer, name, from string) error {
+	if name == "" || name != filepath.Base(name) || name[0] == '.' {
+		return fmt.Errorf("invalid theme name %q", name)
+	}
+	base := tui.ActiveTheme()
+	if from != "" {
+		var err error
+		if base, err = theme.Load(notedApp.ThemesDir(), from); err != nil {
+			return err
+		}
+	}
+	data, err := theme.Template(base)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(notedApp.ThemesDir(), 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(notedApp.ThemesDir(), name+".yaml")
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("theme %s already exists", path)
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "✓ Created %s from %s\n", path, base.Name)
+	fmt.Fprintf(out, "  Activate it with 'noted config set theme %s'\n", name)
+	return nil
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	log "github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/journal"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/vault"
+	"cobra-cli/internal/vaultlog"
+)
+
+var (
+	trashRestoreToFlag string
+	trashEmptyAllFlag  bool
+	trashEmptyYesFlag  bool
+)
+
+// trashCmd represents the trash command
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List, restore and empty deleted notes",
+	Long: `Notes deleted with 'noted rm' are moved into the vault's .trash folder along with
+their original path and deletion time. The trash is never searched or indexed.
+
+Trashed notes are removed for good after trash_retention days (30 by default,
+0 keeps them until the trash is emptied), checked by 'noted rm' and the trash
+commands. Set it per vault with 'noted config set --vault settings.trash_retention 90'.
+
+A note removed for good is also dropped from the vault's journal, along with its
+earlier versions, so 'noted undo' cannot bring it back.`,
+	Args:        cobra.NoArgs,
+	Annotations: paletteEntry{Title: "Show trash"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listTrash(cmd.OutOrStdout())
+	},
+}
+
+// trashListCmd represents the trash list command
+var trashListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List the notes in the trash",
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listTrash(cmd.OutOrStdout())
+	},
+}
+
+// trashRestoreCmd represents the trash restore command
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <note|id>",
+	Short: "Restore a note from the trash",
+	Long: `Move a note from the trash back to its original path, or to --to. The note is
+found by its original path or name, in which case the most recently deleted one
+wins, or by the ID shown by 'noted trash list'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return restoreTrash(cmd.OutOrStdout(), args[0], trashRestoreToFlag)
+	},
+}
+
+// trashEmptyCmd represents the trash empty command
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently delete the notes in the trash",
+	Long: `Permanently delete the notes in the trash that are past the retention period.
+With --all, delete everything in the trash. This cannot be undone.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return emptyTrash(cmd.InOrStdin(), cmd.OutOrStdout(), trashEmptyAllFlag, trashEmptyYesFlag)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+	trashCmd.AddCommand(trashListCmd, trashRestoreCmd, trashEmptyCmd)
+	trashRestoreCmd.Flags().StringVar(&trashRestoreToFlag, "to", "", "Restore to this path instead of the original one")
+	trashEmptyCmd.Flags().BoolVar(&trashEmptyAllFlag, "all", false, "Delete everything in the trash, not just expired notes")
+	trashEmptyCmd.Flags().BoolVarP(&trashEmptyYesFlag, "yes", "y", false, "Do not ask for confirmation")
+}
+
+func listTrash(out io.Writer) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	purgeTrash(v)
+	items, err := vault.ListTrash(v.Path)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		fmt.Fprintln(out, "The trash is empty.")
+		return nil
+	}
+	retention := notedApp.Settings().GetInt("trash_retention")
+	for _, item := range items {
+		expires := "kept until emptied"
+		if retention > 0 {
+			left := time.Until(item.DeletedAt.AddDate(0, 0, retention))
+			expires = fmt.Sprintf("expires in %dd", int(left.Hours()/24)+1)
+		}
+		fmt.Fprintf(out, "  %-26s %-12s %-20s %s\n", item.ID, timeAgo(item.DeletedAt), expires, item.Path)
+	}
+	return nil
+}
+
+func restoreTrash(out io.Writer, name, to string) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	purgeTrash(v)
+	items, err := vault.ListTrash(v.Path)
+	if err != nil {
+		return err
+	}
+	item, ok := vault.FindTrash(items, name)
+	if !ok {
+		return fmt.Errorf("%q is not in the trash of vault %s", name, v.Name)
+	}
+	dest := item.Path
+	if to != "" {
+		if dest, err = vaultRel(to); err != nil {
+			return err
+		}
+		if !vault.IsNote(v.Config, dest) {
+			dest += path.Ext(item.Path)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(v.Path, filepath.FromSlash(dest))); !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s already exists. Use --to to restore it elsewhere", dest)
+	}
+	changes, err := vault.RestoreChanges(v.Path, item, dest)
+	if err != nil {
+		return err
+	}
+	if _, err := journal.Apply(v.Path, journal.Operation{
+		Kind:        journal.KindRestore,
+		Description: "restore " + dest + " from the trash",
+		Changes:     changes,
+	}); err != nil {
+		return err
+	}
+	logVaultEvent(v, vaultlog.EventCreate, "note restored from trash", "note", dest, "trash_id", item.ID)
+	fmt.Fprintf(out, "✓ Restored %s\n", dest)
+	return nil
+}
+
+func emptyTrash(in io.Reader, out io.Writer, all, yes bool) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	items, err := vault.ListTrash(v.Path)
+	if err != nil {
+		return err
+	}
+	retention := notedApp.Settings().GetInt("trash_retention")
+	var doomed []vault.TrashItem
+	for _, item := range items {
+		if all || item.Expired(retention, time.Now()) {
+			doomed = append(doomed, item)
+		}
+	}
+	if len(doomed) == 0 {
+		if len(items) > 0 {
+			fmt.Fprintln(out, "Nothing in the trash has expired. Use --all to delete everything.")
+		} else {
+			fmt.Fprintln(out, "The trash is empty.")
+		}
+		return nil
+	}
+	if !yes {
+		fmt.Fprintf(out, "Permanently delete %d %s? [y/N]: ", len(doomed), plural(len(doomed), "note", "notes"))
+		var response string
+		fmt.Fscanln(in, &response)
+		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
+			fmt.Fprintln(out, "Trash left as is.")
+			return nil
+		}
+	}
+	for _, item := range doomed {
+		if err := vault.RemoveTrash(v.Path, item); err != nil {
+			return err
+		}
+		logVaultEvent(v, vaultlog.EventDelete, "note deleted from trash", "note", item.Path, "trash_id", item.ID)
+	}
+	fmt.Fprintf(out, "✓ Permanently deleted %d %s\n", len(doomed), plural(len(doomed), "note", "notes"))
+	return nil
+}
+
+// purgeTrash drops the notes of v that outlived the retention period. Only rm and the
+// trash commands run it, never a command that just reads the vault; failing to purge
+// never fails a command
+func purgeTrash(v models.Vault) {
+	purged, err := vault.PurgeTrash(v.Path, notedApp.Settings().GetInt("trash_retention"), time.Now())
+	if err != nil {
+		log.Warn("could not purge trash", "vault", v.Name, "err", err)
+	}
+	for _, item := range purged {
+		logVaultEvent(v, vaultlog.EventDelete, "expired note deleted from trash", "note", item.Path, "trash_id", item.ID)
+	}
+}
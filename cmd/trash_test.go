@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cobra-cli/internal/app"
+	"cobra-cli/internal/journal"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/vault"
+)
+
+// testVault makes notedApp use a fresh config whose current vault is a new empty folder,
+// and returns that folder
+func testVault(t *testing.T) string {
+	t.Helper()
+	a, err := app.New(app.Options{ConfigDir: t.TempDir(), Flags: rootCmd.PersistentFlags()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := a.SelectVault(models.Vault{Name: "test", Path: dir}); err != nil {
+		t.Fatal(err)
+	}
+	saved := notedApp
+	notedApp = a
+	t.Cleanup(func() { notedApp = saved })
+	return dir
+}
+
+func TestReadCommandsKeepExpiredTrash(t *testing.T) {
+	dir := testVault(t)
+	if err := os.WriteFile(filepath.Join(dir, "old.md"), []byte("old"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	item, changes, err := vault.TrashChanges("old.md", []byte("old"), 0o644, time.Now().AddDate(0, 0, -365))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := journal.Apply(dir, journal.Operation{Kind: journal.KindDelete, Changes: changes}); err != nil {
+		t.Fatal(err)
+	}
+	trashed := func() bool {
+		items, err := vault.ListTrash(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return len(items) == 1 && items[0].ID == item.ID
+	}
+
+	for _, args := range [][]string{{"stats", "--json"}, {"tasks"}, {"recent"}, {"log"}, {"activity", "--json"}} {
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&out)
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("noted %v: %v\n%s", args, err, out.String())
+		}
+		if !trashed() {
+			t.Fatalf("noted %v purged the trash", args)
+		}
+	}
+	if _, err := journal.Undo(dir); err != nil {
+		t.Errorf("the deletion can no longer be undone: %v", err)
+	}
+	if _, err := journal.Redo(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"trash", "list"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if trashed() {
+		t.Error("noted trash list kept a note past the retention period")
+	}
+}
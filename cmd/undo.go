@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"cobra-cli/internal/journal"
+)
+
+var undoListFlag bool
+
+// undoCmd represents the undo command
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last rename, delete, tag rename or bulk edit",
+	Long: `Undo the last change made with 'noted mv', 'noted rm', 'noted tags rename' or
+'noted replace' in the current vault, restoring the original paths and contents
+from the vault's journal. Run it again to undo earlier changes.
+
+Undo refuses to run if a file the change touched has been edited since, so it
+never overwrites newer work. Use --list to see the journal.`,
+	Args:        cobra.NoArgs,
+	Annotations: paletteEntry{Title: "Undo last change"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if undoListFlag {
+			return listJournal(cmd.OutOrStdout())
+		}
+		return replayJournal(cmd.OutOrStdout(), journal.Undo, "Undid")
+	},
+}
+
+// redoCmd represents the redo command
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Redo the last undone change",
+	Long: `Re-apply the last change undone with 'noted undo'. Like undo, it refuses to run
+if a file the change touches has been edited since.
+
+Making a new change after an undo discards what could be redone.`,
+	Args:        cobra.NoArgs,
+	Annotations: paletteEntry{Title: "Redo"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return replayJournal(cmd.OutOrStdout(), journal.Redo, "Redid")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(redoCmd)
+	undoCmd.Flags().BoolVarP(&undoListFlag, "list", "l", false, "List the journal instead of undoing")
+}
+
+func replayJournal(out io.Writer, replay func(dir string) (journal.Operation, error), verb string) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	op, err := replay(v.Path)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "✓ %s: %s\n", verb, op.Description)
+	return nil
+}
+
+func listJournal(out io.Writer) error {
+	v, err := openCurrentVault()
+	if err != nil {
+		return err
+	}
+	j, err := journal.Read(v.Path)
+	if err != nil {
+		return err
+	}
+	if len(j.Operations) == 0 {
+		fmt.Fprintln(out, "The journal is empty.")
+		return nil
+	}
+	for i := len(j.Operations) - 1; i >= 0; i-- {
+		op := j.Operations[i]
+		state := "  "
+		if i >= j.Cursor {
+			state = "↺ " // Undone, can be redone
+		}
+		n := len(op.Changes)
+		fmt.Fprintf(out, "%s%-12s %-10s %s (%d %s)\n", state, timeAgo(op.Time), op.Kind, op.Description, n, plural(n, "file", "files"))
+	}
+	return nil
+}
@@ -2,17 +2,24 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 
+	"cobra-cli/internal/app"
+	"cobra-cli/internal/config"
 	tui "cobra-cli/internal/tui"
 	"cobra-cli/internal/models"
+	"cobra-cli/internal/vault"
+	"cobra-cli/internal/vaultlog"
+	log "github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 )
 
 var openFlag string
+var presetFlag string
 
 // vaultCmd represents the vault command
 var vaultCmd = &cobra.Command{
@@ -24,20 +31,21 @@ var vaultCmd = &cobra.Command{
   noted vault --open <name>      # Open vault by name
   noted vault --open <index>     # Open vault by index (1-based)
   noted vault list               # List all configured vaults
+  noted vault list --group <g>   # List the vaults in a group
+  noted vault group              # Manage vault groups
   noted vault current            # Show current vault
-  noted vault create <path>      # Create new vault at specified path`,
-	Run: func(cmd *cobra.Command, args []string) {
-		initConfigDir()
-		initConfigFile()
-		
+  noted vault create <path>      # Create new vault at specified path
+  noted vault create <path> --preset <name>  # Create a vault from a preset
+  noted vault presets            # List the vault presets`,
+	Annotations: paletteEntry{Title: "Switch vault", Choices: "open"}.annotations(),
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Handle --open flag
 		if openFlag != "" {
-			openVaultByNameOrIndex(openFlag)
-			return
+			return openVaultByNameOrIndex(cmd.OutOrStdout(), openFlag)
 		}
-		
+
 		// No flags, launch interactive TUI
-		launchVaultTUI()
+		return launchVaultTUI(cmd.OutOrStdout())
 	},
 }
 
@@ -45,10 +53,8 @@ var vaultListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all configured vaults",
 	Long:  `Display a list of all configured vaults with their indices and paths.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		initConfigDir()
-		initConfigFile()
-		listVaults()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listVaults(cmd.OutOrStdout(), groupFlag)
 	},
 }
 
@@ -56,22 +62,35 @@ var vaultCurrentCmd = &cobra.Command{
 	Use:   "current",
 	Short: "Show current active vault",
 	Long:  `Display the currently active vault path and name.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		initConfigDir()
-		initConfigFile()
-		showCurrentVault()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showCurrentVault(cmd.OutOrStdout())
 	},
 }
 
 var vaultCreateCmd = &cobra.Command{
 	Use:   "create <path>",
 	Short: "Create a new vault at the specified path",
-	Long:  `Create a new vault at the specified path and set it as the current vault.`,
-	Args:  cobra.ExactArgs(1),
-	Run: func(cmd *cobra.Command, args []string) {
-		initConfigDir()
-		initConfigFile()
-		createVault(args[0])
+	Long: `Create a new vault at the specified path and set it as the current vault.
+
+A new vault is set up from a preset, which creates its folders and starter templates
+and fills in its vault.json. Run 'noted vault presets' to see them. A directory that
+already has a vault.json is registered as is.
+
+If the directory is an Obsidian vault, its attachment folder, templates folder,
+daily notes settings and ignore filters are imported from .obsidian/, and .obsidian
+itself is ignored.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return createVault(cmd.InOrStdin(), cmd.OutOrStdout(), args[0], presetFlag)
+	},
+}
+
+var vaultPresetsCmd = &cobra.Command{
+	Use:   "presets",
+	Short: "List the presets available to new vaults",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		listPresets(cmd.OutOrStdout())
+		return nil
 	},
 }
 
@@ -80,153 +99,242 @@ func init() {
 	vaultCmd.AddCommand(vaultListCmd)
 	vaultCmd.AddCommand(vaultCurrentCmd)
 	vaultCmd.AddCommand(vaultCreateCmd)
+	vaultCmd.AddCommand(vaultPresetsCmd)
 	
 	// Add --open flag
 	vaultCmd.Flags().StringVarP(&openFlag, "open", "o", "", "Open vault by name or index")
+	vaultCmd.RegisterFlagCompletionFunc("open", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var names []string
+		for _, v := range notedApp.Vaults() {
+			names = append(names, v.Name+"\t"+v.Path)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+	addGroupFlag(vaultListCmd)
+	vaultCreateCmd.Flags().StringVarP(&presetFlag, "preset", "p", "", "Preset to set the vault up with ("+strings.Join(vault.PresetNames(), ", ")+")")
+	vaultCreateCmd.RegisterFlagCompletionFunc("preset", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return vault.PresetNames(), cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
-func launchVaultTUI() {
-	vaults := loadVaults()
-	currentVault := notedConfig.GetString("current_vault")
-	selectedVault, err := tui.LaunchVaultTUI(vaults, currentVault)
+func launchVaultTUI(out io.Writer) error {
+	selectedVault, err := runVaultTUI()
 	if err != nil {
-		fmt.Printf("Error selecting vault: %v\n", err)
-		return
+		return err
 	}
 	// Update config
-	if !vaultContains(vaults, selectedVault) {
-		vaults = append(vaults, selectedVault)
-		saveVaults(vaults)
+	err = notedApp.SelectVault(selectedVault)
+	if err != nil {
+		return fmt.Errorf("failed to update config: %w", err)
 	}
-	notedConfig.Set("current_vault", selectedVault.Path)
-	err = notedConfig.WriteConfigAs(configFile)
+	fmt.Fprintf(out, "✓ Vault set to: %s\n", selectedVault.Name)
+	return launchVaultViewer(out, selectedVault)
+}
+
+// runVaultTUI runs the vault TUI, logs the vaults created and deleted in it and saves the
+// directory picker's bookmarks and recent places
+func runVaultTUI() (models.Vault, error) {
+	places := tui.DirectoryPickerOptions{Bookmarks: notedApp.Bookmarks(), Recent: notedApp.RecentDirs()}
+	result, err := tui.RunVaultTUI(notedApp.Vaults(), notedApp.CurrentVault(), places)
 	if err != nil {
-		fmt.Printf("Failed to update config: %v\n", err)
-		return
+		return models.Vault{}, fmt.Errorf("error selecting vault: %w", err)
+	}
+	for _, deleted := range result.Deleted {
+		if v, err := notedApp.OpenVault(deleted); err == nil {
+			logVaultEvent(v, vaultlog.EventDelete, "vault config deleted", "vault", v.Name)
+		}
 	}
-	fmt.Printf("✓ Vault set to: %s\n", selectedVault.Name)
-	launchVaultViewer(selectedVault.Path)
+	if result.BookmarksChanged {
+		if err := notedApp.SetBookmarks(result.Bookmarks); err != nil {
+			log.Warn("could not save bookmarks", "err", err)
+		}
+	}
+	if result.Cancelled {
+		return models.Vault{}, fmt.Errorf("error selecting vault: vault selection cancelled")
+	}
+	if result.Err != nil {
+		return models.Vault{}, fmt.Errorf("error selecting vault: %w", result.Err)
+	}
+	if result.PickedDir != "" {
+		if err := notedApp.AddRecentDir(result.PickedDir); err != nil {
+			log.Warn("could not save recent directory", "err", err)
+		}
+	}
+	selectedVault := models.Vault{Name: result.Name, Path: result.Path}
+	if result.Created != "" {
+		if v, err := notedApp.OpenVault(selectedVault); err == nil {
+			logVaultEvent(v, vaultlog.EventCreate, "vault created", "vault", v.Name, "preset", result.Created)
+		}
+	}
+	return selectedVault, nil
 }
 
-func openVaultByNameOrIndex(input string) {
-	vaults := loadVaults()
+func openVaultByNameOrIndex(out io.Writer, input string) error {
+	vaults := notedApp.Vaults()
 	if len(vaults) == 0 {
-		fmt.Println("No vaults configured. Run 'noted vault' to create one.")
-		return
+		fmt.Fprintln(out, "No vaults configured. Run 'noted vault' to create one.")
+		return nil
 	}
 	var selectedVault *models.Vault
 	if idx, err := strconv.Atoi(input); err == nil {
 		if idx < 1 || idx > len(vaults) {
-			fmt.Printf("Invalid vault index: %d. Valid range: 1-%d\n", idx, len(vaults))
-			return
+			return fmt.Errorf("invalid vault index: %d. Valid range: 1-%d", idx, len(vaults))
 		}
 		selectedVault = &vaults[idx-1]
 	} else {
-		for i := range vaults {
-			if vaults[i].Name == input {
-				selectedVault = &vaults[i]
-				break
-			}
+		if v, ok := models.FindVault(vaults, input); ok {
+			selectedVault = &v
 		}
 		if selectedVault == nil {
-			fmt.Printf("Vault '%s' not found.\n", input)
-			fmt.Println("Available vaults:")
+			fmt.Fprintln(out, "Available vaults:")
 			for i, vault := range vaults {
-				fmt.Printf("  %d. %s (%s)\n", i+1, vault.Name, vault.Path)
+				fmt.Fprintf(out, "  %d. %s (%s)\n", i+1, vault.Name, vault.Path)
 			}
-			return
+			return fmt.Errorf("vault '%s' not found", input)
 		}
 	}
-	notedConfig.Set("current_vault", selectedVault.Path)
-	err := notedConfig.WriteConfigAs(configFile)
+	err := notedApp.SelectVault(*selectedVault)
 	if err != nil {
-		fmt.Printf("Failed to update config: %v\n", err)
-		return
+		return fmt.Errorf("failed to update config: %w", err)
 	}
-	fmt.Printf("✓ Opened vault: %s\n", selectedVault.Name)
-	launchVaultViewer(selectedVault.Path)
+	fmt.Fprintf(out, "✓ Opened vault: %s\n", selectedVault.Name)
+	return launchVaultViewer(out, *selectedVault)
 }
 
-func listVaults() {
-	vaults := loadVaults()
-	currentVault := notedConfig.GetString("current_vault")
+func listVaults(out io.Writer, group string) error {
+	vaults := notedApp.Vaults()
+	currentVault := notedApp.CurrentVault()
+	if group != "" {
+		members, err := notedApp.GroupVaults(group)
+		if err != nil {
+			return err
+		}
+		vaults = members
+	}
 	if len(vaults) == 0 {
-		fmt.Println("No vaults configured. Run 'noted vault' to create one.")
-		return
+		if group != "" {
+			fmt.Fprintf(out, "Group %s has no vaults. Run 'noted vault group add %s <vault>' to add one.\n", group, group)
+			return nil
+		}
+		fmt.Fprintln(out, "No vaults configured. Run 'noted vault' to create one.")
+		return nil
+	}
+	if group != "" {
+		fmt.Fprintf(out, "Vaults in group %s:\n", group)
+	} else {
+		fmt.Fprintln(out, "Configured vaults:")
 	}
-	fmt.Println("Configured vaults:")
 	for i, vault := range vaults {
 		current := ""
 		if vault.Path == currentVault {
 			current = " (current)"
 		}
-		fmt.Printf("  %d. %s%s\n     %s\n", i+1, vault.Name, current, vault.Path)
+		groups := ""
+		if names := notedApp.VaultGroups(vault); len(names) > 0 {
+			groups = " [" + strings.Join(names, ", ") + "]"
+		}
+		fmt.Fprintf(out, "  %d. %s%s%s\n     %s\n", i+1, vault.Name, current, groups, vault.Path)
 	}
+	return nil
 }
 
-func showCurrentVault() {
-	currentVault := notedConfig.GetString("current_vault")
-	vaults := loadVaults()
+func showCurrentVault(out io.Writer) error {
+	currentVault := notedApp.CurrentVault()
+	vaults := notedApp.Vaults()
 	if currentVault == "" {
-		fmt.Println("No current vault set. Run 'noted vault' to select one.")
-		return
+		fmt.Fprintln(out, "No current vault set. Run 'noted vault' to select one.")
+		return nil
 	}
 	for _, vault := range vaults {
 		if vault.Path == currentVault {
-			fmt.Printf("Current vault: %s\n", vault.Name)
-			fmt.Printf("Path: %s\n", vault.Path)
-			return
+			fmt.Fprintf(out, "Current vault: %s\n", vault.Name)
+			fmt.Fprintf(out, "Path: %s\n", vault.Path)
+			return nil
 		}
 	}
-	fmt.Printf("Current vault path: %s (not found in vaults list)\n", currentVault)
+	fmt.Fprintf(out, "Current vault path: %s (not found in vaults list)\n", currentVault)
+	return nil
 }
 
-func createVault(path string) {
+func createVault(in io.Reader, out io.Writer, path, presetName string) error {
 	expanded, err := expandPath(path)
 	if err != nil {
-		fmt.Printf("Error expanding path: %v\n", err)
-		return
+		return fmt.Errorf("error expanding path: %w", err)
+	}
+	preset, err := vault.LookupPreset(vault.DefaultPreset)
+	if presetName != "" {
+		preset, err = vault.LookupPreset(presetName)
+	}
+	if err != nil {
+		return err
 	}
 	if _, err := os.Stat(expanded); os.IsNotExist(err) {
-		fmt.Printf("Directory '%s' does not exist. Create it? [y/N]: ", expanded)
+		fmt.Fprintf(out, "Directory '%s' does not exist. Create it? [y/N]: ", expanded)
 		var response string
-		fmt.Scanln(&response)
+		fmt.Fscanln(in, &response)
 		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-			fmt.Println("Vault creation cancelled.")
-			return
+			fmt.Fprintln(out, "Vault creation cancelled.")
+			return nil
 		}
 		err := os.MkdirAll(expanded, 0o755)
 		if err != nil {
-			fmt.Printf("Failed to create directory: %v\n", err)
-			return
+			return fmt.Errorf("failed to create directory: %w", err)
 		}
-		fmt.Printf("✓ Created directory: %s\n", expanded)
+		fmt.Fprintf(out, "✓ Created directory: %s\n", expanded)
 	}
-	vaults := loadVaults()
 	name := filepath.Base(expanded)
-	newVault := models.Vault{Name: name, Path: expanded}
-	if !vaultContains(vaults, newVault) {
-		vaults = append(vaults, newVault)
-		saveVaults(vaults)
+	if err := app.CheckVaultName(notedApp.Vaults(), models.Vault{Name: name, Path: expanded}); err != nil {
+		return err
+	}
+	if _, err := os.Stat(config.VaultConfigPath(expanded)); err == nil {
+		if presetName != "" {
+			return fmt.Errorf("%s is already a vault; presets only apply to new vaults", expanded)
+		}
+	} else {
+		cfg, imported, err := vault.Create(expanded, name, preset)
+		if err != nil {
+			return fmt.Errorf("failed to create vault: %w", err)
+		}
+		logVaultEvent(models.Vault{Name: name, Path: expanded, Config: cfg}, vaultlog.EventCreate, "vault created",
+			"vault", name, "preset", preset.Name, "obsidian", len(imported) > 0)
+		fmt.Fprintf(out, "✓ Set up %s vault\n", preset.Title)
+		if len(imported) > 0 {
+			fmt.Fprintf(out, "✓ Imported Obsidian settings from %s:\n", vault.ObsidianDir)
+			for _, line := range imported {
+				fmt.Fprintf(out, "    %s\n", line)
+			}
+		}
 	}
-	notedConfig.Set("current_vault", newVault.Path)
-	err = notedConfig.WriteConfigAs(configFile)
+	newVault := models.Vault{Name: name, Path: expanded}
+	err = notedApp.SelectVault(newVault)
 	if err != nil {
-		fmt.Printf("Failed to update config: %v\n", err)
-		return
+		return fmt.Errorf("failed to update config: %w", err)
+	}
+	fmt.Fprintf(out, "✓ Vault created and set as current: %s\n", newVault.Name)
+	return launchVaultViewer(out, newVault)
+}
+
+func listPresets(out io.Writer) {
+	fmt.Fprintln(out, "Vault presets:")
+	for _, p := range vault.Presets {
+		fmt.Fprintf(out, "  %-14s %s\n", p.Name, p.Description)
+		if len(p.Folders) > 0 {
+			fmt.Fprintf(out, "  %-14s folders: %s\n", "", strings.Join(p.Folders, ", "))
+		}
 	}
-	fmt.Printf("✓ Vault created and set as current: %s\n", newVault.Name)
-	launchVaultViewer(newVault.Path)
 }
 
-func launchVaultViewer(vaultPath string) {
-	fmt.Printf("\nLaunching vault viewer for: %s\n", filepath.Base(vaultPath))
-	// TODO: Implement vault viewer TUI
-	// err := tui.LaunchVaultViewer(vaultPath)
-	// if err != nil {
-	// 	fmt.Printf("Error launching vault viewer: %v\n", err)
-	// 	return
-	// }
+// launchVaultViewer browses v when running on a terminal
+func launchVaultViewer(out io.Writer, v models.Vault) error {
+	v, err := notedApp.OpenVault(v)
+	if err != nil {
+		return fmt.Errorf("failed to load vault config: %w", err)
+	}
+	if !isTerminal(out) {
+		return nil
+	}
+	fmt.Fprintf(out, "\nLaunching vault viewer for: %s\n", v.Name)
+	return browseVault(out, v)
 }
 
 // expandPath expands ~ to home directory
@@ -239,4 +347,4 @@ func expandPath(path string) (string, error) {
 		return filepath.Join(home, path[1:]), nil
 	}
 	return path, nil
-}
\ No newline at end of file
+}
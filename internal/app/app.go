@@ -0,0 +1,330 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	log "github.com/charmbracelet/log"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"cobra-cli/internal/config"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/theme"
+	"cobra-cli/internal/vault"
+	"cobra-cli/internal/vaultlog"
+)
+
+// App is the state shared by every noted command: where the config lives, the
+// global config itself and the flags that override layered settings.
+// It is built once per process by New and never prints or exits; anything the
+// user should hear about is collected in Notices.
+type App struct {
+	ConfigDir  string
+	ConfigFile string
+	Config     *viper.Viper
+	Flags      *pflag.FlagSet
+	Notices    []string
+
+	vaultLayer *vaultLayer // Settings of the current vault, read on first use
+}
+
+// vaultLayer is the settings layer read from the vault.json of the vault at path.
+type vaultLayer struct {
+	path     string
+	settings map[string]any
+	err      error
+	warned   bool
+}
+
+// Options control how New locates the config.
+type Options struct {
+	ConfigDir string         // Overrides $XDG_CONFIG_HOME/noted, mainly for tests and embedding
+	Flags     *pflag.FlagSet // Flags forming the highest settings layer, may be nil
+}
+
+// New resolves the config directory, creates a default config.yaml if there is none,
+// migrates it to the current schema and binds the settings layers.
+func New(opts Options) (*App, error) {
+	a := &App{ConfigDir: opts.ConfigDir, Flags: opts.Flags}
+	if a.ConfigDir == "" {
+		dir, err := DefaultConfigDir()
+		if err != nil {
+			return nil, err
+		}
+		a.ConfigDir = dir
+	}
+	if err := a.initConfigDir(); err != nil {
+		return nil, err
+	}
+	if err := a.initConfigFile(); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// DefaultConfigDir returns $XDG_CONFIG_HOME/noted, falling back to ~/.config/noted.
+func DefaultConfigDir() (string, error) {
+	xdgConfig := os.Getenv("XDG_CONFIG_HOME")
+	if xdgConfig == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not determine home directory: %w", err)
+		}
+		xdgConfig = filepath.Join(home, ".config")
+	}
+	return filepath.Join(xdgConfig, "noted"), nil
+}
+
+func (a *App) initConfigDir() error {
+	if _, err := os.Stat(a.ConfigDir); os.IsNotExist(err) {
+		if err := os.MkdirAll(a.ConfigDir, 0o755); err != nil {
+			return fmt.Errorf("failed to create config directory: %w", err)
+		}
+		a.notify("Created config directory at %s", a.ConfigDir)
+	}
+	return nil
+}
+
+func (a *App) initConfigFile() error {
+	a.ConfigFile = filepath.Join(a.ConfigDir, "config.yaml")
+	a.Config = viper.New()
+	a.Config.SetConfigFile(a.ConfigFile)
+	a.Config.SetConfigType("yaml")
+
+	if _, err := os.Stat(a.ConfigFile); os.IsNotExist(err) {
+		err := config.WriteGlobalDoc(a.ConfigFile, map[string]any{
+			config.SchemaVersionKey: config.GlobalSchemaVersion,
+			"vaults":                []string{},
+			"current_vault":         "",
+			"templates_dir":         "",
+			"other_settings":        map[string]any{},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to write default config: %w", err)
+		}
+		a.notify("Initialized new config at %s", a.ConfigFile)
+	}
+
+	report, err := config.MigrateGlobalFile(a.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("failed to migrate config: %w", err)
+	}
+	a.noteMigration(report)
+	if err := a.Config.ReadInConfig(); err != nil {
+		return fmt.Errorf("failed to read config: %w", err)
+	}
+	if err := config.BindLayers(a.Config, a.Flags); err != nil {
+		return fmt.Errorf("failed to bind settings: %w", err)
+	}
+	return nil
+}
+
+func (a *App) notify(format string, args ...any) {
+	a.Notices = append(a.Notices, fmt.Sprintf(format, args...))
+}
+
+// noteMigration records a notice when a config file was upgraded to a newer schema.
+func (a *App) noteMigration(report config.MigrationReport) {
+	if !report.Changed() {
+		return
+	}
+	a.notify("Upgraded %s from schema v%d to v%d (backup: %s)", report.Path, report.From, report.To, report.Backup)
+	for _, change := range report.Changes {
+		a.notify("  - %s", change)
+	}
+}
+
+// DrainNotices returns the pending notices and clears them.
+func (a *App) DrainNotices() []string {
+	notices := a.Notices
+	a.Notices = nil
+	return notices
+}
+
+// CurrentVault returns the path of the active vault, or "" if none is selected.
+func (a *App) CurrentVault() string {
+	return a.Config.GetString("current_vault")
+}
+
+// Vaults returns the registered vaults.
+func (a *App) Vaults() []models.Vault {
+	return decodeVaults(a.Config.Get("vaults"))
+}
+
+// SelectVault registers v if it is new and makes it the current vault. The registry is
+// merged with the copy on disk, so vaults added by another noted process are kept. A new
+// vault whose name is taken by another one, ignoring case, is refused.
+func (a *App) SelectVault(v models.Vault) error {
+	var merged []models.Vault
+	err := config.UpdateGlobalDoc(a.ConfigFile, func(doc map[string]any) error {
+		merged = decodeVaults(doc["vaults"])
+		if !VaultContains(merged, v) {
+			if err := CheckVaultName(merged, v); err != nil {
+				return err
+			}
+			merged = append(merged, v)
+		}
+		doc["vaults"] = encodeVaults(merged)
+		doc["current_vault"] = v.Path
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.Config.Set("vaults", encodeVaults(merged))
+	a.Config.Set("current_vault", v.Path)
+	return nil
+}
+
+// VaultConfig loads and migrates the vault.json of the vault at path.
+func (a *App) VaultConfig(path string) (models.VaultConfig, error) {
+	cfg, report, err := config.ReadVault(path)
+	if err != nil {
+		return cfg, err
+	}
+	a.noteMigration(report)
+	return cfg, nil
+}
+
+// Settings resolves layered settings for the current vault:
+// defaults < config.yaml < vault.json < NOTED_* env < flags.
+// A vault.json that cannot be read is left out, with a notice added once.
+func (a *App) Settings() config.Resolver {
+	r := config.Resolver{Global: a.Config, Flags: a.Flags}
+	settings, err := a.VaultSettings()
+	if err != nil && !a.vaultLayer.warned {
+		a.notify("Ignoring the vault settings: %v", err)
+		a.vaultLayer.warned = true
+	}
+	r.Vault = settings
+	return r
+}
+
+// VaultSettings returns the settings of the current vault's vault.json, or nil if it has
+// none. The file is read once, and again only after the current vault changes.
+func (a *App) VaultSettings() (map[string]any, error) {
+	vaultPath := a.CurrentVault()
+	if a.vaultLayer != nil && a.vaultLayer.path == vaultPath {
+		return a.vaultLayer.settings, a.vaultLayer.err
+	}
+	a.vaultLayer = &vaultLayer{path: vaultPath}
+	if vaultPath == "" {
+		return nil, nil
+	}
+	_, err := os.Stat(config.VaultConfigPath(vaultPath))
+	if err == nil {
+		var cfg models.VaultConfig
+		cfg, err = a.VaultConfig(vaultPath)
+		a.vaultLayer.settings = cfg.Settings
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		a.vaultLayer.settings = nil
+		a.vaultLayer.err = fmt.Errorf("vault %s: %w", vaultPath, err)
+	}
+	return a.vaultLayer.settings, a.vaultLayer.err
+}
+
+// OpenVault fills in v.Config from the vault's vault.json, or with the defaults when
+// the vault has none yet.
+func (a *App) OpenVault(v models.Vault) (models.Vault, error) {
+	v.VaultConfigPath = config.VaultConfigPath(v.Path)
+	if _, err := os.Stat(v.VaultConfigPath); os.IsNotExist(err) {
+		v.Config = vault.DefaultConfig(v.Path, v.Name)
+		return v, nil
+	}
+	cfg, err := a.VaultConfig(v.Path)
+	if err != nil {
+		return v, fmt.Errorf("vault %s: %w", v.Name, err)
+	}
+	v.Config = cfg
+	return v, nil
+}
+
+// ThemesDir returns the folder holding user theme files.
+func (a *App) ThemesDir() string {
+	return theme.Dir(a.ConfigDir)
+}
+
+// Theme loads the theme named by the layered theme setting.
+func (a *App) Theme() (theme.Theme, error) {
+	return theme.Load(a.ThemesDir(), a.Settings().GetString("theme"))
+}
+
+// KeyOverrides returns the keys bound to TUI actions in config.yaml, keyed by action,
+// which replace those of the configured keymap.
+func (a *App) KeyOverrides() map[string][]string {
+	return a.Config.GetStringMapStringSlice("keys")
+}
+
+// VaultLog returns the logger for the log file of v, whose config must be loaded. Its level
+// and rotation follow the layered log_level, log_max_size and log_keep settings.
+func (a *App) VaultLog(v models.Vault) *vaultlog.Logger {
+	settings := a.Settings()
+	level, err := log.ParseLevel(settings.GetString("log_level"))
+	if err != nil {
+		level = log.InfoLevel
+	}
+	return &vaultlog.Logger{
+		Path:    v.Config.LogPath,
+		Level:   level,
+		MaxSize: int64(settings.GetInt("log_max_size")) * 1024,
+		Keep:    settings.GetInt("log_keep"),
+	}
+}
+
+// VaultContains reports whether vaults already holds a vault at v's path.
+func VaultContains(vaults []models.Vault, v models.Vault) bool {
+	for _, existing := range vaults {
+		if existing.Path == v.Path {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckVaultName returns an error if a vault other than v in vaults has v's name, ignoring
+// case. Vault names must be unique: groups, cross-vault links and exports refer to vaults
+// by name.
+func CheckVaultName(vaults []models.Vault, v models.Vault) error {
+	if other, ok := models.FindVault(vaults, v.Name); ok && other.Path != v.Path {
+		return fmt.Errorf("a vault named %s is already registered at %s", other.Name, other.Path)
+	}
+	return nil
+}
+
+// decodeVaults reads the vault registry, stored either as a JSON string or a YAML list.
+func decodeVaults(raw any) []models.Vault {
+	var vaults []models.Vault
+	switch v := raw.(type) {
+	case string:
+		_ = json.Unmarshal([]byte(v), &vaults)
+	case []any:
+		for _, item := range v {
+			if m, ok := item.(map[string]any); ok {
+				name, _ := m["Name"].(string)
+				path, _ := m["Path"].(string)
+				vaults = append(vaults, models.Vault{Name: name, Path: path})
+			}
+		}
+	}
+	return vaults
+}
+
+// registryEntry is what the registry stores per vault; the config itself lives in vault.json.
+type registryEntry struct {
+	Name string
+	Path string
+}
+
+func encodeVaults(vaults []models.Vault) string {
+	entries := make([]registryEntry, len(vaults))
+	for i, v := range vaults {
+		entries[i] = registryEntry{Name: v.Name, Path: v.Path}
+	}
+	b, _ := json.Marshal(entries)
+	return string(b)
+}
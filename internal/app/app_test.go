@@ -0,0 +1,88 @@
+package app
+
+import (
+	"os"
+	"testing"
+
+	"cobra-cli/internal/config"
+	"cobra-cli/internal/models"
+)
+
+func TestVaultSettings(t *testing.T) {
+	if editor, ok := os.LookupEnv("NOTED_EDITOR"); ok {
+		t.Setenv("NOTED_EDITOR", editor) // Restored after the test
+		os.Unsetenv("NOTED_EDITOR")
+	}
+	a, err := New(Options{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	if err := a.SelectVault(models.Vault{Name: "test", Path: dir}); err != nil {
+		t.Fatal(err)
+	}
+	a.DrainNotices()
+	tests := []struct {
+		name    string
+		file    string // vault.json, or "" for none
+		want    string
+		wantErr bool
+	}{
+		{name: "no vault.json", want: "vim"},
+		{name: "settings", file: `{"schema_version": 1, "name": "test", "settings": {"editor": "nano"}}`, want: "nano"},
+		{name: "malformed", file: `{"settings": `, want: "vim", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(config.VaultConfigPath(dir))
+			if tt.file != "" {
+				if err := os.WriteFile(config.VaultConfigPath(dir), []byte(tt.file), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			a.vaultLayer = nil
+			if _, err := a.VaultSettings(); (err != nil) != tt.wantErr {
+				t.Errorf("VaultSettings() error = %v, want error %v", err, tt.wantErr)
+			}
+			a.Config.Set("editor", "vim")
+			if got := a.Settings().GetString("editor"); got != tt.want {
+				t.Errorf("editor = %q, want %q", got, tt.want)
+			}
+			if notices := a.DrainNotices(); (len(notices) > 0) != tt.wantErr {
+				t.Errorf("notices = %q, want one only for an unreadable vault.json", notices)
+			}
+		})
+	}
+}
+
+func TestSelectVaultRejectsDuplicateNames(t *testing.T) {
+	a, err := New(Options{ConfigDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	notes := models.Vault{Name: "Notes", Path: "/vaults/notes"}
+	tests := []struct {
+		name    string
+		v       models.Vault
+		wantErr bool
+	}{
+		{name: "new", v: notes},
+		{name: "again", v: notes},
+		{name: "same name", v: models.Vault{Name: "Notes", Path: "/other/notes"}, wantErr: true},
+		{name: "other case", v: models.Vault{Name: "notes", Path: "/other/notes"}, wantErr: true},
+		{name: "other name", v: models.Vault{Name: "work", Path: "/vaults/work"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := a.SelectVault(tt.v); (err != nil) != tt.wantErr {
+				t.Errorf("SelectVault() error = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+	if got := len(a.Vaults()); got != 2 {
+		t.Errorf("%d vaults registered, want 2", got)
+	}
+	if v, err := a.FindVault("NOTES"); err != nil || v.Path != notes.Path {
+		t.Errorf("FindVault(NOTES) = %v, %v", v, err)
+	}
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cobra-cli/internal/config"
+	"cobra-cli/internal/models"
+)
+
+// Groups returns the named vault groups from the global config, keyed by group name,
+// each listing the names of its member vaults.
+func (a *App) Groups() map[string][]string {
+	return decodeGroups(a.Config.Get("groups"))
+}
+
+// GroupNames returns the group names in sorted order.
+func (a *App) GroupNames() []string {
+	var names []string
+	for name := range a.Groups() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// VaultGroups returns the sorted names of the groups v belongs to.
+func (a *App) VaultGroups(v models.Vault) []string {
+	var names []string
+	for name, members := range a.Groups() {
+		for _, m := range members {
+			if m == v.Name {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GroupVaults resolves the members of a group to registered vaults.
+func (a *App) GroupVaults(group string) ([]models.Vault, error) {
+	members, ok := a.Groups()[normalizeGroup(group)]
+	if !ok {
+		return nil, fmt.Errorf("vault group %q not found", group)
+	}
+	var vaults []models.Vault
+	for _, name := range members {
+		v, err := a.FindVault(name)
+		if err != nil {
+			return nil, fmt.Errorf("group %q: %w", group, err)
+		}
+		vaults = append(vaults, v)
+	}
+	return vaults, nil
+}
+
+// FindVault looks up a registered vault by name, case-insensitively.
+func (a *App) FindVault(name string) (models.Vault, error) {
+	if v, ok := models.FindVault(a.Vaults(), name); ok {
+		return v, nil
+	}
+	return models.Vault{}, fmt.Errorf("vault '%s' not found", name)
+}
+
+// AddToGroup adds registered vaults to a group, creating the group if needed.
+func (a *App) AddToGroup(group string, vaultNames ...string) error {
+	// Members are kept under their registered names
+	names := make([]string, len(vaultNames))
+	for i, name := range vaultNames {
+		v, err := a.FindVault(name)
+		if err != nil {
+			return err
+		}
+		names[i] = v.Name
+	}
+	return a.updateGroups(func(groups map[string][]string) error {
+		g := normalizeGroup(group)
+		for _, name := range names {
+			if !containsString(groups[g], name) {
+				groups[g] = append(groups[g], name)
+			}
+		}
+		return nil
+	})
+}
+
+// RemoveFromGroup removes vaults from a group. An emptied group is kept.
+func (a *App) RemoveFromGroup(group string, vaultNames ...string) error {
+	return a.updateGroups(func(groups map[string][]string) error {
+		g := normalizeGroup(group)
+		members, ok := groups[g]
+		if !ok {
+			return fmt.Errorf("vault group %q not found", group)
+		}
+		var kept []string
+		for _, m := range members {
+			if !containsString(vaultNames, m) {
+				kept = append(kept, m)
+			}
+		}
+		groups[g] = kept
+		return nil
+	})
+}
+
+// DeleteGroup removes a group; the vaults themselves are untouched.
+func (a *App) DeleteGroup(group string) error {
+	return a.updateGroups(func(groups map[string][]string) error {
+		g := normalizeGroup(group)
+		if _, ok := groups[g]; !ok {
+			return fmt.Errorf("vault group %q not found", group)
+		}
+		delete(groups, g)
+		return nil
+	})
+}
+
+func (a *App) updateGroups(fn func(groups map[string][]string) error) error {
+	var updated map[string][]string
+	err := config.UpdateGlobalDoc(a.ConfigFile, func(doc map[string]any) error {
+		updated = decodeGroups(doc["groups"])
+		if err := fn(updated); err != nil {
+			return err
+		}
+		doc["groups"] = updated
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.Config.Set("groups", updated)
+	return nil
+}
+
+// normalizeGroup lower-cases group names, matching how viper reports map keys.
+func normalizeGroup(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func decodeGroups(raw any) map[string][]string {
+	groups := map[string][]string{}
+	switch g := raw.(type) {
+	case map[string][]string:
+		for name, members := range g {
+			groups[normalizeGroup(name)] = append([]string(nil), members...)
+		}
+	case map[string]any:
+		for name, members := range g {
+			var names []string
+			switch m := members.(type) {
+			case []any:
+				for _, item := range m {
+					if s, ok := item.(string); ok {
+						names = append(names, s)
+					}
+				}
+			case []string:
+				names = append(names, m...)
+			}
+			groups[normalizeGroup(name)] = names
+		}
+	}
+	return groups
+}
+
+// containsString reports whether slice holds s, ignoring case as vault names do.
+func containsString(slice []string, s string) bool {
+	for _, v := range slice {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package app
+
+import "cobra-cli/internal/config"
+
+// MaxRecentDirs is how many recently chosen directories the directory picker remembers.
+const MaxRecentDirs = 10
+
+// Bookmarks returns the directories bookmarked in the directory picker.
+func (a *App) Bookmarks() []string {
+	return a.Config.GetStringSlice("bookmarks")
+}
+
+// SetBookmarks replaces the bookmarked directories.
+func (a *App) SetBookmarks(dirs []string) error {
+	return a.updateList("bookmarks", func([]string) []string { return dirs })
+}
+
+// RecentDirs returns the directories recently chosen in the directory picker, most
+// recent first.
+func (a *App) RecentDirs() []string {
+	return a.Config.GetStringSlice("recent_dirs")
+}
+
+// AddRecentDir moves dir to the front of the recent directories, dropping the oldest
+// beyond MaxRecentDirs.
+func (a *App) AddRecentDir(dir string) error {
+	return a.updateList("recent_dirs", func(recent []string) []string {
+		updated := []string{dir}
+		for _, d := range recent {
+			if d != dir && len(updated) < MaxRecentDirs {
+				updated = append(updated, d)
+			}
+		}
+		return updated
+	})
+}
+
+// updateList rewrites a string list in config.yaml from its copy on disk, so lists
+// changed by another noted process are not clobbered.
+func (a *App) updateList(key string, fn func(list []string) []string) error {
+	var updated []string
+	err := config.UpdateGlobalDoc(a.ConfigFile, func(doc map[string]any) error {
+		var list []string
+		switch v := doc[key].(type) {
+		case []any:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					list = append(list, s)
+				}
+			}
+		case []string:
+			list = v
+		}
+		updated = fn(list)
+		if len(updated) == 0 {
+			delete(doc, key)
+		} else {
+			doc[key] = updated
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.Config.Set(key, updated)
+	return nil
+}
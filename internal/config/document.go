@@ -0,0 +1,154 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"cobra-cli/internal/models"
+)
+
+// ReadGlobalDoc decodes config.yaml into a generic document for key-level edits.
+func ReadGlobalDoc(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return yamlCodec{}.decode(data)
+}
+
+// WriteGlobalDoc replaces config.yaml with doc.
+func WriteGlobalDoc(path string, doc map[string]any) error {
+	return withLock(path, func() error {
+		return writeGlobalDoc(path, doc)
+	})
+}
+
+// UpdateGlobalDoc re-reads config.yaml under the file lock, applies fn and writes it back.
+// Callers change only the keys they own, so concurrent edits to other keys survive.
+func UpdateGlobalDoc(path string, fn func(doc map[string]any) error) error {
+	return withLock(path, func() error {
+		doc, err := ReadGlobalDoc(path)
+		if err != nil {
+			return err
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+		return writeGlobalDoc(path, doc)
+	})
+}
+
+func writeGlobalDoc(path string, doc map[string]any) error {
+	out, err := yamlCodec{}.encode(doc)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(path, out, 0o644)
+}
+
+// ReadVaultDoc decodes the vault.json in dir into a generic document for key-level edits.
+func ReadVaultDoc(dir string) (map[string]any, error) {
+	if _, _, err := ReadVault(dir); err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(VaultConfigPath(dir))
+	if err != nil {
+		return nil, err
+	}
+	return jsonCodec{}.decode(data)
+}
+
+// UpdateVaultDoc re-reads the vault.json in dir under the file lock, applies fn,
+// validates the result against models.VaultConfig and writes the document back, keeping
+// keys the struct does not know. ModifiedAt is bumped so the change is visible in the
+// vault details.
+func UpdateVaultDoc(dir string, fn func(doc map[string]any) error) error {
+	return withLock(VaultConfigPath(dir), func() error {
+		if _, err := migrateVaultFile(VaultConfigPath(dir)); err != nil {
+			return err
+		}
+		data, err := os.ReadFile(VaultConfigPath(dir))
+		if err != nil {
+			return err
+		}
+		doc, err := jsonCodec{}.decode(data)
+		if err != nil {
+			return err
+		}
+		if err := fn(doc); err != nil {
+			return err
+		}
+		b, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		var cfg models.VaultConfig
+		if err := json.Unmarshal(b, &cfg); err != nil {
+			return fmt.Errorf("invalid vault config: %w", err)
+		}
+		doc["modified_at"] = time.Now()
+		out, err := jsonCodec{}.encode(doc)
+		if err != nil {
+			return err
+		}
+		return WriteFileAtomic(VaultConfigPath(dir), out, 0o644)
+	})
+}
+
+// ReplaceFile swaps in new contents for a config file edited out of band, but only if
+// the file still holds expected; otherwise another process changed it in the meantime.
+func ReplaceFile(path string, expected, contents []byte) error {
+	return withLock(path, func() error {
+		current, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if string(current) != string(expected) {
+			return fmt.Errorf("%s was changed by another process while it was being edited", path)
+		}
+		return WriteFileAtomic(path, contents, 0o644)
+	})
+}
+
+// ValidateGlobalFile checks that path holds a config.yaml this build can load.
+func ValidateGlobalFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	doc, err := yamlCodec{}.decode(data)
+	if err != nil {
+		return err
+	}
+	return checkVersion(doc, GlobalSchemaVersion)
+}
+
+// ValidateVaultFile checks that path holds a vault.json this build can load.
+func ValidateVaultFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	doc, err := jsonCodec{}.decode(data)
+	if err != nil {
+		return err
+	}
+	if err := checkVersion(doc, VaultSchemaVersion); err != nil {
+		return err
+	}
+	var cfg models.VaultConfig
+	return json.Unmarshal(data, &cfg)
+}
+
+func checkVersion(doc map[string]any, current int) error {
+	v, err := schemaVersion(doc)
+	if err != nil {
+		return err
+	}
+	if v > current {
+		return fmt.Errorf("schema version %d is newer than the supported version %d", v, current)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateVaultDoc(t *testing.T) {
+	dir := t.TempDir()
+	original := `{"schema_version": 1, "name": "notes", "settings": {}, "metadata": {}, "supported_types": [], "ignore_patterns": [], "plugin": {"sync": true}}`
+	if err := os.WriteFile(VaultConfigPath(dir), []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	err := UpdateVaultDoc(dir, func(doc map[string]any) error {
+		SetPath(doc, "settings.editor", "nano")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(VaultConfigPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]any{"sync": true}; !reflect.DeepEqual(doc["plugin"], want) {
+		t.Errorf("plugin = %v, want the unknown key kept as %v", doc["plugin"], want)
+	}
+	if editor, _ := GetPath(doc, "settings.editor"); editor != "nano" {
+		t.Errorf("settings.editor = %v, want nano", editor)
+	}
+	if _, ok := doc["modified_at"]; !ok {
+		t.Error("modified_at was not bumped")
+	}
+
+	// A document the vault config cannot hold is refused and the file left as it was
+	err = UpdateVaultDoc(dir, func(doc map[string]any) error {
+		doc["name"] = []any{"not", "a", "name"}
+		return nil
+	})
+	if err == nil {
+		t.Error("UpdateVaultDoc() accepted a list as the vault name")
+	}
+	after, err := os.ReadFile(VaultConfigPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(after) != string(data) {
+		t.Errorf("refused update changed vault.json to %s", after)
+	}
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// EnvPrefix prefixes the environment variables that override settings, e.g. NOTED_EDITOR.
+const EnvPrefix = "NOTED"
+
+// Layer identifies where an effective setting came from, in increasing order of priority.
+type Layer int
+
+const (
+	LayerDefault Layer = iota
+	LayerGlobal
+	LayerVault
+	LayerEnv
+	LayerFlag
+)
+
+func (l Layer) String() string {
+	switch l {
+	case LayerGlobal:
+		return "global"
+	case LayerVault:
+		return "vault"
+	case LayerEnv:
+		return "env"
+	case LayerFlag:
+		return "flag"
+	}
+	return "default"
+}
+
+// Setting is a key that resolves through every layer: built-in default, config.yaml,
+// the current vault's settings map, NOTED_* environment variables and command-line flags.
+type Setting struct {
+	Key         string
+	Type        ValueType
+	Default     func() any
+	Flag        string // Name of the persistent flag that overrides the setting, if any
+	Description string
+}
+
+// Settings lists the layered settings. In config.yaml they live at the top level,
+// in vault.json under "settings".
+var Settings = []Setting{
+	{Key: "editor", Type: TypeString, Default: defaultEditor, Flag: "editor", Description: "Command used to open notes and config files"},
+	{Key: "date_format", Type: TypeString, Default: constant("2006-01-02"), Description: "Go time layout used for dates in note names"},
+	{Key: "daily_folder", Type: TypeString, Default: constant(""), Description: "Folder, relative to the vault root, that holds daily notes"},
+	{Key: "daily_template", Type: TypeString, Default: constant(""), Description: "Template for daily notes, relative to the vault root"},
+	{Key: "attachment_folder", Type: TypeString, Default: constant(""), Description: "Folder, relative to the vault root, for attachments"},
+	{Key: "log_level", Type: TypeString, Default: constant("info"), Flag: "log-level", Description: "Minimum level of log output (debug, info, warn, error)"},
+	{Key: "log_max_size", Type: TypeInt, Default: constant(1024), Description: "Size in KiB at which the vault log is rotated, 0 to never rotate"},
+	{Key: "log_keep", Type: TypeInt, Default: constant(3), Description: "Number of rotated vault logs to keep"},
+	{Key: "theme", Type: TypeString, Default: constant("dark"), Flag: "theme", Description: "Color theme of the TUI: dark, light, high-contrast or a file in the themes folder"},
+	{Key: "keymap", Type: TypeString, Default: constant("default"), Flag: "keymap", Description: "Key bindings of the TUI: default, vim or emacs, with per-action overrides under keys"},
+	{Key: "trash_retention", Type: TypeInt, Default: constant(30), Description: "Days deleted notes stay in the vault's trash, 0 to keep them until emptied"},
+	{Key: "remind_time", Type: TypeString, Default: constant("09:00"), Description: "Time of day tasks are reminded of on their due date, unless they have a remind:HH:MM"},
+	{Key: "remind_command", Type: TypeString, Default: constant(""), Description: "Shell command 'noted remind' runs for each due task, with its text as last argument; empty to print them"},
+}
+
+func init() {
+	for _, s := range Settings {
+		GlobalKeys = append(GlobalKeys, Key{Name: s.Key, Type: s.Type, Description: s.Description})
+	}
+}
+
+func constant(v any) func() any {
+	return func() any { return v }
+}
+
+// defaultEditor follows the usual $VISUAL, $EDITOR, vi convention.
+func defaultEditor() any {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if e := os.Getenv(env); e != "" {
+			return e
+		}
+	}
+	return "vi"
+}
+
+// LookupSetting finds a layered setting by key.
+func LookupSetting(key string) (Setting, bool) {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return Setting{}, false
+}
+
+// EnvName returns the environment variable that overrides key.
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// BindLayers registers the defaults, environment variables and flags of every
+// setting on v, so v.Get resolves everything except the vault layer.
+func BindLayers(v *viper.Viper, flags *pflag.FlagSet) error {
+	v.SetEnvPrefix(EnvPrefix)
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	for _, s := range Settings {
+		v.SetDefault(s.Key, s.Default())
+		if err := v.BindEnv(s.Key); err != nil {
+			return err
+		}
+		if s.Flag == "" || flags == nil {
+			continue
+		}
+		if f := flags.Lookup(s.Flag); f != nil {
+			if err := v.BindPFlag(s.Key, f); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Resolver resolves settings through all layers. Global is the viper instance
+// prepared by BindLayers; Vault is the current vault's settings map, if any.
+type Resolver struct {
+	Global *viper.Viper
+	Vault  map[string]any
+	Flags  *pflag.FlagSet
+}
+
+// LayerValue is the value a single layer holds for a setting.
+type LayerValue struct {
+	Layer  Layer
+	Source string // Where the layer reads the value from, e.g. NOTED_EDITOR
+	Value  any
+	Set    bool
+}
+
+// Resolution explains how a setting got its effective value.
+type Resolution struct {
+	Setting Setting
+	Value   any
+	From    Layer
+	Layers  []LayerValue // Highest priority first
+}
+
+// Get returns the effective value of key. Flags, env and the global file are
+// resolved by viper; the vault layer is slotted in between env and global.
+func (r Resolver) Get(key string) any {
+	s, ok := LookupSetting(key)
+	if !ok {
+		return nil
+	}
+	if !r.flagLayer(s).Set && !r.envLayer(s).Set {
+		if lv := r.vaultLayer(s); lv.Set {
+			return lv.Value
+		}
+	}
+	if r.Global == nil {
+		return s.Default()
+	}
+	return r.Global.Get(key)
+}
+
+// GetString returns the effective value of key as text.
+func (r Resolver) GetString(key string) string {
+	return FormatValue(r.Get(key))
+}
+
+// GetInt returns the effective value of key as an integer, or 0 if it is not one.
+func (r Resolver) GetInt(key string) int {
+	n, _ := strconv.Atoi(r.GetString(key))
+	return n
+}
+
+// Explain resolves key and records what every layer holds for it.
+func (r Resolver) Explain(key string) (Resolution, error) {
+	s, ok := LookupSetting(key)
+	if !ok {
+		return Resolution{}, fmt.Errorf("%q is not a layered setting", key)
+	}
+	layers := []LayerValue{r.flagLayer(s), r.envLayer(s), r.vaultLayer(s), r.globalLayer(s)}
+	layers = append(layers, LayerValue{Layer: LayerDefault, Source: "built-in", Value: s.Default(), Set: true})
+
+	res := Resolution{Setting: s, Layers: layers}
+	for _, l := range layers {
+		if l.Set {
+			res.Value, res.From = l.Value, l.Layer
+			break
+		}
+	}
+	return res, nil
+}
+
+func (r Resolver) flagLayer(s Setting) LayerValue {
+	lv := LayerValue{Layer: LayerFlag}
+	if s.Flag == "" {
+		return lv
+	}
+	lv.Source = "--" + s.Flag
+	if r.Flags == nil {
+		return lv
+	}
+	if f := r.Flags.Lookup(s.Flag); f != nil && f.Changed {
+		lv.Value, lv.Set = f.Value.String(), true
+	}
+	return lv
+}
+
+func (r Resolver) envLayer(s Setting) LayerValue {
+	lv := LayerValue{Layer: LayerEnv, Source: EnvName(s.Key)}
+	// An empty variable counts as unset, as it does for viper
+	if v := os.Getenv(lv.Source); v != "" {
+		lv.Value, lv.Set = v, true
+	}
+	return lv
+}
+
+func (r Resolver) vaultLayer(s Setting) LayerValue {
+	lv := LayerValue{Layer: LayerVault, Source: "settings." + s.Key}
+	if r.Vault == nil {
+		return lv
+	}
+	if v, ok := GetPath(r.Vault, s.Key); ok {
+		lv.Value, lv.Set = v, true
+	}
+	return lv
+}
+
+func (r Resolver) globalLayer(s Setting) LayerValue {
+	lv := LayerValue{Layer: LayerGlobal, Source: s.Key}
+	if r.Global == nil || !r.Global.InConfig(s.Key) {
+		return lv
+	}
+	doc, err := ReadGlobalDoc(r.Global.ConfigFileUsed())
+	if err != nil {
+		return lv
+	}
+	if v, ok := GetPath(doc, s.Key); ok {
+		lv.Value, lv.Set = v, true
+	}
+	return lv
+}
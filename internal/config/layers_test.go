@@ -0,0 +1,46 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestResolverLayers(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      string
+		vault    map[string]any
+		want     string
+		wantFrom Layer
+	}{
+		{name: "default", want: "vi", wantFrom: LayerDefault},
+		{name: "vault", vault: map[string]any{"editor": "nano"}, want: "nano", wantFrom: LayerVault},
+		{name: "env over vault", env: "emacs", vault: map[string]any{"editor": "nano"}, want: "emacs", wantFrom: LayerEnv},
+		{name: "empty env", env: "", vault: map[string]any{"editor": "nano"}, want: "nano", wantFrom: LayerVault},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvName("editor"), tt.env)
+			t.Setenv("VISUAL", "")
+			t.Setenv("EDITOR", "")
+			global := viper.New()
+			global.SetConfigFile(filepath.Join(t.TempDir(), "config.yaml"))
+			if err := BindLayers(global, nil); err != nil {
+				t.Fatal(err)
+			}
+			r := Resolver{Global: global, Vault: tt.vault}
+			if got := r.GetString("editor"); got != tt.want {
+				t.Errorf("GetString(editor) = %q, want %q", got, tt.want)
+			}
+			res, err := r.Explain("editor")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.From != tt.wantFrom || FormatValue(res.Value) != tt.want {
+				t.Errorf("Explain(editor) = %v from %v, want %q from %v", res.Value, res.From, tt.want, tt.wantFrom)
+			}
+		})
+	}
+}
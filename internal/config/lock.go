@@ -0,0 +1,50 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// lockTimeout bounds how long a writer waits for another noted process to finish.
+const lockTimeout = 10 * time.Second
+
+// errLocked is returned by tryLock when another process holds the lock.
+var errLocked = errors.New("config file is locked")
+
+// Lock is an exclusive lock on a config file, held through a sibling "<file>.lock" that
+// only exists while the lock is held.
+type Lock struct {
+	path string
+	f    *os.File
+}
+
+// LockFile blocks until it holds the lock for path or lockTimeout expires.
+func LockFile(path string) (*Lock, error) {
+	l := &Lock{path: path + ".lock"}
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err := l.tryLock()
+		if err == nil {
+			return l, nil
+		}
+		if !errors.Is(err, errLocked) {
+			return nil, err
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for %s: another noted process is writing it", path)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// withLock runs fn while holding the lock for path.
+func withLock(path string, fn func() error) error {
+	l, err := LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer l.Unlock()
+	return fn()
+}
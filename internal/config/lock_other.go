@@ -0,0 +1,36 @@
+//go:build !unix
+
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// staleLockAge is how old a lock file must be before it is assumed to belong to a crashed process.
+const staleLockAge = time.Minute
+
+func (l *Lock) tryLock() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0o644)
+	if err == nil {
+		l.f = f
+		return nil
+	}
+	if !os.IsExist(err) {
+		return err
+	}
+	if info, statErr := os.Stat(l.path); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+		os.Remove(l.path)
+	}
+	return errLocked
+}
+
+// Unlock releases the lock by removing the lock file.
+func (l *Lock) Unlock() error {
+	if l.f == nil {
+		return nil
+	}
+	l.f.Close()
+	l.f = nil
+	return os.Remove(l.path)
+}
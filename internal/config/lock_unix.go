@@ -0,0 +1,52 @@
+//go:build unix
+
+package config
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+func (l *Lock) tryLock() error {
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return errLocked
+		}
+		return err
+	}
+	// The previous holder removes the lock file before releasing it, so the file locked
+	// may no longer be the one at the path; try again on the one there now.
+	held, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if current, err := os.Stat(l.path); err != nil || !os.SameFile(held, current) {
+		f.Close()
+		return errLocked
+	}
+	l.f = f
+	return nil
+}
+
+// Unlock releases the lock. The lock file is removed while the lock is still held, so a
+// process that opened it in the meantime sees it is stale and locks a new one.
+func (l *Lock) Unlock() error {
+	if l.f == nil {
+		return nil
+	}
+	removeErr := os.Remove(l.path)
+	err := syscall.Flock(int(l.f.Fd()), syscall.LOCK_UN)
+	l.f.Close()
+	l.f = nil
+	if err == nil && removeErr != nil && !os.IsNotExist(removeErr) {
+		err = removeErr
+	}
+	return err
+}
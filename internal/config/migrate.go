@@ -0,0 +1,274 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersionKey is the key holding the schema version in both config.yaml and vault.json.
+const SchemaVersionKey = "schema_version"
+
+// Current schema versions written by this build of noted.
+const (
+	GlobalSchemaVersion = 1
+	VaultSchemaVersion  = 1
+)
+
+// Migration upgrades a decoded config document from version From to From+1.
+// Apply mutates doc in place and returns a human-readable line for every change it made.
+type Migration struct {
+	From        int
+	Description string
+	Apply       func(doc map[string]any) ([]string, error)
+}
+
+// MigrationReport describes the outcome of migrating a single config file.
+type MigrationReport struct {
+	Path    string
+	From    int
+	To      int
+	Backup  string   // Path of the backup taken before rewriting, empty if nothing changed
+	Changes []string // One line per change, prefixed with the step that made it
+}
+
+// Changed reports whether the file was rewritten.
+func (r MigrationReport) Changed() bool {
+	return r.From != r.To
+}
+
+// globalMigrations upgrade ~/.config/noted/config.yaml.
+var globalMigrations = []Migration{
+	{
+		From:        0,
+		Description: "add schema_version and fill in missing default keys",
+		Apply: func(doc map[string]any) ([]string, error) {
+			var changes []string
+			defaults := map[string]any{
+				"vaults":         []any{},
+				"current_vault":  "",
+				"templates_dir":  "",
+				"other_settings": map[string]any{},
+			}
+			for _, key := range []string{"vaults", "current_vault", "templates_dir", "other_settings"} {
+				if _, ok := doc[key]; !ok {
+					doc[key] = defaults[key]
+					changes = append(changes, fmt.Sprintf("added missing key %q", key))
+				}
+			}
+			return changes, nil
+		},
+	},
+}
+
+// vaultMigrations upgrade <vault>/vault.json.
+var vaultMigrations = []Migration{
+	{
+		From:        0,
+		Description: "add schema_version and normalize empty collections",
+		Apply: func(doc map[string]any) ([]string, error) {
+			var changes []string
+			for _, key := range []string{"metadata", "settings"} {
+				if v, ok := doc[key]; !ok || v == nil {
+					doc[key] = map[string]any{}
+					changes = append(changes, fmt.Sprintf("initialized %q to an empty map", key))
+				}
+			}
+			for _, key := range []string{"supported_types", "ignore_patterns"} {
+				if v, ok := doc[key]; !ok || v == nil {
+					doc[key] = []any{}
+					changes = append(changes, fmt.Sprintf("initialized %q to an empty list", key))
+				}
+			}
+			return changes, nil
+		},
+	},
+}
+
+// MigrateGlobalFile upgrades a YAML global config file in place.
+func MigrateGlobalFile(path string) (report MigrationReport, err error) {
+	err = withLock(path, func() error {
+		report, err = migrateGlobalFile(path)
+		return err
+	})
+	return report, err
+}
+
+// MigrateVaultFile upgrades a vault.json file in place.
+func MigrateVaultFile(path string) (report MigrationReport, err error) {
+	err = withLock(path, func() error {
+		report, err = migrateVaultFile(path)
+		return err
+	})
+	return report, err
+}
+
+func migrateGlobalFile(path string) (MigrationReport, error) {
+	return migrateFile(path, GlobalSchemaVersion, globalMigrations, yamlCodec{})
+}
+
+func migrateVaultFile(path string) (MigrationReport, error) {
+	return migrateFile(path, VaultSchemaVersion, vaultMigrations, jsonCodec{})
+}
+
+// codec decodes and encodes a config document in a specific file format.
+type codec interface {
+	decode(data []byte) (map[string]any, error)
+	encode(doc map[string]any) ([]byte, error)
+}
+
+type yamlCodec struct{}
+
+func (yamlCodec) decode(data []byte) (map[string]any, error) {
+	doc := map[string]any{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return doc, nil
+}
+
+func (yamlCodec) encode(doc map[string]any) ([]byte, error) {
+	return yaml.Marshal(doc)
+}
+
+type jsonCodec struct{}
+
+func (jsonCodec) decode(data []byte) (map[string]any, error) {
+	doc := map[string]any{}
+	if len(bytes.TrimSpace(data)) == 0 {
+		return doc, nil
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]any{}
+	}
+	return doc, nil
+}
+
+func (jsonCodec) encode(doc map[string]any) ([]byte, error) {
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// migrateFile upgrades path in place; the caller must hold the file's lock.
+func migrateFile(path string, current int, migrations []Migration, c codec) (MigrationReport, error) {
+	report := MigrationReport{Path: path}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return report, err
+	}
+	doc, err := c.decode(data)
+	if err != nil {
+		return report, fmt.Errorf("parse %s: %w", path, err)
+	}
+	from, err := schemaVersion(doc)
+	if err != nil {
+		return report, fmt.Errorf("%s: %w", path, err)
+	}
+	report.From, report.To = from, from
+	if from > current {
+		return report, fmt.Errorf("%s has schema version %d, newer than the supported version %d; upgrade noted", path, from, current)
+	}
+	if from == current {
+		return report, nil
+	}
+
+	changes, err := runMigrations(doc, from, current, migrations)
+	if err != nil {
+		return report, fmt.Errorf("migrate %s: %w", path, err)
+	}
+	out, err := c.encode(doc)
+	if err != nil {
+		return report, err
+	}
+
+	backup := fmt.Sprintf("%s.v%d.bak", path, from)
+	if err := copyFile(path, backup); err != nil {
+		return report, fmt.Errorf("back up %s: %w", path, err)
+	}
+	if err := WriteFileAtomic(path, out, 0o644); err != nil {
+		return report, err
+	}
+	report.To = current
+	report.Backup = backup
+	report.Changes = changes
+	return report, nil
+}
+
+// runMigrations applies every step from version from up to current and stamps the final version.
+func runMigrations(doc map[string]any, from, current int, migrations []Migration) ([]string, error) {
+	var changes []string
+	for v := from; v < current; v++ {
+		step, ok := findMigration(migrations, v)
+		if !ok {
+			return changes, fmt.Errorf("no migration from schema version %d", v)
+		}
+		stepChanges, err := step.Apply(doc)
+		if err != nil {
+			return changes, fmt.Errorf("v%d→v%d (%s): %w", v, v+1, step.Description, err)
+		}
+		doc[SchemaVersionKey] = v + 1
+		changes = append(changes, fmt.Sprintf("v%d→v%d: %s", v, v+1, step.Description))
+		for _, c := range stepChanges {
+			changes = append(changes, fmt.Sprintf("v%d→v%d: %s", v, v+1, c))
+		}
+	}
+	return changes, nil
+}
+
+func findMigration(migrations []Migration, from int) (Migration, bool) {
+	for _, m := range migrations {
+		if m.From == from {
+			return m, true
+		}
+	}
+	return Migration{}, false
+}
+
+// schemaVersion reads the schema version from a decoded document; a missing key means version 0.
+func schemaVersion(doc map[string]any) (int, error) {
+	raw, ok := doc[SchemaVersionKey]
+	if !ok || raw == nil {
+		return 0, nil
+	}
+	switch v := raw.(type) {
+	case int:
+		return v, nil
+	case int64:
+		return int(v), nil
+	case uint64:
+		return int(v), nil
+	case float64:
+		return int(v), nil
+	}
+	return 0, fmt.Errorf("invalid %s value %v", SchemaVersionKey, raw)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
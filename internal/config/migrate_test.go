@@ -0,0 +1,146 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMigrateGlobalFile(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		wantFrom    int
+		wantChanged bool
+		wantDoc     map[string]any
+		wantErr     string
+	}{
+		{
+			name:        "unversioned",
+			file:        "current_vault: /notes\n",
+			wantChanged: true,
+			wantDoc: map[string]any{
+				SchemaVersionKey: 1,
+				"current_vault":  "/notes",
+				"vaults":         []any{},
+				"templates_dir":  "",
+				"other_settings": map[string]any{},
+			},
+		},
+		{
+			name:     "current",
+			file:     "schema_version: 1\ncurrent_vault: /notes\n",
+			wantFrom: 1,
+			wantDoc:  map[string]any{SchemaVersionKey: 1, "current_vault": "/notes"},
+		},
+		{
+			name:    "newer",
+			file:    "schema_version: 9\n",
+			wantErr: "newer than the supported version",
+		},
+		{
+			name:    "invalid version",
+			file:    "schema_version: two\n",
+			wantErr: "invalid schema_version",
+		},
+		{
+			name:    "malformed",
+			file:    "vaults: [\n",
+			wantErr: "parse",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			report, err := MigrateGlobalFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("MigrateGlobalFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.From != tt.wantFrom || report.To != GlobalSchemaVersion || report.Changed() != tt.wantChanged {
+				t.Errorf("report = %+v", report)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			doc := map[string]any{}
+			if err := yaml.Unmarshal(data, &doc); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(doc, tt.wantDoc) {
+				t.Errorf("migrated file = %v, want %v", doc, tt.wantDoc)
+			}
+			backup, err := os.ReadFile(report.Backup)
+			if tt.wantChanged && (err != nil || string(backup) != tt.file) {
+				t.Errorf("backup %s = %q, %v; want the original file", report.Backup, backup, err)
+			}
+			if !tt.wantChanged && report.Backup != "" {
+				t.Errorf("backup %s taken of an unchanged file", report.Backup)
+			}
+		})
+	}
+}
+
+func TestMigrateVaultFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.json")
+	if err := os.WriteFile(path, []byte(`{"name": "notes", "settings": null}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	report, err := MigrateVaultFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !report.Changed() || len(report.Changes) != 5 {
+		t.Errorf("report = %+v, want the step and its 4 changes", report)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		SchemaVersionKey:  float64(1),
+		"name":            "notes",
+		"metadata":        map[string]any{},
+		"settings":        map[string]any{},
+		"supported_types": []any{},
+		"ignore_patterns": []any{},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("migrated file = %v, want %v", doc, want)
+	}
+
+	// Migrating again changes nothing
+	if report, err = MigrateVaultFile(path); err != nil || report.Changed() {
+		t.Errorf("second MigrateVaultFile() = %+v, %v", report, err)
+	}
+}
+
+func TestRunMigrationsMissingStep(t *testing.T) {
+	doc := map[string]any{}
+	migrations := []Migration{{From: 0, Description: "first", Apply: func(map[string]any) ([]string, error) { return nil, nil }}}
+	_, err := runMigrations(doc, 0, 2, migrations)
+	if err == nil || !strings.Contains(err.Error(), "no migration from schema version 1") {
+		t.Errorf("runMigrations() error = %v", err)
+	}
+	if doc[SchemaVersionKey] != 1 {
+		t.Errorf("schema version = %v after the first step, want 1", doc[SchemaVersionKey])
+	}
+}
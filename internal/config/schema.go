@@ -0,0 +1,302 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValueType describes how a config value is parsed from and rendered to text.
+type ValueType int
+
+const (
+	TypeAny ValueType = iota // Inferred from the text: bool, int, [list] or string
+	TypeString
+	TypeBool
+	TypeInt
+	TypeDuration
+	TypeStringList
+	TypeMap
+)
+
+// String returns the name used for the type in help output and the --type flag.
+func (t ValueType) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeBool:
+		return "bool"
+	case TypeInt:
+		return "int"
+	case TypeDuration:
+		return "duration"
+	case TypeStringList:
+		return "list"
+	case TypeMap:
+		return "map"
+	}
+	return "any"
+}
+
+// ParseValueType converts a type name as accepted by the --type flag.
+func ParseValueType(name string) (ValueType, error) {
+	for _, t := range []ValueType{TypeAny, TypeString, TypeBool, TypeInt, TypeDuration, TypeStringList, TypeMap} {
+		if t.String() == name {
+			return t, nil
+		}
+	}
+	return TypeAny, fmt.Errorf("unknown type %q (want string, bool, int, duration, list, map or any)", name)
+}
+
+// Key describes a known config key.
+// A key of TypeMap accepts arbitrary dotted sub-keys, typed by ElemType.
+type Key struct {
+	Name        string
+	Type        ValueType
+	ElemType    ValueType // Type of sub-keys for TypeMap keys
+	ReadOnly    bool      // Managed by noted itself, not settable by hand
+	Description string
+}
+
+// GlobalKeys is the schema of ~/.config/noted/config.yaml.
+var GlobalKeys = []Key{
+	{Name: SchemaVersionKey, Type: TypeInt, ReadOnly: true, Description: "Version of the config file layout"},
+	{Name: "vaults", Type: TypeString, ReadOnly: true, Description: "Registered vaults, managed by 'noted vault'"},
+	{Name: "current_vault", Type: TypeString, Description: "Path of the active vault"},
+	{Name: "templates_dir", Type: TypeString, Description: "Directory of templates shared by all vaults"},
+	{Name: "other_settings", Type: TypeMap, ElemType: TypeAny, Description: "Free-form settings"},
+	{Name: "groups", Type: TypeMap, ElemType: TypeStringList, Description: "Named groups of vaults, e.g. groups.work = [notes, wiki]"},
+	{Name: "bookmarks", Type: TypeStringList, Description: "Directories bookmarked in the directory picker"},
+	{Name: "keys", Type: TypeMap, ElemType: TypeStringList, Description: "Keys bound to TUI actions, replacing the keymap's, e.g. keys.quit = [q, ctrl+q]"},
+	{Name: "recent_dirs", Type: TypeStringList, ReadOnly: true, Description: "Directories recently chosen in the directory picker, managed by noted"},
+}
+
+// VaultKeys is the schema of <vault>/vault.json.
+var VaultKeys = []Key{
+	{Name: SchemaVersionKey, Type: TypeInt, ReadOnly: true, Description: "Version of the vault config layout"},
+	{Name: "name", Type: TypeString, Description: "Human-readable vault name"},
+	{Name: "templates_path", Type: TypeString, Description: "Path to the templates directory"},
+	{Name: "log_path", Type: TypeString, Description: "Path to the vault's log file"},
+	{Name: "history_path", Type: TypeString, Description: "Path to the vault's history file"},
+	{Name: "supported_types", Type: TypeStringList, Description: "File extensions treated as notes"},
+	{Name: "ignore_patterns", Type: TypeStringList, Description: "Glob patterns to ignore"},
+	{Name: "created_at", Type: TypeString, ReadOnly: true, Description: "When the vault was created"},
+	{Name: "modified_at", Type: TypeString, ReadOnly: true, Description: "When the vault config was last modified"},
+	{Name: "metadata", Type: TypeMap, ElemType: TypeString, Description: "Arbitrary metadata"},
+	{Name: "settings", Type: TypeMap, ElemType: TypeAny, Description: "Custom vault settings"},
+}
+
+// LookupKey finds the schema entry for a dotted key.
+// Sub-keys of map keys (e.g. settings.editor) resolve to a synthetic Key typed by the map's ElemType.
+func LookupKey(keys []Key, name string) (Key, error) {
+	root, sub, nested := strings.Cut(name, ".")
+	for _, k := range keys {
+		if k.Name != root {
+			continue
+		}
+		if !nested {
+			return k, nil
+		}
+		if k.Type != TypeMap || sub == "" {
+			return Key{}, fmt.Errorf("%q has no sub-key %q", root, sub)
+		}
+		if s, ok := LookupSetting(sub); ok && k.Name == "settings" {
+			return Key{Name: name, Type: s.Type, Description: s.Description}, nil
+		}
+		return Key{Name: name, Type: k.ElemType, Description: k.Description}, nil
+	}
+	return Key{}, fmt.Errorf("unknown config key %q", name)
+}
+
+// ParseValue converts text from the command line into a value of type t.
+func ParseValue(t ValueType, raw string) (any, error) {
+	switch t {
+	case TypeString:
+		return raw, nil
+	case TypeBool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a bool", raw)
+		}
+		return b, nil
+	case TypeInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", raw)
+		}
+		return n, nil
+	case TypeDuration:
+		d, err := ParseDuration(raw)
+		if err != nil {
+			return nil, err
+		}
+		return FormatDuration(d), nil
+	case TypeStringList:
+		return parseList(raw), nil
+	case TypeMap:
+		var m map[string]any
+		if err := json.Unmarshal([]byte(raw), &m); err != nil {
+			return nil, fmt.Errorf("%q is not a JSON object", raw)
+		}
+		return m, nil
+	}
+	return inferValue(raw), nil
+}
+
+// inferValue guesses the type of untyped text: bools, integers and [a, b] lists, else a string.
+func inferValue(raw string) any {
+	if b, err := strconv.ParseBool(raw); err == nil {
+		return b
+	}
+	if n, err := strconv.Atoi(raw); err == nil {
+		return n
+	}
+	if strings.HasPrefix(raw, "[") && strings.HasSuffix(raw, "]") {
+		return parseList(raw)
+	}
+	return raw
+}
+
+// parseList accepts "a,b,c" or "[a, b, c]".
+func parseList(raw string) []string {
+	raw = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(raw), "["), "]")
+	items := []string{}
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// ParseDuration extends time.ParseDuration with a "d" (day) and "w" (week) unit, e.g. "30d".
+func ParseDuration(raw string) (time.Duration, error) {
+	raw = strings.TrimSpace(raw)
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(raw, suffix); ok {
+			if v, err := strconv.Atoi(n); err == nil {
+				return time.Duration(v) * unit, nil
+			}
+		}
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a duration (e.g. 90m, 12h, 30d)", raw)
+	}
+	return d, nil
+}
+
+// FormatDuration renders whole days as "Nd" and everything else the way time.Duration does.
+func FormatDuration(d time.Duration) string {
+	day := 24 * time.Hour
+	if d > 0 && d%day == 0 {
+		return fmt.Sprintf("%dd", d/day)
+	}
+	return d.String()
+}
+
+// FormatValue renders a config value for display.
+func FormatValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case []string:
+		return "[" + strings.Join(val, ", ") + "]"
+	case []any:
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = FormatValue(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case map[string]any, map[string]string:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// GetPath looks up a dotted key in a nested document.
+func GetPath(doc map[string]any, key string) (any, bool) {
+	parts := strings.Split(key, ".")
+	var cur any = doc
+	for _, p := range parts {
+		m, ok := asMap(cur)
+		if !ok {
+			return nil, false
+		}
+		if cur, ok = m[p]; !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// SetPath stores value under a dotted key, creating intermediate maps.
+func SetPath(doc map[string]any, key string, value any) {
+	parts := strings.Split(key, ".")
+	m := doc
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := asMap(m[p])
+		if !ok {
+			next = map[string]any{}
+		}
+		m[p] = next
+		m = next
+	}
+	m[parts[len(parts)-1]] = value
+}
+
+// UnsetPath removes a dotted key and reports whether it existed.
+func UnsetPath(doc map[string]any, key string) bool {
+	parts := strings.Split(key, ".")
+	m := doc
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := asMap(m[p])
+		if !ok {
+			return false
+		}
+		m = next
+	}
+	last := parts[len(parts)-1]
+	if _, ok := m[last]; !ok {
+		return false
+	}
+	delete(m, last)
+	return true
+}
+
+// Flatten returns every leaf of a nested document keyed by its dotted path, sorted by key.
+func Flatten(doc map[string]any) ([]string, map[string]any) {
+	flat := map[string]any{}
+	flattenInto(flat, "", doc)
+	keys := make([]string, 0, len(flat))
+	for k := range flat {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, flat
+}
+
+func flattenInto(flat map[string]any, prefix string, doc map[string]any) {
+	for k, v := range doc {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		if m, ok := asMap(v); ok && len(m) > 0 {
+			flattenInto(flat, key, m)
+			continue
+		}
+		flat[key] = v
+	}
+}
+
+func asMap(v any) (map[string]any, bool) {
+	m, ok := v.(map[string]any)
+	return m, ok
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		typ     ValueType
+		raw     string
+		want    any
+		wantErr bool
+	}{
+		{typ: TypeString, raw: "42", want: "42"},
+		{typ: TypeBool, raw: "true", want: true},
+		{typ: TypeBool, raw: "maybe", wantErr: true},
+		{typ: TypeInt, raw: "30", want: 30},
+		{typ: TypeInt, raw: "3.5", wantErr: true},
+		{typ: TypeDuration, raw: "48h", want: "2d"},
+		{typ: TypeDuration, raw: "90m", want: "1h30m0s"},
+		{typ: TypeDuration, raw: "soon", wantErr: true},
+		{typ: TypeStringList, raw: "a, b,,c", want: []string{"a", "b", "c"}},
+		{typ: TypeStringList, raw: "[]", want: []string{}},
+		{typ: TypeMap, raw: `{"a": 1}`, want: map[string]any{"a": float64(1)}},
+		{typ: TypeMap, raw: "a=1", wantErr: true},
+		{typ: TypeAny, raw: "false", want: false},
+		{typ: TypeAny, raw: "7", want: 7},
+		{typ: TypeAny, raw: "[x, y]", want: []string{"x", "y"}},
+		{typ: TypeAny, raw: "vim", want: "vim"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.typ.String()+"/"+tt.raw, func(t *testing.T) {
+			got, err := ParseValue(tt.typ, tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseValue() error = %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseValue() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseValueType(t *testing.T) {
+	for _, name := range []string{"any", "string", "bool", "int", "duration", "list", "map"} {
+		typ, err := ParseValueType(name)
+		if err != nil || typ.String() != name {
+			t.Errorf("ParseValueType(%q) = %v, %v", name, typ, err)
+		}
+	}
+	if _, err := ParseValueType("float"); err == nil {
+		t.Error("ParseValueType(float) succeeded")
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    time.Duration
+		wantErr bool
+	}{
+		{raw: "30d", want: 30 * 24 * time.Hour},
+		{raw: " 2w ", want: 14 * 24 * time.Hour},
+		{raw: "12h", want: 12 * time.Hour},
+		{raw: "1h30m", want: 90 * time.Minute},
+		{raw: "0", want: 0},
+		{raw: "d", wantErr: true},
+		{raw: "1.5d", wantErr: true},
+		{raw: "later", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseDuration(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDuration() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"cobra-cli/internal/models"
+)
+
+// VaultConfigFile is the name of the config file stored at the root of every vault.
+const VaultConfigFile = "vault.json"
+
+// VaultDataDir is the folder inside every vault where noted keeps its own state, such as
+// the operation journal. It is never walked, searched or indexed.
+const VaultDataDir = ".noted"
+
+// VaultTrashDir is the folder inside every vault that holds deleted notes until they are
+// restored or expire. Like VaultDataDir, it is never walked, searched or indexed.
+const VaultTrashDir = ".trash"
+
+// VaultDataPath returns the path of a state file in the data folder of the vault at dir.
+func VaultDataPath(dir, name string) string {
+	return filepath.Join(dir, VaultDataDir, name)
+}
+
+// VaultConfigPath returns the path of the config file for the vault at dir.
+func VaultConfigPath(dir string) string {
+	return filepath.Join(dir, VaultConfigFile)
+}
+
+// ReadVault loads the vault config stored in dir, migrating it to the current schema first.
+func ReadVault(dir string) (models.VaultConfig, MigrationReport, error) {
+	var cfg models.VaultConfig
+	report, err := MigrateVaultFile(VaultConfigPath(dir))
+	if err != nil {
+		return cfg, report, err
+	}
+	cfg, err = readVault(dir)
+	return cfg, report, err
+}
+
+// WriteVault replaces the config in dir with cfg, stamping the current schema version.
+func WriteVault(dir string, cfg models.VaultConfig) error {
+	return withLock(VaultConfigPath(dir), func() error {
+		return writeVault(dir, cfg)
+	})
+}
+
+// UpdateVault re-reads the config in dir under the file lock, applies fn and writes the
+// result back, so concurrent noted processes never drop each other's changes.
+func UpdateVault(dir string, fn func(cfg *models.VaultConfig) error) error {
+	return withLock(VaultConfigPath(dir), func() error {
+		if _, err := migrateVaultFile(VaultConfigPath(dir)); err != nil {
+			return err
+		}
+		cfg, err := readVault(dir)
+		if err != nil {
+			return err
+		}
+		if err := fn(&cfg); err != nil {
+			return err
+		}
+		return writeVault(dir, cfg)
+	})
+}
+
+func readVault(dir string) (models.VaultConfig, error) {
+	var cfg models.VaultConfig
+	data, err := os.ReadFile(VaultConfigPath(dir))
+	if err != nil {
+		return cfg, err
+	}
+	err = json.Unmarshal(data, &cfg)
+	return cfg, err
+}
+
+func writeVault(dir string, cfg models.VaultConfig) error {
+	cfg.SchemaVersion = VaultSchemaVersion
+	b, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(VaultConfigPath(dir), append(b, '\n'), 0o644)
+}
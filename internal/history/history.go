@@ -0,0 +1,117 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"cobra-cli/internal/config"
+)
+
+// Actions recorded in the history log.
+const (
+	ActionOpen    = "open"
+	ActionCreate  = "create"
+	ActionEdit    = "edit"
+	ActionCommand = "command"
+)
+
+// Entry is one line of a vault's history log, the JSON-lines file at
+// VaultConfig.HistoryPath that records what happened to which note and when.
+type Entry struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Note    string    `json:"note,omitempty"`    // Vault-relative path of the note, if any
+	Command string    `json:"command,omitempty"` // Command line, for ActionCommand
+	Words   int       `json:"words,omitempty"`   // Change in word count, for ActionCreate and ActionEdit
+}
+
+// Read returns every entry in the log at path, oldest first. A missing log has no
+// entries, and lines that cannot be parsed are skipped.
+func Read(path string) ([]Entry, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Time.IsZero() {
+			continue
+		}
+		entries = append(entries, e)
+	}
+	return entries, scanner.Err()
+}
+
+// Append adds e to the log at path, stamping the current time if e has none.
+func Append(path string, e Entry) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	lock, err := config.LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// RecentNotes returns the latest open, create or edit entry of each note, most recent
+// first, up to n entries, or all of them if n is 0.
+func RecentNotes(entries []Entry, n int) []Entry {
+	var recent []Entry
+	seen := map[string]bool{}
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if e.Note == "" || seen[e.Note] {
+			continue
+		}
+		if e.Action != ActionOpen && e.Action != ActionCreate && e.Action != ActionEdit {
+			continue
+		}
+		seen[e.Note] = true
+		recent = append(recent, e)
+		if n > 0 && len(recent) == n {
+			break
+		}
+	}
+	return recent
+}
+
+// RecentCommands returns the latest commands run, most recent first, up to n entries,
+// or all of them if n is 0.
+func RecentCommands(entries []Entry, n int) []Entry {
+	var recent []Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		if entries[i].Action != ActionCommand {
+			continue
+		}
+		recent = append(recent, entries[i])
+		if n > 0 && len(recent) == n {
+			break
+		}
+	}
+	return recent
+}
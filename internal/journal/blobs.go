@@ -0,0 +1,157 @@
+package journal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"cobra-cli/internal/config"
+)
+
+// BlobsDir is the folder in a vault's data folder that keeps the file contents the journal
+// refers to, one file per distinct contents, named after their SHA-256.
+const BlobsDir = "blobs"
+
+// blobsPath returns the blob folder of the vault at dir.
+func blobsPath(dir string) string {
+	return config.VaultDataPath(dir, BlobsDir)
+}
+
+// blobPath returns where the blob named hash is kept.
+func blobPath(dir, hash string) string {
+	return filepath.Join(blobsPath(dir), hash[:2], hash)
+}
+
+// storeBlob keeps data as a blob, unless the same contents already are one, and returns
+// its name.
+func storeBlob(dir string, data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	path := blobPath(dir, hash)
+	if _, err := os.Stat(path); err == nil {
+		return hash, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	return hash, config.WriteFileAtomic(path, data, 0o644)
+}
+
+// storeBlobs keeps the contents of changes not stored yet as blobs.
+func storeBlobs(dir string, changes []Change) error {
+	for i := range changes {
+		c := &changes[i]
+		var err error
+		if c.Before != nil && c.before == "" {
+			if c.before, err = storeBlob(dir, *c.Before); err != nil {
+				return err
+			}
+		}
+		if c.After != nil && c.after == "" {
+			if c.after, err = storeBlob(dir, *c.After); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// loadBlobs reads the contents of changes from their blobs.
+func loadBlobs(dir string, changes []Change) error {
+	load := func(hash string, contents **[]byte, rel string) error {
+		if hash == "" || *contents != nil {
+			return nil
+		}
+		data, err := os.ReadFile(blobPath(dir, hash))
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("the journal no longer has the contents of %s", rel)
+		}
+		if err != nil {
+			return err
+		}
+		*contents = &data
+		return nil
+	}
+	for i := range changes {
+		c := &changes[i]
+		if err := load(c.before, &c.Before, c.Path); err != nil {
+			return err
+		}
+		if err := load(c.after, &c.After, c.Path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blobs returns the names of the blobs the changes refer to.
+func blobs(changes []Change) []string {
+	var names []string
+	for _, c := range changes {
+		for _, hash := range []string{c.before, c.after} {
+			if hash != "" {
+				names = append(names, hash)
+			}
+		}
+	}
+	return names
+}
+
+// capBlobs drops the oldest operations of j, but never the latest, while the blobs of the
+// operations kept add up to more than MaxBlobBytes.
+func capBlobs(dir string, j *Journal) error {
+	seen := map[string]bool{}
+	var total int64
+	for i := len(j.Operations) - 1; i >= 0; i-- {
+		for _, hash := range blobs(j.Operations[i].Changes) {
+			if seen[hash] {
+				continue
+			}
+			seen[hash] = true
+			info, err := os.Stat(blobPath(dir, hash))
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		if total > MaxBlobBytes && i < len(j.Operations)-1 {
+			dropped := i + 1
+			j.Operations = j.Operations[dropped:]
+			j.Cursor = max(j.Cursor-dropped, 0)
+			return nil
+		}
+	}
+	return nil
+}
+
+// collectBlobs removes the blobs no operation of j refers to anymore.
+func collectBlobs(dir string, j Journal) error {
+	used := map[string]bool{}
+	for _, op := range j.Operations {
+		for _, hash := range blobs(op.Changes) {
+			used[hash] = true
+		}
+	}
+	root := blobsPath(dir)
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if errors.Is(err, os.ErrNotExist) && path == root {
+			return filepath.SkipDir
+		}
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		if !used[d.Name()] {
+			return os.Remove(path)
+		}
+		return nil
+	})
+}
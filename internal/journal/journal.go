@@ -0,0 +1,350 @@
+package journal
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"cobra-cli/internal/config"
+)
+
+// JournalFile is the name of the operation journal in a vault's data folder.
+const JournalFile = "journal.json"
+
+// MaxOperations is how many operations the journal keeps; older ones can no longer be undone.
+const MaxOperations = 100
+
+// MaxBlobBytes bounds the size of the file contents the journal keeps. Once the contents of
+// its operations add up to more, the oldest are dropped and can no longer be undone; the
+// latest operation is always kept.
+var MaxBlobBytes int64 = 64 << 20
+
+// Kinds of operations recorded in the journal.
+const (
+	KindRename    = "rename"
+	KindDelete    = "delete"
+	KindTagRename = "tag-rename"
+	KindEdit      = "edit"
+	KindRestore   = "restore"
+)
+
+// Change is what an operation did to a single file. A nil Before means the operation
+// created the file, a nil After that it removed it. The journal file only refers to the
+// contents, which are kept as blobs next to it and loaded when the change is replayed.
+type Change struct {
+	Path   string // Vault-relative, with forward slashes
+	Before *[]byte
+	After  *[]byte
+	// Mode holds the permissions the file is written with. Apply fills it in from the file
+	// when it exists; files created without one get 0644.
+	Mode os.FileMode
+
+	before, after string // Blobs of Before and After, "" if there is none
+}
+
+// storedChange is how a Change is written in the journal file.
+type storedChange struct {
+	Path   string      `json:"path"`
+	Before string      `json:"before_blob,omitempty"`
+	After  string      `json:"after_blob,omitempty"`
+	Mode   os.FileMode `json:"mode,omitempty"`
+	// Contents written inline by journals from before blobs; moved to blobs on the next write
+	InlineBefore *[]byte `json:"before,omitempty"`
+	InlineAfter  *[]byte `json:"after,omitempty"`
+}
+
+func (c Change) MarshalJSON() ([]byte, error) {
+	return json.Marshal(storedChange{Path: c.Path, Before: c.before, After: c.after, Mode: c.Mode})
+}
+
+func (c *Change) UnmarshalJSON(data []byte) error {
+	var s storedChange
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*c = Change{Path: s.Path, Before: s.InlineBefore, After: s.InlineAfter, Mode: s.Mode, before: s.Before, after: s.After}
+	return nil
+}
+
+// Contents wraps file contents for a Change.
+func Contents(data []byte) *[]byte {
+	return &data
+}
+
+// Operation is a set of file changes applied, undone and redone as a whole.
+type Operation struct {
+	ID          int       `json:"id"`
+	Time        time.Time `json:"time"`
+	Kind        string    `json:"kind"`
+	Description string    `json:"description"`
+	Changes     []Change  `json:"changes"`
+}
+
+// Journal is the undo history of a vault. Operations before Cursor are applied and can be
+// undone; operations from Cursor on were undone and can be redone.
+type Journal struct {
+	Cursor     int         `json:"cursor"`
+	Operations []Operation `json:"operations"`
+	NextID     int         `json:"next_id"`
+}
+
+// Path returns the journal file of the vault at dir.
+func Path(dir string) string {
+	return config.VaultDataPath(dir, JournalFile)
+}
+
+// Read loads the journal of the vault at dir; a vault without one has an empty journal.
+func Read(dir string) (Journal, error) {
+	var j Journal
+	data, err := os.ReadFile(Path(dir))
+	if errors.Is(err, os.ErrNotExist) {
+		return j, nil
+	}
+	if err != nil {
+		return j, err
+	}
+	if err := json.Unmarshal(data, &j); err != nil {
+		return j, fmt.Errorf("parse %s: %w", Path(dir), err)
+	}
+	if j.Cursor < 0 || j.Cursor > len(j.Operations) {
+		j.Cursor = len(j.Operations)
+	}
+	return j, nil
+}
+
+// write stores the contents of new changes as blobs, drops the oldest operations once their
+// contents outgrow MaxBlobBytes, writes the journal and removes the blobs it no longer uses.
+func write(dir string, j *Journal) error {
+	for i := range j.Operations {
+		if err := storeBlobs(dir, j.Operations[i].Changes); err != nil {
+			return err
+		}
+	}
+	if err := capBlobs(dir, j); err != nil {
+		return err
+	}
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	if err := config.WriteFileAtomic(Path(dir), data, 0o644); err != nil {
+		return err
+	}
+	return collectBlobs(dir, *j)
+}
+
+// update runs fn on the journal under its lock and writes it back.
+func update(dir string, fn func(j *Journal) error) error {
+	if err := os.MkdirAll(filepath.Dir(Path(dir)), 0o755); err != nil {
+		return err
+	}
+	lock, err := config.LockFile(Path(dir))
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	j, err := Read(dir)
+	if err != nil {
+		return err
+	}
+	if err := fn(&j); err != nil {
+		return err
+	}
+	return write(dir, &j)
+}
+
+// Apply performs op on the vault at dir and records it, discarding anything that was undone.
+// Every file must still match its Before state, so an operation planned from stale reads
+// never clobbers newer changes.
+func Apply(dir string, op Operation) (Operation, error) {
+	err := update(dir, func(j *Journal) error {
+		if err := recordModes(dir, op.Changes); err != nil {
+			return err
+		}
+		if err := forward(dir, op.Changes); err != nil {
+			return err
+		}
+		j.NextID++
+		op.ID = j.NextID
+		if op.Time.IsZero() {
+			op.Time = time.Now()
+		}
+		j.Operations = append(j.Operations[:j.Cursor], op)
+		if len(j.Operations) > MaxOperations {
+			j.Operations = j.Operations[len(j.Operations)-MaxOperations:]
+		}
+		j.Cursor = len(j.Operations)
+		return nil
+	})
+	return op, err
+}
+
+// Undo reverses the last applied operation. It refuses if any file it touched has
+// changed since.
+func Undo(dir string) (Operation, error) {
+	var op Operation
+	err := update(dir, func(j *Journal) error {
+		if j.Cursor == 0 {
+			return errors.New("nothing to undo")
+		}
+		op = j.Operations[j.Cursor-1]
+		if err := loadBlobs(dir, op.Changes); err != nil {
+			return fmt.Errorf("cannot undo %s: %w", op.Description, err)
+		}
+		if err := backward(dir, op.Changes); err != nil {
+			return fmt.Errorf("cannot undo %s: %w", op.Description, err)
+		}
+		j.Cursor--
+		return nil
+	})
+	return op, err
+}
+
+// Redo re-applies the last undone operation. It refuses if any file it touches has
+// changed since the undo.
+func Redo(dir string) (Operation, error) {
+	var op Operation
+	err := update(dir, func(j *Journal) error {
+		if j.Cursor == len(j.Operations) {
+			return errors.New("nothing to redo")
+		}
+		op = j.Operations[j.Cursor]
+		if err := loadBlobs(dir, op.Changes); err != nil {
+			return fmt.Errorf("cannot redo %s: %w", op.Description, err)
+		}
+		if err := forward(dir, op.Changes); err != nil {
+			return fmt.Errorf("cannot redo %s: %w", op.Description, err)
+		}
+		j.Cursor++
+		return nil
+	})
+	return op, err
+}
+
+// Forget removes the operations matched by match from the journal, along with the file
+// contents only they kept, and returns how many it removed. They can no longer be undone
+// or redone.
+func Forget(dir string, match func(op Operation) bool) (int, error) {
+	if _, err := os.Stat(Path(dir)); errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	removed := 0
+	err := update(dir, func(j *Journal) error {
+		var kept []Operation
+		cursor := j.Cursor
+		for i, op := range j.Operations {
+			if !match(op) {
+				kept = append(kept, op)
+				continue
+			}
+			removed++
+			if i < j.Cursor {
+				cursor--
+			}
+		}
+		j.Operations, j.Cursor = kept, cursor
+		return nil
+	})
+	return removed, err
+}
+
+// recordModes fills in the permissions of the changed files that exist.
+func recordModes(dir string, changes []Change) error {
+	for i, c := range changes {
+		if c.Mode != 0 || c.Before == nil {
+			continue
+		}
+		info, err := os.Stat(filepath.Join(dir, filepath.FromSlash(c.Path)))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		changes[i].Mode = info.Mode().Perm()
+	}
+	return nil
+}
+
+// forward moves every file from its Before to its After state.
+func forward(dir string, changes []Change) error {
+	return transition(dir, changes,
+		func(c Change) *[]byte { return c.Before },
+		func(c Change) *[]byte { return c.After })
+}
+
+// backward moves every file from its After back to its Before state.
+func backward(dir string, changes []Change) error {
+	return transition(dir, changes,
+		func(c Change) *[]byte { return c.After },
+		func(c Change) *[]byte { return c.Before })
+}
+
+// transition checks that every file is in its from state before touching any, then moves
+// them to their to state. Files are written before any are removed, so a failure midway
+// never loses contents.
+func transition(dir string, changes []Change, from, to func(Change) *[]byte) error {
+	for _, c := range changes {
+		if err := check(dir, c.Path, from(c)); err != nil {
+			return err
+		}
+	}
+	for _, removing := range []bool{false, true} {
+		for _, c := range changes {
+			if (to(c) == nil) != removing {
+				continue
+			}
+			if err := set(dir, c.Path, to(c), c.Mode); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// check verifies that the file at rel is in the expected state.
+func check(dir, rel string, want *[]byte) error {
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+	exists := err == nil
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	switch {
+	case want == nil && exists:
+		return fmt.Errorf("%s exists", rel)
+	case want != nil && !exists:
+		return fmt.Errorf("%s no longer exists", rel)
+	case want != nil && !bytes.Equal(data, *want):
+		return fmt.Errorf("%s has changed since", rel)
+	}
+	return nil
+}
+
+// set writes the file at rel with the permissions mode, or 0644 without any, or removes it.
+func set(dir, rel string, contents *[]byte, mode os.FileMode) error {
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if contents == nil {
+		err := os.Remove(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		// Drop folders the removal left empty, so undoing a move leaves no trace
+		for d := filepath.Dir(path); d != filepath.Clean(dir) && os.Remove(d) == nil; d = filepath.Dir(d) {
+		}
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if mode == 0 {
+		mode = 0o644
+	}
+	return config.WriteFileAtomic(path, *contents, mode)
+}
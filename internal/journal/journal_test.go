@@ -0,0 +1,344 @@
+package journal
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, rel, content string, mode os.FileMode) {
+	t.Helper()
+	path := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), mode); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, mode); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// state returns the contents of rel, or "<none>" if it does not exist.
+func state(t *testing.T, dir, rel string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+	if os.IsNotExist(err) {
+		return "<none>"
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func edit(rel, before, after string) Change {
+	return Change{Path: rel, Before: Contents([]byte(before)), After: Contents([]byte(after))}
+}
+
+func TestApplyUndoRedo(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   map[string]string
+		changes []Change
+		after   map[string]string
+	}{
+		{
+			name:    "edit",
+			files:   map[string]string{"a.md": "one"},
+			changes: []Change{edit("a.md", "one", "two")},
+			after:   map[string]string{"a.md": "two"},
+		},
+		{
+			name:  "move",
+			files: map[string]string{"a.md": "note", "b.md": "[[a]]"},
+			changes: []Change{
+				{Path: "a.md", Before: Contents([]byte("note"))},
+				{Path: "dir/c.md", After: Contents([]byte("note"))},
+				edit("b.md", "[[a]]", "[[c]]"),
+			},
+			after: map[string]string{"a.md": "<none>", "dir/c.md": "note", "b.md": "[[c]]"},
+		},
+		{
+			name:    "create",
+			changes: []Change{{Path: "new.md", After: Contents([]byte("hi"))}},
+			after:   map[string]string{"new.md": "hi"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for rel, content := range tt.files {
+				writeFile(t, dir, rel, content, 0o644)
+			}
+			if _, err := Apply(dir, Operation{Kind: KindEdit, Description: tt.name, Changes: tt.changes}); err != nil {
+				t.Fatal(err)
+			}
+			for rel, want := range tt.after {
+				if got := state(t, dir, rel); got != want {
+					t.Errorf("after apply, %s = %q, want %q", rel, got, want)
+				}
+			}
+			if _, err := Undo(dir); err != nil {
+				t.Fatal(err)
+			}
+			for rel := range tt.after {
+				want, ok := tt.files[rel]
+				if !ok {
+					want = "<none>"
+				}
+				if got := state(t, dir, rel); got != want {
+					t.Errorf("after undo, %s = %q, want %q", rel, got, want)
+				}
+			}
+			if _, err := os.Stat(filepath.Join(dir, "dir")); !os.IsNotExist(err) {
+				t.Errorf("undo left an empty folder behind")
+			}
+			if _, err := Redo(dir); err != nil {
+				t.Fatal(err)
+			}
+			for rel, want := range tt.after {
+				if got := state(t, dir, rel); got != want {
+					t.Errorf("after redo, %s = %q, want %q", rel, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRefusals(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := Undo(dir); err == nil || !strings.Contains(err.Error(), "nothing to undo") {
+		t.Errorf("Undo() on an empty journal = %v", err)
+	}
+	if _, err := Redo(dir); err == nil || !strings.Contains(err.Error(), "nothing to redo") {
+		t.Errorf("Redo() on an empty journal = %v", err)
+	}
+
+	writeFile(t, dir, "a.md", "changed elsewhere", 0o644)
+	if _, err := Apply(dir, Operation{Changes: []Change{edit("a.md", "one", "two")}}); err == nil {
+		t.Error("Apply() planned from a stale read succeeded")
+	}
+	if _, err := Apply(dir, Operation{Changes: []Change{{Path: "a.md", After: Contents([]byte("x"))}}}); err == nil {
+		t.Error("Apply() created a file over an existing one")
+	}
+	if got := state(t, dir, "a.md"); got != "changed elsewhere" {
+		t.Errorf("refused Apply() changed a.md to %q", got)
+	}
+
+	// Undo and redo refuse once a file changed since, and leave every file as it was
+	writeFile(t, dir, "b.md", "b", 0o644)
+	if _, err := Apply(dir, Operation{Description: "both", Changes: []Change{
+		edit("a.md", "changed elsewhere", "mine"),
+		edit("b.md", "b", "b2"),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "b.md", "edited after", 0o644)
+	if _, err := Undo(dir); err == nil || !strings.Contains(err.Error(), "b.md has changed since") {
+		t.Errorf("Undo() over a changed file = %v", err)
+	}
+	if got := state(t, dir, "a.md"); got != "mine" {
+		t.Errorf("refused Undo() changed a.md to %q", got)
+	}
+	writeFile(t, dir, "b.md", "b2", 0o644)
+	if _, err := Undo(dir); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(filepath.Join(dir, "a.md"))
+	if _, err := Redo(dir); err == nil || !strings.Contains(err.Error(), "a.md no longer exists") {
+		t.Errorf("Redo() over a removed file = %v", err)
+	}
+}
+
+func TestApplyDiscardsRedo(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.md", "1", 0o644)
+	for _, c := range []Change{edit("a.md", "1", "2"), edit("a.md", "2", "3")} {
+		if _, err := Apply(dir, Operation{Changes: []Change{c}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Undo(dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Apply(dir, Operation{Changes: []Change{edit("a.md", "2", "4")}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Redo(dir); err == nil {
+		t.Error("Redo() replayed an operation discarded by a newer one")
+	}
+	j, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(j.Operations) != 2 || j.Cursor != 2 {
+		t.Errorf("journal has %d operations and cursor %d, want 2 and 2", len(j.Operations), j.Cursor)
+	}
+}
+
+func TestContentsKeptAsBlobs(t *testing.T) {
+	dir := t.TempDir()
+	secret := strings.Repeat("large note body ", 100)
+	writeFile(t, dir, "a.md", secret, 0o644)
+	if _, err := Apply(dir, Operation{Changes: []Change{edit("a.md", secret, "short")}}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(Path(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("large note body")) || len(data) > 1000 {
+		t.Errorf("journal file holds the contents inline: %d bytes", len(data))
+	}
+	if _, err := Undo(dir); err != nil {
+		t.Fatal(err)
+	}
+	if got := state(t, dir, "a.md"); got != secret {
+		t.Errorf("undo restored %q", got)
+	}
+}
+
+func TestModeKept(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "run.md", "one", 0o600)
+	ops := []Operation{
+		{Description: "edit", Changes: []Change{edit("run.md", "one", "two")}},
+		{Description: "removal", Changes: []Change{{Path: "run.md", Before: Contents([]byte("two"))}}},
+	}
+	for _, op := range ops {
+		if _, err := Apply(dir, op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, step := range []string{"removal", "edit"} {
+		if _, err := Undo(dir); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(filepath.Join(dir, "run.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0o600 {
+			t.Errorf("after undoing the %s, run.md has mode %v, want 0600", step, info.Mode().Perm())
+		}
+	}
+}
+
+func TestBlobCap(t *testing.T) {
+	defer func(n int64) { MaxBlobBytes = n }(MaxBlobBytes)
+	MaxBlobBytes = 3500
+	dir := t.TempDir()
+	content := "start"
+	writeFile(t, dir, "a.md", content, 0o644)
+	for i := 0; i < 6; i++ {
+		next := strings.Repeat(string(rune('a'+i)), 1000)
+		if _, err := Apply(dir, Operation{Changes: []Change{edit("a.md", content, next)}}); err != nil {
+			t.Fatal(err)
+		}
+		content = next
+	}
+	j, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(j.Operations) != 2 || j.Cursor != 2 {
+		t.Errorf("journal kept %d operations with cursor %d, want 2 and 2", len(j.Operations), j.Cursor)
+	}
+	var blobs int
+	filepath.WalkDir(blobsPath(dir), func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			blobs++
+		}
+		return nil
+	})
+	if blobs != 3 {
+		t.Errorf("%d blobs left, want the 3 the kept operations use", blobs)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := Undo(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Undo(dir); err == nil {
+		t.Error("Undo() went past the dropped operations")
+	}
+}
+
+func TestLegacyInlineJournal(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.md", "two", 0o644)
+	// "b25l" and "dHdv" are "one" and "two" in base64
+	legacy := `{"cursor":1,"next_id":1,"operations":[{"id":1,"kind":"edit","description":"old","changes":[{"path":"a.md","before":"b25l","after":"dHdv"}]}]}`
+	writeFile(t, dir, ".noted/journal.json", legacy, 0o644)
+	writeFile(t, dir, "b.md", "x", 0o644)
+	if _, err := Apply(dir, Operation{Changes: []Change{edit("b.md", "x", "y")}}); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(Path(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte(`"before":`)) {
+		t.Errorf("inline contents were not moved to blobs: %s", data)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := Undo(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := state(t, dir, "a.md"); got != "one" {
+		t.Errorf("undoing the legacy operation left a.md = %q", got)
+	}
+	if got := state(t, dir, "b.md"); got != "x" {
+		t.Errorf("b.md = %q", got)
+	}
+}
+
+func TestForget(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.md", "1", 0o644)
+	writeFile(t, dir, "b.md", "secret", 0o644)
+	ops := []Operation{
+		{Description: "a", Changes: []Change{edit("a.md", "1", "2")}},
+		{Description: "b", Changes: []Change{{Path: "b.md", Before: Contents([]byte("secret"))}}},
+		{Description: "a again", Changes: []Change{edit("a.md", "2", "3")}},
+	}
+	for _, op := range ops {
+		if _, err := Apply(dir, op); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := Undo(dir); err != nil {
+		t.Fatal(err)
+	}
+	n, err := Forget(dir, func(op Operation) bool { return op.Description == "b" })
+	if err != nil || n != 1 {
+		t.Fatalf("Forget() = %d, %v", n, err)
+	}
+	j, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(j.Operations) != 2 || j.Cursor != 1 {
+		t.Errorf("journal has %d operations and cursor %d, want 2 and 1", len(j.Operations), j.Cursor)
+	}
+	found := false
+	filepath.WalkDir(blobsPath(dir), func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			if data, _ := os.ReadFile(path); string(data) == "secret" {
+				found = true
+			}
+		}
+		return nil
+	})
+	if found {
+		t.Error("the forgotten operation's contents are still kept")
+	}
+	if op, err := Redo(dir); err != nil || op.Description != "a again" {
+		t.Errorf("Redo() = %q, %v", op.Description, err)
+	}
+}
@@ -5,6 +5,7 @@ import "time"
 // VaultConfig represents the configuration for a vault.
 // This config is stored at the base of the vault directory.
 type VaultConfig struct {
+	SchemaVersion  int               `json:"schema_version"`   // Version of the vault.json layout, used for migrations
 	Name           string            `json:"name"`             // Human-readable vault name
 	TemplatesPath  string            `json:"templates_path"`   // Path to the templates directory
 	LogPath        string            `json:"log_path"`         // Path to the vault's log file (optional)
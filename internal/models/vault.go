@@ -1,5 +1,7 @@
 package models
 
+import "strings"
+
 // Vault represents a vault directory and its configuration.
 // It embeds VaultConfig for direct access to config fields.
 type Vault struct {
@@ -9,3 +11,14 @@ type Vault struct {
 	Config         VaultConfig // Embedded config for this vault
 } 
 
+
+// FindVault looks a vault up by name, case-insensitively. Vault names are unique
+// regardless of case, so at most one matches.
+func FindVault(vaults []Vault, name string) (Vault, bool) {
+	for _, v := range vaults {
+		if strings.EqualFold(v.Name, name) {
+			return v, true
+		}
+	}
+	return Vault{}, false
+}
@@ -0,0 +1,154 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// BoardColumn is a column of a kanban board note: a heading and the list items under it.
+type BoardColumn struct {
+	Title string
+	Line  int // 1-based line of the heading
+	Cards []BoardCard
+}
+
+// BoardCard is a top-level list item of a board column, along with the lines indented
+// under it.
+type BoardCard struct {
+	Text  string // The item without its list marker and checkbox
+	Done  bool   // The item is a checked-off task
+	Line  int    // 1-based line the item starts on
+	Lines int    // How many lines the item spans
+}
+
+var (
+	headingRe  = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	listItemRe = regexp.MustCompile(`^(?:[-*+]|\d+[.)])\s+(.*)$`)
+)
+
+// ParseBoard reads a board note. Its columns are its ## headings, or its # headings if it
+// has no ## headings, and its cards the unindented list items under them.
+func ParseBoard(content string) []BoardColumn {
+	lines := strings.Split(content, "\n")
+	start := 0
+	if fm, body := Frontmatter(content); fm != nil {
+		start = len(lines) - len(strings.Split(body, "\n"))
+	}
+	level := 1
+	inFence := false
+	for i := start; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+			inFence = !inFence
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(lines[i]); m != nil && !inFence && len(m[1]) == 2 {
+			level = 2
+			break
+		}
+	}
+
+	var columns []BoardColumn
+	var current *BoardColumn
+	inFence = false
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			switch {
+			case len(m[1]) == level:
+				columns = append(columns, BoardColumn{Title: m[2], Line: i + 1})
+				current = &columns[len(columns)-1]
+			case len(m[1]) < level:
+				// A title above the columns
+				current = nil
+			}
+			continue
+		}
+		m := listItemRe.FindStringSubmatch(line)
+		if current == nil || m == nil {
+			continue
+		}
+		card := BoardCard{Text: m[1], Line: i + 1, Lines: 1}
+		if t, ok := parseTask(line); ok {
+			card.Text, card.Done = t.Text, t.Done
+		}
+		for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" && (lines[i+1][0] == ' ' || lines[i+1][0] == '\t') {
+			i++
+			card.Lines++
+		}
+		current.Cards = append(current.Cards, card)
+	}
+	return columns
+}
+
+// MoveCard moves a card of the board note content to the end of another column. from and
+// to are column indexes and card the card's index in column from.
+func MoveCard(content string, from, card, to int) (string, error) {
+	columns := ParseBoard(content)
+	if from < 0 || from >= len(columns) || to < 0 || to >= len(columns) {
+		return "", fmt.Errorf("no such column")
+	}
+	if card < 0 || card >= len(columns[from].Cards) {
+		return "", fmt.Errorf("no such card")
+	}
+	if from == to {
+		return content, nil
+	}
+	c := columns[from].Cards[card]
+	lines := strings.Split(content, "\n")
+	block := append([]string(nil), lines[c.Line-1:c.Line-1+c.Lines]...)
+
+	// Insert after the last card of the target column, or right below its heading
+	at := columns[to].Line
+	if cards := columns[to].Cards; len(cards) > 0 {
+		last := cards[len(cards)-1]
+		at = last.Line - 1 + last.Lines
+	}
+	lines = append(lines[:c.Line-1], lines[c.Line-1+c.Lines:]...)
+	if at > c.Line-1 {
+		at -= len(block)
+	}
+	lines = append(lines[:at], append(block, lines[at:]...)...)
+	return strings.Join(lines, "\n"), nil
+}
+
+// SetFrontmatterField sets a top-level frontmatter field of a note, keeping the rest of the
+// frontmatter as written. A note without frontmatter gets some.
+func SetFrontmatterField(content, key, value string) string {
+	encoded, err := yaml.Marshal(value)
+	if err != nil {
+		encoded = []byte(value)
+	}
+	field := key + ": " + strings.TrimSpace(string(encoded))
+	if fm, _ := Frontmatter(content); fm == nil {
+		return "---\n" + field + "\n---\n" + content
+	}
+	lines := strings.Split(content, "\n")
+	fieldRe := regexp.MustCompile(`^` + regexp.QuoteMeta(key) + `\s*:`)
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimRight(lines[i], "\r") == "---" {
+			lines = append(lines[:i], append([]string{field}, lines[i:]...)...)
+			break
+		}
+		if fieldRe.MatchString(lines[i]) {
+			lines[i] = field
+			// Drop the lines of a value continued below the key, such as a list
+			end := i + 1
+			for end < len(lines) && (strings.HasPrefix(lines[end], " ") || strings.HasPrefix(lines[end], "\t") || strings.HasPrefix(lines[end], "- ")) {
+				end++
+			}
+			lines = append(lines[:i+1], lines[end:]...)
+			break
+		}
+	}
+	return strings.Join(lines, "\n")
+}
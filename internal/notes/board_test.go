@@ -0,0 +1,133 @@
+package notes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBoard(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []BoardColumn
+	}{
+		{
+			name:    "level two columns",
+			content: "# Sprint\n\n## Todo\n- Write docs\n- [ ] Fix bug\n  with details\n\n## Done\n- [x] Ship it\n",
+			want: []BoardColumn{
+				{Title: "Todo", Line: 3, Cards: []BoardCard{
+					{Text: "Write docs", Line: 4, Lines: 1},
+					{Text: "Fix bug", Line: 5, Lines: 2},
+				}},
+				{Title: "Done", Line: 8, Cards: []BoardCard{{Text: "Ship it", Done: true, Line: 9, Lines: 1}}},
+			},
+		},
+		{
+			name:    "level one columns",
+			content: "# Todo\n1. First\n# Done ##\n",
+			want: []BoardColumn{
+				{Title: "Todo", Line: 1, Cards: []BoardCard{{Text: "First", Line: 2, Lines: 1}}},
+				{Title: "Done", Line: 3},
+			},
+		},
+		{
+			name:    "frontmatter and fences",
+			content: "---\ntitle: Board\n---\n## Todo\n```\n## Not a column\n- not a card\n```\n- Card\n",
+			want: []BoardColumn{
+				{Title: "Todo", Line: 4, Cards: []BoardCard{{Text: "Card", Line: 9, Lines: 1}}},
+			},
+		},
+		{
+			name:    "no headings",
+			content: "- a\n- b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseBoard(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBoard() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoveCard(t *testing.T) {
+	board := "## Todo\n- a\n  note\n- b\n\n## Doing\n\n## Done\n- c\n"
+	tests := []struct {
+		name           string
+		from, card, to int
+		want           string
+		wantErr        bool
+	}{
+		{name: "to an empty column", from: 0, card: 0, to: 1, want: "## Todo\n- b\n\n## Doing\n- a\n  note\n\n## Done\n- c\n"},
+		{name: "after the last card", from: 0, card: 1, to: 2, want: "## Todo\n- a\n  note\n\n## Doing\n\n## Done\n- c\n- b\n"},
+		{name: "back up", from: 2, card: 0, to: 0, want: "## Todo\n- a\n  note\n- b\n- c\n\n## Doing\n\n## Done\n"},
+		{name: "same column", from: 0, card: 1, to: 0, want: board},
+		{name: "no such column", from: 0, card: 0, to: 3, wantErr: true},
+		{name: "no such card", from: 1, card: 0, to: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MoveCard(board, tt.from, tt.card, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MoveCard() error = %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MoveCard() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFrontmatterField(t *testing.T) {
+	tests := []struct {
+		name       string
+		content    string
+		key, value string
+		want       string
+	}{
+		{
+			name:    "replace",
+			content: "---\ntitle: A\nstatus: todo\n---\nBody\n",
+			key:     "status", value: "done",
+			want: "---\ntitle: A\nstatus: done\n---\nBody\n",
+		},
+		{
+			name:    "add",
+			content: "---\ntitle: A\n---\nBody\n",
+			key:     "status", value: "doing",
+			want: "---\ntitle: A\nstatus: doing\n---\nBody\n",
+		},
+		{
+			name:    "no frontmatter",
+			content: "Body\n",
+			key:     "status", value: "todo",
+			want: "---\nstatus: todo\n---\nBody\n",
+		},
+		{
+			name:    "replace a list",
+			content: "---\nstatus:\n  - a\n  - b\ntags: [x]\n---\n",
+			key:     "status", value: "done",
+			want: "---\nstatus: done\ntags: [x]\n---\n",
+		},
+		{
+			name:    "quoted when needed",
+			content: "---\ntitle: A\n---\n",
+			key:     "status", value: "yes: really",
+			want: "---\ntitle: A\nstatus: 'yes: really'\n---\n",
+		},
+		{
+			name:    "similar key left alone",
+			content: "---\nstatus_note: keep\n---\n",
+			key:     "status", value: "done",
+			want: "---\nstatus_note: keep\nstatus: done\n---\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SetFrontmatterField(tt.content, tt.key, tt.value); got != tt.want {
+				t.Errorf("SetFrontmatterField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
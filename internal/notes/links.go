@@ -0,0 +1,128 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+)
+
+// CrossVaultScheme prefixes links that point into another registered vault,
+// e.g. [[vault:Research/Papers/Attention]].
+const CrossVaultScheme = "vault:"
+
+// Link is a [[wiki link]] found in a note.
+type Link struct {
+	Raw     string // The link as written, including brackets
+	Vault   string // Target vault name for cross-vault links, empty for local links
+	Target  string // Note path or name inside the vault, without heading or alias
+	Heading string // Optional #heading
+	Alias   string // Optional |alias
+	Line    int    // 1-based line number
+}
+
+// CrossVault reports whether the link points into another vault.
+func (l Link) CrossVault() bool {
+	return l.Vault != ""
+}
+
+var wikiLinkRe = regexp.MustCompile(`!?\[\[([^\[\]]+?)\]\]`)
+
+// ParseLinks extracts the wiki links of a note, skipping fenced code blocks.
+func ParseLinks(content string) []Link {
+	var links []Link
+	inFence := false
+	for i, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, m := range wikiLinkRe.FindAllStringSubmatch(line, -1) {
+			if l, ok := parseLink(m[1]); ok {
+				l.Raw = m[0]
+				l.Line = i + 1
+				links = append(links, l)
+			}
+		}
+	}
+	return links
+}
+
+func parseLink(inner string) (Link, bool) {
+	var l Link
+	inner, l.Alias, _ = strings.Cut(inner, "|")
+	inner, l.Heading, _ = strings.Cut(inner, "#")
+	inner = strings.TrimSpace(inner)
+	if rest, ok := strings.CutPrefix(inner, CrossVaultScheme); ok {
+		vaultName, target, found := strings.Cut(rest, "/")
+		if !found || vaultName == "" {
+			return l, false
+		}
+		l.Vault = vaultName
+		inner = target
+	}
+	l.Target = strings.TrimSpace(inner)
+	return l, l.Target != ""
+}
+
+// RewriteLinks passes every wiki link of a note to fn and replaces the target of those for
+// which fn returns a new one, keeping headings and aliases. It reports whether anything changed.
+func RewriteLinks(content string, fn func(l Link) (string, bool)) (string, bool) {
+	changed := false
+	content = ReplaceLinks(content, func(l Link, embed bool) string {
+		target, ok := fn(l)
+		if !ok || target == l.Target {
+			return l.Raw
+		}
+		changed = true
+		return formatLink(l, target, embed)
+	})
+	return content, changed
+}
+
+// ReplaceLinks replaces every wiki link of a note outside fenced code blocks with what fn
+// returns for it; embed is set for ![[embedded]] links.
+func ReplaceLinks(content string, fn func(l Link, embed bool) string) string {
+	lines := strings.Split(content, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		lines[i] = wikiLinkRe.ReplaceAllStringFunc(line, func(raw string) string {
+			inner := strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(raw, "!"), "[["), "]]")
+			l, ok := parseLink(inner)
+			if !ok {
+				return raw
+			}
+			l.Raw, l.Line = raw, i+1
+			return fn(l, strings.HasPrefix(raw, "!"))
+		})
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatLink(l Link, target string, embed bool) string {
+	var b strings.Builder
+	if embed {
+		b.WriteString("!")
+	}
+	b.WriteString("[[")
+	if l.CrossVault() {
+		b.WriteString(CrossVaultScheme + l.Vault + "/")
+	}
+	b.WriteString(target)
+	if l.Heading != "" {
+		b.WriteString("#" + l.Heading)
+	}
+	if l.Alias != "" {
+		b.WriteString("|" + l.Alias)
+	}
+	b.WriteString("]]")
+	return b.String()
+}
@@ -0,0 +1,162 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Frontmatter splits a note into its YAML frontmatter, delimited by --- lines at the
+// very start of the note, and the body after it. Notes without valid frontmatter
+// return a nil map and the whole content as body.
+func Frontmatter(content string) (map[string]any, string) {
+	rest, ok := strings.CutPrefix(content, "---\n")
+	if !ok {
+		return nil, content
+	}
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return nil, content
+	}
+	var fm map[string]any
+	if err := yaml.Unmarshal([]byte(rest[:end]), &fm); err != nil {
+		return nil, content
+	}
+	body := rest[end+len("\n---"):]
+	if i := strings.IndexByte(body, '\n'); i >= 0 {
+		body = body[i+1:]
+	} else {
+		body = ""
+	}
+	return fm, body
+}
+
+// inlineTagRe matches #tags preceded by whitespace or the start of a line. Tags may
+// nest with slashes, e.g. #project/noted.
+var inlineTagRe = regexp.MustCompile(`(?:^|\s)#([\p{L}\p{N}_/-]+)`)
+
+var inlineCodeRe = regexp.MustCompile("`[^`]*`")
+
+// ParseTags returns the tags of a note, lower-cased and without the leading #, in the order
+// they first appear: frontmatter tags first, then inline #tags outside code. Purely numeric
+// tags such as #1 are not tags.
+func ParseTags(content string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	add := func(tag string) {
+		tag = strings.ToLower(strings.Trim(strings.TrimPrefix(strings.TrimSpace(tag), "#"), "/"))
+		if tag == "" || seen[tag] || strings.Trim(tag, "0123456789") == "" {
+			return
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
+	}
+
+	fm, body := Frontmatter(content)
+	for _, key := range []string{"tags", "tag"} {
+		switch v := fm[key].(type) {
+		case string:
+			for _, t := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' }) {
+				add(t)
+			}
+		case []any:
+			for _, t := range v {
+				if s, ok := t.(string); ok {
+					add(s)
+				}
+			}
+		}
+	}
+
+	inFence := false
+	for _, line := range strings.Split(body, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		line = inlineCodeRe.ReplaceAllString(line, "")
+		for _, m := range inlineTagRe.FindAllStringSubmatch(line, -1) {
+			add(m[1])
+		}
+	}
+	return tags
+}
+
+// CountWords counts the words in a note's body, leaving out its frontmatter.
+func CountWords(content string) int {
+	_, body := Frontmatter(content)
+	return len(strings.Fields(body))
+}
+
+// RenameTag renames tag old to new, including nested tags below it such as old/child, in
+// the note's frontmatter tags and its inline #tags outside code. Tags match case-insensitively.
+// It reports whether anything changed.
+func RenameTag(content, old, new string) (string, bool) {
+	old = strings.Trim(strings.TrimPrefix(old, "#"), "/")
+	new = strings.Trim(strings.TrimPrefix(new, "#"), "/")
+	tag := regexp.QuoteMeta(old) + `((?:/[\p{L}\p{N}_/-]*)?)`
+	inlineRe := regexp.MustCompile(`(?i)(^|\s)#` + tag + `([^\p{L}\p{N}_/-]|$)`)
+	fmRe := regexp.MustCompile(`(?i)(^|[\s\[,"'#])` + tag + `([\s,\]"']|$)`)
+
+	lines := strings.Split(content, "\n")
+	start := 0
+	if len(lines) > 0 && lines[0] == "---" {
+		inTags := false
+		for i := 1; i < len(lines); i++ {
+			line := lines[i]
+			if line == "---" {
+				start = i + 1
+				break
+			}
+			if key, _, ok := strings.Cut(line, ":"); ok && !strings.HasPrefix(line, " ") && !strings.HasPrefix(line, "-") {
+				inTags = key == "tags" || key == "tag"
+				if inTags {
+					lines[i] = key + ":" + replaceAll(fmRe, line[len(key)+1:], "${1}"+new+"${2}${3}")
+				}
+				continue
+			}
+			if inTags {
+				lines[i] = replaceAll(fmRe, line, "${1}"+new+"${2}${3}")
+			}
+		}
+		if start == 0 {
+			// No closing ---, so there was no frontmatter after all
+			lines = strings.Split(content, "\n")
+		}
+	}
+
+	inFence := false
+	for i := start; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		// Only rename outside `inline code`, which sits in the odd segments
+		segments := strings.Split(lines[i], "`")
+		for s := 0; s < len(segments); s += 2 {
+			segments[s] = replaceAll(inlineRe, segments[s], "${1}#"+new+"${2}${3}")
+		}
+		lines[i] = strings.Join(segments, "`")
+	}
+	out := strings.Join(lines, "\n")
+	return out, out != content
+}
+
+// replaceAll applies re until the text stops changing, since adjacent matches share the
+// delimiter between them and a single pass skips every other one.
+func replaceAll(re *regexp.Regexp, s, repl string) string {
+	for {
+		next := re.ReplaceAllString(s, repl)
+		if next == s {
+			return s
+		}
+		s = next
+	}
+}
@@ -0,0 +1,247 @@
+package notes
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Task is a checkbox item of a note, such as
+//
+//	- [ ] Send the draft to Ana due:2026-10-20 @ana !high #work
+type Task struct {
+	Line     int    // 1-based line number
+	Text     string // The item after its checkbox, metadata included
+	Done     bool
+	Due      time.Time // Midnight of the due: date in local time, zero without one
+	People   []string  // @mentions, without the @
+	Priority string    // high, medium or low, from !high and the like
+	Tags     []string  // #tags, lower-cased and without the #
+	Every    string    // Recurrence rule of every:, e.g. monday or 2w
+	Remind   string    // Time of day of remind:, e.g. 09:30
+}
+
+// HasDue reports whether the task has a due date.
+func (t Task) HasDue() bool {
+	return !t.Due.IsZero()
+}
+
+// taskRe matches list items starting with a checkbox; the groups are the text up to the
+// box's mark, the mark and the item's text.
+var taskRe = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+\[)([ xX])\](?:\s+(.*))?$`)
+
+var (
+	dueRe      = regexp.MustCompile(`(?:^|\s)due:(\d{4}-\d{2}-\d{2})\b`)
+	personRe   = regexp.MustCompile(`(?:^|\s)@([\p{L}\p{N}_.-]*[\p{L}\p{N}_])`)
+	priorityRe = regexp.MustCompile(`(?i)(?:^|\s)!(high|medium|low)\b`)
+	everyRe    = regexp.MustCompile(`(?:^|\s)every:([\p{L}\p{N}]+)`)
+	remindRe   = regexp.MustCompile(`(?:^|\s)remind:(\d{1,2}:\d{2})\b`)
+)
+
+// ParseTasks returns the checkbox items of a note, skipping its frontmatter and fenced
+// code blocks.
+func ParseTasks(content string) []Task {
+	var tasks []Task
+	lines := strings.Split(content, "\n")
+	start := 0
+	if fm, body := Frontmatter(content); fm != nil {
+		start = len(lines) - len(strings.Split(body, "\n"))
+	}
+	inFence := false
+	for i := start; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		if t, ok := parseTask(line); ok {
+			t.Line = i + 1
+			tasks = append(tasks, t)
+		}
+	}
+	return tasks
+}
+
+func parseTask(line string) (Task, bool) {
+	m := taskRe.FindStringSubmatch(line)
+	if m == nil {
+		return Task{}, false
+	}
+	t := Task{Text: strings.TrimSpace(m[3]), Done: m[2] != " "}
+	if t.Text == "" {
+		// An empty checkbox, such as a template's placeholder, is not a task yet
+		return Task{}, false
+	}
+	if d := dueRe.FindStringSubmatch(t.Text); d != nil {
+		if due, err := time.ParseInLocation("2006-01-02", d[1], time.Local); err == nil {
+			t.Due = due
+		}
+	}
+	for _, p := range personRe.FindAllStringSubmatch(t.Text, -1) {
+		t.People = append(t.People, p[1])
+	}
+	if p := priorityRe.FindStringSubmatch(t.Text); p != nil {
+		t.Priority = strings.ToLower(p[1])
+	}
+	if e := everyRe.FindStringSubmatch(t.Text); e != nil {
+		t.Every = strings.ToLower(e[1])
+	}
+	if r := remindRe.FindStringSubmatch(t.Text); r != nil {
+		t.Remind = r[1]
+	}
+	t.Tags = ParseTags(t.Text)
+	return t, true
+}
+
+// SetTaskDone checks or unchecks the task on the 1-based line of content.
+func SetTaskDone(content string, line int, done bool) (string, error) {
+	lines := strings.Split(content, "\n")
+	if line < 1 || line > len(lines) {
+		return "", fmt.Errorf("line %d is out of range", line)
+	}
+	m := taskRe.FindStringSubmatchIndex(lines[line-1])
+	if m == nil {
+		return "", fmt.Errorf("line %d is not a task", line)
+	}
+	mark := " "
+	if done {
+		mark = "x"
+	}
+	text := lines[line-1]
+	lines[line-1] = text[:m[4]] + mark + text[m[5]:]
+	return strings.Join(lines, "\n"), nil
+}
+
+// NextDue returns the day after from that the recurrence rule of every: falls on. Rules are
+// a weekday (monday or mon), day, week, month or year, or a count of days, weeks, months or
+// years such as 3d, 2w, 6m or 1y.
+func NextDue(rule string, from time.Time) (time.Time, error) {
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, from.Location())
+	rule = strings.ToLower(rule)
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if rule == name || rule == name[:3] {
+			days := (int(d)-int(from.Weekday())+6)%7 + 1
+			return from.AddDate(0, 0, days), nil
+		}
+	}
+	n, unit := 1, rule
+	switch rule {
+	case "day", "daily":
+		unit = "d"
+	case "week", "weekly":
+		unit = "w"
+	case "month", "monthly":
+		unit = "m"
+	case "year", "yearly":
+		unit = "y"
+	default:
+		i := strings.IndexFunc(rule, func(r rune) bool { return r < '0' || r > '9' })
+		if i <= 0 {
+			return time.Time{}, fmt.Errorf("unknown recurrence every:%s", rule)
+		}
+		fmt.Sscan(rule[:i], &n)
+		unit = rule[i:]
+	}
+	if n < 1 {
+		return time.Time{}, fmt.Errorf("unknown recurrence every:%s", rule)
+	}
+	switch unit {
+	case "d":
+		return from.AddDate(0, 0, n), nil
+	case "w":
+		return from.AddDate(0, 0, 7*n), nil
+	case "m":
+		return from.AddDate(0, n, 0), nil
+	case "y":
+		return from.AddDate(n, 0, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("unknown recurrence every:%s", rule)
+}
+
+// RepeatTask adds the next occurrence of the recurring task on the 1-based line of content
+// below it: an open copy of the task due on the next day its every: rule falls on, counted
+// from its due date, or from the day of now if it has none, and never before the day of now.
+// If the line below already holds the next occurrence, content is returned as is along with
+// a zero time.
+func RepeatTask(content string, line int, now time.Time) (string, time.Time, error) {
+	lines := strings.Split(content, "\n")
+	t, err := recurringTask(lines, line)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if nextOccurrenceBelow(lines, line, t) {
+		return content, time.Time{}, nil
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	from := today
+	if t.HasDue() {
+		from = t.Due
+	}
+	next, err := NextDue(t.Every, from)
+	// Skip the occurrences that went by while the task stayed open
+	for err == nil && next.Before(today) {
+		next, err = NextDue(t.Every, next)
+	}
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	text := strings.TrimRight(lines[line-1], "\r")
+	m := taskRe.FindStringSubmatchIndex(text)
+	repeated := text[:m[4]] + " " + text[m[5]:]
+	due := "due:" + next.Format("2006-01-02")
+	if loc := dueRe.FindStringSubmatchIndex(repeated); loc != nil {
+		repeated = repeated[:loc[2]-len("due:")] + due + repeated[loc[3]:]
+	} else {
+		repeated += " " + due
+	}
+	lines = append(lines[:line], append([]string{repeated}, lines[line:]...)...)
+	return strings.Join(lines, "\n"), next, nil
+}
+
+// UnrepeatTask removes the open next occurrence RepeatTask added below the recurring task on
+// the 1-based line of content, if it is still there, and reports whether it did.
+func UnrepeatTask(content string, line int) (string, bool, error) {
+	lines := strings.Split(content, "\n")
+	t, err := recurringTask(lines, line)
+	if err != nil {
+		return "", false, err
+	}
+	if !nextOccurrenceBelow(lines, line, t) {
+		return content, false, nil
+	}
+	lines = append(lines[:line], lines[line+1:]...)
+	return strings.Join(lines, "\n"), true, nil
+}
+
+// recurringTask parses the task on the 1-based line of lines, which must have an every: rule.
+func recurringTask(lines []string, line int) (Task, error) {
+	if line < 1 || line > len(lines) {
+		return Task{}, fmt.Errorf("line %d is out of range", line)
+	}
+	t, ok := parseTask(strings.TrimRight(lines[line-1], "\r"))
+	if !ok {
+		return Task{}, fmt.Errorf("line %d is not a task", line)
+	}
+	if t.Every == "" {
+		return Task{}, fmt.Errorf("line %d is not a recurring task", line)
+	}
+	return t, nil
+}
+
+// nextOccurrenceBelow reports whether the line below the 1-based line holds an open copy of
+// the recurring task t, whatever it is due.
+func nextOccurrenceBelow(lines []string, line int, t Task) bool {
+	if line >= len(lines) {
+		return false
+	}
+	below, ok := parseTask(strings.TrimRight(lines[line], "\r"))
+	if !ok || below.Done || below.Every != t.Every {
+		return false
+	}
+	return strings.TrimSpace(dueRe.ReplaceAllString(below.Text, "")) == strings.TrimSpace(dueRe.ReplaceAllString(t.Text, ""))
+}
@@ -0,0 +1,223 @@
+package notes
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseTasks(t *testing.T) {
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)
+	tests := []struct {
+		name    string
+		content string
+		want    []Task
+	}{
+		{
+			name:    "metadata",
+			content: "- [ ] Send the draft due:2026-10-20 @ana !high #work",
+			want: []Task{{
+				Line:     1,
+				Text:     "Send the draft due:2026-10-20 @ana !high #work",
+				Due:      due,
+				People:   []string{"ana"},
+				Priority: "high",
+				Tags:     []string{"work"},
+			}},
+		},
+		{
+			name:    "done and numbered",
+			content: "1. [x] Shipped\n  * [X] Nested",
+			want:    []Task{{Line: 1, Text: "Shipped", Done: true}, {Line: 2, Text: "Nested", Done: true}},
+		},
+		{
+			name:    "recurrence and reminder",
+			content: "- [ ] Water plants every:2W remind:08:30",
+			want:    []Task{{Line: 1, Text: "Water plants every:2W remind:08:30", Every: "2w", Remind: "08:30"}},
+		},
+		{
+			name:    "empty checkboxes",
+			content: "- [ ]\n- [ ]   \n- [x] \n- [ ] Real",
+			want:    []Task{{Line: 4, Text: "Real"}},
+		},
+		{
+			name:    "template placeholders",
+			content: "# {{title}}\n\n## Tasks\n\n- [ ] \n",
+			want:    nil,
+		},
+		{
+			name:    "frontmatter and fences",
+			content: "---\ntodo: - [ ] not a task\n---\n```\n- [ ] code\n```\n- [ ] Task",
+			want:    []Task{{Line: 7, Text: "Task"}},
+		},
+		{
+			name:    "not tasks",
+			content: "- [] nope\n-[ ] nope\n[ ] nope\n- [y] nope",
+			want:    nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseTasks(tt.content)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseTasks() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func date(s string) time.Time {
+	d, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestNextDue(t *testing.T) {
+	// 2026-10-18 is a Sunday
+	tests := []struct {
+		rule string
+		from string
+		want string
+	}{
+		{"monday", "2026-10-18", "2026-10-19"},
+		{"mon", "2026-10-19", "2026-10-26"},
+		{"sunday", "2026-10-18", "2026-10-25"},
+		{"Friday", "2026-10-18", "2026-10-23"},
+		{"day", "2026-10-18", "2026-10-19"},
+		{"daily", "2026-12-31", "2027-01-01"},
+		{"week", "2026-10-18", "2026-10-25"},
+		{"month", "2026-10-18", "2026-11-18"},
+		{"year", "2026-10-18", "2027-10-18"},
+		{"3d", "2026-10-18", "2026-10-21"},
+		{"2w", "2026-10-18", "2026-11-01"},
+		{"6m", "2026-10-18", "2027-04-18"},
+		{"1y", "2024-02-29", "2025-03-01"},
+	}
+	for _, tt := range tests {
+		got, err := NextDue(tt.rule, date(tt.from))
+		if err != nil {
+			t.Errorf("NextDue(%q, %s) error: %v", tt.rule, tt.from, err)
+			continue
+		}
+		if !got.Equal(date(tt.want)) {
+			t.Errorf("NextDue(%q, %s) = %s, want %s", tt.rule, tt.from, got.Format("2006-01-02"), tt.want)
+		}
+	}
+	for _, rule := range []string{"", "fortnight", "0d", "d", "3x", "-2w"} {
+		if _, err := NextDue(rule, date("2026-10-18")); err == nil {
+			t.Errorf("NextDue(%q) accepted an invalid rule", rule)
+		}
+	}
+}
+
+func TestRepeatTask(t *testing.T) {
+	now := date("2026-10-18").Add(15 * time.Hour)
+	tests := []struct {
+		name     string
+		content  string
+		line     int
+		want     string
+		wantNext string
+	}{
+		{
+			name:     "from the due date",
+			content:  "# Chores\n- [x] Water plants due:2026-10-18 every:2d\n- [ ] Other",
+			line:     2,
+			want:     "# Chores\n- [x] Water plants due:2026-10-18 every:2d\n- [ ] Water plants due:2026-10-20 every:2d\n- [ ] Other",
+			wantNext: "2026-10-20",
+		},
+		{
+			name:     "overdue occurrences skipped",
+			content:  "- [x] Stretch due:2026-10-10 every:3d",
+			line:     1,
+			want:     "- [x] Stretch due:2026-10-10 every:3d\n- [ ] Stretch due:2026-10-19 every:3d",
+			wantNext: "2026-10-19",
+		},
+		{
+			name:     "due today is not in the past",
+			content:  "- [x] Stretch due:2026-10-15 every:3d",
+			line:     1,
+			want:     "- [x] Stretch due:2026-10-15 every:3d\n- [ ] Stretch due:2026-10-18 every:3d",
+			wantNext: "2026-10-18",
+		},
+		{
+			name:     "without a due date",
+			content:  "  * [X] Review every:monday #work",
+			line:     1,
+			want:     "  * [X] Review every:monday #work\n  * [ ] Review every:monday #work due:2026-10-19",
+			wantNext: "2026-10-19",
+		},
+		{
+			name:     "next occurrence already there",
+			content:  "- [x] Water plants due:2026-10-18 every:2d\n- [ ] Water plants due:2026-10-20 every:2d",
+			line:     1,
+			want:     "- [x] Water plants due:2026-10-18 every:2d\n- [ ] Water plants due:2026-10-20 every:2d",
+			wantNext: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, next, err := RepeatTask(tt.content, tt.line, now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("RepeatTask() =\n%s\nwant\n%s", got, tt.want)
+			}
+			gotNext := ""
+			if !next.IsZero() {
+				gotNext = next.Format("2006-01-02")
+			}
+			if gotNext != tt.wantNext {
+				t.Errorf("RepeatTask() next = %q, want %q", gotNext, tt.wantNext)
+			}
+		})
+	}
+	for _, content := range []string{"- [x] Not recurring", "Not a task", ""} {
+		if _, _, err := RepeatTask(content, 1, now); err == nil {
+			t.Errorf("RepeatTask(%q) = nil error, want one", content)
+		}
+	}
+	if _, _, err := RepeatTask("- [ ] A every:day", 2, now); err == nil {
+		t.Error("RepeatTask() accepted an out of range line")
+	}
+}
+
+func TestToggleRecurringTwice(t *testing.T) {
+	now := date("2026-10-18")
+	content := "- [ ] Rec due:2026-10-10 every:3d\n- [ ] Other"
+	for i := 0; i < 2; i++ {
+		done, err := SetTaskDone(content, 1, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if content, _, err = RepeatTask(done, 1, now); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			reopened, err := SetTaskDone(content, 1, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var removed bool
+			if content, removed, err = UnrepeatTask(reopened, 1); err != nil || !removed {
+				t.Fatalf("UnrepeatTask() = %v, %v; want the occurrence removed", removed, err)
+			}
+			if content != "- [ ] Rec due:2026-10-10 every:3d\n- [ ] Other" {
+				t.Fatalf("UnrepeatTask() =\n%s", content)
+			}
+		}
+	}
+	want := "- [x] Rec due:2026-10-10 every:3d\n- [ ] Rec due:2026-10-19 every:3d\n- [ ] Other"
+	if content != want {
+		t.Errorf("off, on, off =\n%s\nwant\n%s", content, want)
+	}
+	// A checked-off or different task below is not the next occurrence
+	for _, below := range []string{"- [x] Rec due:2026-10-19 every:3d", "- [ ] Rec due:2026-10-19 every:2d", "- [ ] Other"} {
+		if _, removed, _ := UnrepeatTask("- [ ] Rec due:2026-10-10 every:3d\n"+below, 1); removed {
+			t.Errorf("UnrepeatTask() removed %q", below)
+		}
+	}
+}
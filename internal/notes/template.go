@@ -0,0 +1,20 @@
+package notes
+
+import (
+	"regexp"
+	"strings"
+)
+
+var templateVarRe = regexp.MustCompile(`\{\{\s*([a-zA-Z_]+)\s*\}\}`)
+
+// RenderTemplate fills in the {{name}} placeholders of a template from vars. Unknown
+// placeholders are left as they are.
+func RenderTemplate(tmpl string, vars map[string]string) string {
+	return templateVarRe.ReplaceAllStringFunc(tmpl, func(m string) string {
+		name := strings.ToLower(templateVarRe.FindStringSubmatch(m)[1])
+		if v, ok := vars[name]; ok {
+			return v
+		}
+		return m
+	})
+}
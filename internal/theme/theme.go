@@ -0,0 +1,354 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"gopkg.in/yaml.v3"
+)
+
+// Default is the theme used when none is configured.
+const Default = "dark"
+
+// HeatmapLevels is how many shades a theme gives the activity heatmap, from no activity
+// to the busiest days.
+const HeatmapLevels = 5
+
+// Color is a theme color. Built-in themes pick a value for every color depth, so
+// 16-color terminals get hand-picked colors instead of approximations. Theme files may
+// give a single value instead, a 256-color number or a #rrggbb hex color, which is then
+// degraded automatically.
+type Color struct {
+	TrueColor string `yaml:"truecolor,omitempty"`
+	ANSI256   string `yaml:"ansi256,omitempty"`
+	ANSI      string `yaml:"ansi,omitempty"`
+}
+
+// UnmarshalYAML accepts either a single color or a mapping of truecolor, ansi256 and ansi.
+func (c *Color) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		*c = Color{TrueColor: n.Value}
+		return nil
+	}
+	type plain Color
+	return n.Decode((*plain)(c))
+}
+
+// IsZero reports whether the color is unset, leaving the terminal's default color.
+func (c Color) IsZero() bool {
+	return c == Color{}
+}
+
+// Lipgloss converts the color for use in lipgloss styles.
+func (c Color) Lipgloss() lipgloss.TerminalColor {
+	switch {
+	case c.IsZero():
+		return lipgloss.NoColor{}
+	case c.ANSI256 == "" && c.ANSI == "":
+		return lipgloss.Color(c.TrueColor)
+	}
+	full := lipgloss.CompleteColor{TrueColor: c.TrueColor, ANSI256: c.ANSI256, ANSI: c.ANSI}
+	// Fill in missing depths from the deeper ones, which lipgloss degrades as needed
+	if full.TrueColor == "" {
+		full.TrueColor = full.ANSI256
+	}
+	if full.ANSI256 == "" {
+		full.ANSI256 = full.TrueColor
+	}
+	if full.ANSI == "" {
+		full.ANSI = full.ANSI256
+	}
+	return full
+}
+
+// Theme is the palette every TUI component draws from. Colors are named by role rather
+// than hue, so a theme can map them freely.
+type Theme struct {
+	Name        string `yaml:"-"`
+	Description string `yaml:"description,omitempty"`
+
+	Primary    Color   `yaml:"primary"`    // Headers, borders and the selection background
+	OnPrimary  Color   `yaml:"on_primary"` // Text on the selection background
+	Accent     Color   `yaml:"accent"`     // Buttons, bars and modal borders
+	Text       Color   `yaml:"text"`       // Regular list items; unset keeps the terminal's color
+	Strong     Color   `yaml:"strong"`     // Emphasized values, e.g. in the stats dashboard
+	Muted      Color   `yaml:"muted"`      // Help, labels and section headings
+	Error      Color   `yaml:"error"`
+	Success    Color   `yaml:"success"`
+	Warning    Color   `yaml:"warning"`   // Marks such as bookmarks
+	Highlight  Color   `yaml:"highlight"` // Characters matched by a filter
+	Surface    Color   `yaml:"surface"`   // Background of modals
+	Heatmap    []Color `yaml:"heatmap"`
+	FaintMuted bool    `yaml:"-"` // Render muted text faint as well
+}
+
+// Light reports whether t is meant for light terminals, judging by how bright its
+// surface color is.
+func (t Theme) Light() bool {
+	hex, ok := strings.CutPrefix(t.Surface.TrueColor, "#")
+	if !ok || len(hex) != 6 {
+		return false
+	}
+	var r, g, b int
+	if _, err := fmt.Sscanf(hex, "%02x%02x%02x", &r, &g, &b); err != nil {
+		return false
+	}
+	return 299*r+587*g+114*b > 128*1000
+}
+
+func c(trueColor, ansi256, ansi string) Color {
+	return Color{TrueColor: trueColor, ANSI256: ansi256, ANSI: ansi}
+}
+
+// Builtins are the themes that ship with noted.
+var Builtins = []Theme{
+	{
+		Name:        "dark",
+		Description: "Purple accents for dark terminals",
+		Primary:     c("#5f5fff", "63", "12"),
+		OnPrimary:   c("#ffffd7", "230", "15"),
+		Accent:      c("#875fff", "99", "13"),
+		Strong:      c("#ffffd7", "230", "15"),
+		Muted:       c("#8a8a8a", "245", "8"),
+		Error:       c("#ff0000", "9", "9"),
+		Success:     c("#00ff00", "10", "10"),
+		Warning:     c("#ffd700", "220", "11"),
+		Highlight:   c("#ff87d7", "212", "13"),
+		Surface:     c("#303030", "236", "0"),
+		Heatmap: []Color{
+			c("#3a3a3a", "237", "8"),
+			c("#005f00", "22", "2"),
+			c("#008700", "28", "2"),
+			c("#00af00", "34", "10"),
+			c("#00ff00", "46", "10"),
+		},
+		FaintMuted: true,
+	},
+	{
+		Name:        "light",
+		Description: "Deep blues for light terminals",
+		Primary:     c("#005faf", "25", "4"),
+		OnPrimary:   c("#ffffff", "231", "15"),
+		Accent:      c("#8700af", "91", "5"),
+		Strong:      c("#262626", "235", "0"),
+		Muted:       c("#767676", "243", "8"),
+		Error:       c("#d70000", "160", "1"),
+		Success:     c("#008700", "28", "2"),
+		Warning:     c("#af8700", "136", "3"),
+		Highlight:   c("#d70087", "162", "5"),
+		Surface:     c("#e4e4e4", "254", "7"),
+		Heatmap: []Color{
+			c("#dadada", "253", "7"),
+			c("#afd7af", "151", "10"),
+			c("#87d787", "114", "10"),
+			c("#00af00", "34", "2"),
+			c("#005f00", "22", "2"),
+		},
+		FaintMuted: true,
+	},
+	{
+		Name:        "high-contrast",
+		Description: "Bright, unfaded colors on black for maximum legibility",
+		Primary:     c("#ffff00", "226", "11"),
+		OnPrimary:   c("#000000", "16", "0"),
+		Accent:      c("#00ffff", "51", "14"),
+		Text:        c("#ffffff", "231", "15"),
+		Strong:      c("#ffffff", "231", "15"),
+		Muted:       c("#d0d0d0", "252", "7"),
+		Error:       c("#ff5f5f", "203", "9"),
+		Success:     c("#00ff00", "46", "10"),
+		Warning:     c("#ffaf00", "214", "11"),
+		Highlight:   c("#ff00ff", "201", "13"),
+		Surface:     c("#000000", "16", "0"),
+		Heatmap: []Color{
+			c("#585858", "240", "8"),
+			c("#00875f", "29", "2"),
+			c("#00d75f", "41", "10"),
+			c("#87ff87", "120", "10"),
+			c("#ffffff", "231", "15"),
+		},
+	},
+}
+
+// Builtin returns the built-in theme called name.
+func Builtin(name string) (Theme, bool) {
+	for _, t := range Builtins {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Theme{}, false
+}
+
+// Dir returns the folder holding user themes in the config directory configDir.
+func Dir(configDir string) string {
+	return filepath.Join(configDir, "themes")
+}
+
+// file is a user theme file: a set of colors on top of the theme it extends.
+type file struct {
+	Extends string `yaml:"extends"`
+	Theme   `yaml:",inline"`
+	// FaintMuted is a pointer here so a file can tell "false" from "not set"
+	FaintMuted *bool `yaml:"faint_muted"`
+}
+
+// Load returns the theme called name: a user theme from dir (name.yaml or name.yml),
+// which takes precedence, or a built-in one.
+func Load(dir, name string) (Theme, error) {
+	return load(dir, name, map[string]bool{})
+}
+
+func load(dir, name string, seen map[string]bool) (Theme, error) {
+	path, err := userThemePath(dir, name)
+	if seen[name] {
+		// A user theme may extend the built-in theme it replaces, but nothing else twice
+		err = os.ErrNotExist
+		if _, ok := Builtin(name); !ok {
+			return Theme{}, fmt.Errorf("theme %q extends itself", name)
+		}
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		if t, ok := Builtin(name); ok {
+			return t, nil
+		}
+		return Theme{}, fmt.Errorf("unknown theme %q (built-in themes: %s; user themes go in %s)", name, strings.Join(builtinNames(), ", "), dir)
+	}
+	if err != nil {
+		return Theme{}, err
+	}
+	seen[name] = true
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Theme{}, err
+	}
+	var f file
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", path, err)
+	}
+	if len(f.Heatmap) != 0 && len(f.Heatmap) != HeatmapLevels {
+		return Theme{}, fmt.Errorf("theme %s: heatmap needs %d colors, got %d", path, HeatmapLevels, len(f.Heatmap))
+	}
+	base := f.Extends
+	if base == "" {
+		base = Default
+	}
+	t, err := load(dir, base, seen)
+	if err != nil {
+		return Theme{}, fmt.Errorf("theme %s: %w", path, err)
+	}
+	t.overlay(f.Theme)
+	if f.FaintMuted != nil {
+		t.FaintMuted = *f.FaintMuted
+	}
+	t.Name = name
+	if f.Description != "" {
+		t.Description = f.Description
+	} else {
+		t.Description = "Based on " + base
+	}
+	return t, nil
+}
+
+// overlay replaces the colors of t with those set in o.
+func (t *Theme) overlay(o Theme) {
+	for _, pair := range []struct{ dst, src *Color }{
+		{&t.Primary, &o.Primary}, {&t.OnPrimary, &o.OnPrimary}, {&t.Accent, &o.Accent},
+		{&t.Text, &o.Text}, {&t.Strong, &o.Strong}, {&t.Muted, &o.Muted},
+		{&t.Error, &o.Error}, {&t.Success, &o.Success}, {&t.Warning, &o.Warning},
+		{&t.Highlight, &o.Highlight}, {&t.Surface, &o.Surface},
+	} {
+		if !pair.src.IsZero() {
+			*pair.dst = *pair.src
+		}
+	}
+	if len(o.Heatmap) == HeatmapLevels {
+		t.Heatmap = o.Heatmap
+	}
+}
+
+func userThemePath(dir, name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) {
+		return "", os.ErrNotExist
+	}
+	for _, ext := range []string{".yaml", ".yml"} {
+		path := filepath.Join(dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", os.ErrNotExist
+}
+
+func builtinNames() []string {
+	names := make([]string, len(Builtins))
+	for i, t := range Builtins {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Info describes an available theme.
+type Info struct {
+	Name        string
+	Description string
+	Path        string // Theme file, empty for built-in themes
+	Err         error  // Why a theme file could not be loaded
+}
+
+// List returns the built-in themes followed by the user themes in dir, sorted by name.
+// A user theme named like a built-in one replaces it.
+func List(dir string) []Info {
+	var infos []Info
+	users := map[string]bool{}
+	entries, _ := os.ReadDir(dir)
+	var user []Info
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		name := strings.TrimSuffix(e.Name(), ext)
+		if users[name] {
+			continue
+		}
+		users[name] = true
+		info := Info{Name: name, Path: filepath.Join(dir, e.Name())}
+		if t, err := Load(dir, name); err != nil {
+			info.Err = err
+		} else {
+			info.Description = t.Description
+		}
+		user = append(user, info)
+	}
+	for _, t := range Builtins {
+		if !users[t.Name] {
+			infos = append(infos, Info{Name: t.Name, Description: t.Description})
+		}
+	}
+	sort.Slice(user, func(i, j int) bool { return user[i].Name < user[j].Name })
+	return append(infos, user...)
+}
+
+// Template renders a theme file that extends base and spells out all of its colors,
+// ready to be edited.
+func Template(base Theme) ([]byte, error) {
+	faint := base.FaintMuted
+	base.Description = "Based on " + base.Name
+	data, err := yaml.Marshal(file{Extends: base.Name, Theme: base, FaintMuted: &faint})
+	if err != nil {
+		return nil, err
+	}
+	header := "# noted theme. Colors are a 256-color number, a #rrggbb hex color, or a mapping\n" +
+		"# of truecolor, ansi256 and ansi (0-15) values for terminals of each color depth.\n" +
+		"# Leave a color out to keep the one from the theme this file extends.\n"
+	return append([]byte(header), data...), nil
+}
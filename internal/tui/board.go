@@ -0,0 +1,321 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// boardMinColumnWidth is the narrowest a board column gets; boards with more columns than
+// fit scroll sideways.
+const boardMinColumnWidth = 24
+
+// boardCardsTop is the screen line of the first card of a column, below the board's header,
+// the column's border and its title.
+const boardCardsTop = 3
+
+// BoardCard is a card on the kanban board.
+type BoardCard struct {
+	Title  string
+	Detail string // Shown below the title of the highlighted card, e.g. the note's path
+	Done   bool
+}
+
+// BoardColumn is a column of the kanban board.
+type BoardColumn struct {
+	Title string
+	Cards []BoardCard
+}
+
+// BoardOptions describe the board to show.
+type BoardOptions struct {
+	Title   string
+	Columns []BoardColumn
+	// Move moves a card of column from to the end of column to in the board's source and
+	// returns the board as it now is.
+	Move func(from, card, to int) ([]BoardColumn, error)
+}
+
+// BoardResult tells what happened on the board.
+type BoardResult struct {
+	Moved int // How many times a card was moved
+	// Open is set when the user picked Card of Column to open it.
+	Open   bool
+	Column int
+	Card   int
+}
+
+// LaunchBoard shows a kanban board until the user quits or picks a card.
+func LaunchBoard(opts BoardOptions) (BoardResult, error) {
+	p := tea.NewProgram(newBoardModel(opts), programOptions()...)
+	finalModel, err := p.Run()
+	if err != nil {
+		return BoardResult{}, err
+	}
+	return finalModel.(boardModel).result, nil
+}
+
+type boardModel struct {
+	title   string
+	columns []BoardColumn
+	move    func(from, card, to int) ([]BoardColumn, error)
+	col     int   // Highlighted column
+	cursors []int // Highlighted card of each column
+	offsets []int // First card shown of each column
+	first   int   // First column shown
+	width   int
+	height  int
+	err     string
+	result  BoardResult
+}
+
+func newBoardModel(opts BoardOptions) boardModel {
+	m := boardModel{title: opts.Title, move: opts.Move, width: 100, height: 24}
+	m.setColumns(opts.Columns)
+	return m
+}
+
+func (m boardModel) Init() tea.Cmd {
+	return nil
+}
+
+// setColumns replaces the board's columns, keeping the highlighted cards where possible.
+func (m *boardModel) setColumns(columns []BoardColumn) {
+	m.columns = columns
+	for len(m.cursors) < len(columns) {
+		m.cursors = append(m.cursors, 0)
+		m.offsets = append(m.offsets, 0)
+	}
+	m.cursors, m.offsets = m.cursors[:len(columns)], m.offsets[:len(columns)]
+	m.fix()
+}
+
+// columnWidth is the width of a column, borders included.
+func (m boardModel) columnWidth() int {
+	if len(m.columns) == 0 {
+		return m.width
+	}
+	w := m.width / len(m.columns)
+	if w < boardMinColumnWidth {
+		w = boardMinColumnWidth
+	}
+	return w
+}
+
+// shownColumns is how many columns fit side by side.
+func (m boardModel) shownColumns() int {
+	n := m.width / m.columnWidth()
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// cardRows is how many cards fit in a column.
+func (m boardModel) cardRows() int {
+	// Header, column borders and title, help bar and a spare line
+	rows := m.height - 6
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// fix keeps the highlighted column and cards in range and in view.
+func (m *boardModel) fix() {
+	if m.col >= len(m.columns) {
+		m.col = len(m.columns) - 1
+	}
+	if m.col < 0 {
+		m.col = 0
+	}
+	if m.col < m.first {
+		m.first = m.col
+	}
+	if shown := m.shownColumns(); m.col >= m.first+shown {
+		m.first = m.col - shown + 1
+	}
+	rows := m.cardRows()
+	for i, c := range m.columns {
+		if m.cursors[i] >= len(c.Cards) {
+			m.cursors[i] = len(c.Cards) - 1
+		}
+		if m.cursors[i] < 0 {
+			m.cursors[i] = 0
+		}
+		if m.cursors[i] < m.offsets[i] {
+			m.offsets[i] = m.cursors[i]
+		}
+		if m.cursors[i] >= m.offsets[i]+rows {
+			m.offsets[i] = m.cursors[i] - rows + 1
+		}
+	}
+}
+
+// hasCard reports whether the highlighted column has a card to act on.
+func (m boardModel) hasCard() bool {
+	return m.col < len(m.columns) && len(m.columns[m.col].Cards) > 0
+}
+
+// moveCard moves the highlighted card delta columns over and follows it there.
+func (m *boardModel) moveCard(delta int) {
+	to := m.col + delta
+	if !m.hasCard() || to < 0 || to >= len(m.columns) {
+		return
+	}
+	columns, err := m.move(m.col, m.cursors[m.col], to)
+	if err != nil {
+		m.err = "✗ " + err.Error()
+		return
+	}
+	m.err = ""
+	m.result.Moved++
+	m.col = to
+	// Moved cards land at the end of their new column
+	m.cursors[to] = len(columns[to].Cards) - 1
+	m.setColumns(columns)
+}
+
+func (m boardModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.fix()
+	case tea.MouseMsg:
+		if delta := wheelDelta(msg); delta != 0 {
+			if m.col < len(m.columns) {
+				m.cursors[m.col] += delta
+			}
+			m.fix()
+			return m, nil
+		}
+		if !clicked(msg) || len(m.columns) == 0 {
+			return m, nil
+		}
+		// A click highlights a column and the card under it, and a second click opens the card
+		col := m.first + msg.X/m.columnWidth()
+		if col >= len(m.columns) || col >= m.first+m.shownColumns() {
+			return m, nil
+		}
+		if card := m.offsets[col] + msg.Y - boardCardsTop; msg.Y >= boardCardsTop && card < len(m.columns[col].Cards) {
+			if col == m.col && card == m.cursors[col] {
+				m.result.Open = true
+				m.result.Column, m.result.Card = col, card
+				return m, tea.Quit
+			}
+			m.cursors[col] = card
+		}
+		m.col = col
+		m.fix()
+	case tea.KeyMsg:
+		switch {
+		case matchKey(msg, false, keymap.Back, keymap.Quit):
+			return m, tea.Quit
+		case matchKey(msg, false, keymap.Select):
+			if m.hasCard() {
+				m.result.Open = true
+				m.result.Column, m.result.Card = m.col, m.cursors[m.col]
+				return m, tea.Quit
+			}
+		case matchKey(msg, false, keymap.MoveLeft):
+			m.moveCard(-1)
+		case matchKey(msg, false, keymap.MoveRight):
+			m.moveCard(1)
+		case matchKey(msg, false, keymap.Left):
+			m.col--
+			m.fix()
+		case matchKey(msg, false, keymap.Right):
+			m.col++
+			m.fix()
+		case matchKey(msg, false, keymap.Up) && m.col < len(m.columns):
+			m.cursors[m.col]--
+			m.fix()
+		case matchKey(msg, false, keymap.Down) && m.col < len(m.columns):
+			m.cursors[m.col]++
+			m.fix()
+		}
+	}
+	return m, nil
+}
+
+func (m boardModel) View() string {
+	cards := 0
+	for _, c := range m.columns {
+		cards += len(c.Cards)
+	}
+	header := headerStyle.Render("▦ "+m.title) + helpBarStyle.Render(fmt.Sprintf("%d %s", cards, pluralCards(cards)))
+	if m.err != "" {
+		header += " " + errorStyle.Render(m.err)
+	}
+	var columns []string
+	end := m.first + m.shownColumns()
+	if end > len(m.columns) {
+		end = len(m.columns)
+	}
+	for i := m.first; i < end; i++ {
+		columns = append(columns, m.renderColumn(i))
+	}
+	board := lipgloss.JoinHorizontal(lipgloss.Top, columns...)
+	if len(m.columns) == 0 {
+		board = helpBarStyle.Render("This board has no columns.")
+	}
+	scroll := ""
+	if m.first > 0 || end < len(m.columns) {
+		scroll = fmt.Sprintf("   columns %d-%d of %d", m.first+1, end, len(m.columns))
+	}
+	help := helpText(false, keyHelp("Column", keymap.Left, keymap.Right), keyHelp("Card", keymap.Up, keymap.Down), keyHelp("Move card", keymap.MoveLeft, keymap.MoveRight), keyHelp("Open", keymap.Select), keyHelp("Quit", keymap.Back, keymap.Quit))
+	return header + "\n" + board + "\n" + helpBarStyle.Render(help+scroll)
+}
+
+func (m boardModel) renderColumn(i int) string {
+	c := m.columns[i]
+	width := m.columnWidth() - 4 // Borders and padding
+	style := boardColumnStyle
+	if i == m.col {
+		style = boardActiveColumnStyle
+	}
+	title := headerStyle.Padding(0).Render(truncate(c.Title, width-4)) + helpBarStyle.Render(fmt.Sprint(len(c.Cards)))
+	lines := []string{title}
+	rows := m.cardRows()
+	end := m.offsets[i] + rows
+	if end > len(c.Cards) {
+		end = len(c.Cards)
+	}
+	for j := m.offsets[i]; j < end; j++ {
+		card := c.Cards[j]
+		text := card.Title
+		if card.Done {
+			text = "✓ " + text
+		}
+		text = truncate(text, width)
+		switch {
+		case i == m.col && j == m.cursors[i]:
+			lines = append(lines, selectedStyle.Width(width).Render(text))
+		case card.Done:
+			lines = append(lines, dirPickerDimStyle.Render(text))
+		default:
+			lines = append(lines, dirPickerItemStyle.Render(text))
+		}
+	}
+	if len(c.Cards) == 0 {
+		lines = append(lines, dirPickerDimStyle.Render("(empty)"))
+	}
+	for len(lines) < rows+1 {
+		lines = append(lines, "")
+	}
+	if i == m.col && len(c.Cards) > 0 {
+		if detail := c.Cards[m.cursors[i]].Detail; detail != "" {
+			lines[len(lines)-1] = helpBarStyle.Padding(0).Render(truncateStart(detail, width))
+		}
+	}
+	return style.Width(width + 2).Render(strings.Join(lines, "\n"))
+}
+
+func pluralCards(n int) string {
+	if n == 1 {
+		return "card"
+	}
+	return "cards"
+}
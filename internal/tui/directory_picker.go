@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
@@ -8,173 +9,585 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	textinput "github.com/charmbracelet/bubbles/textinput"
-	"github.com/charmbracelet/lipgloss"
 )
 
-var (
-	dirPickerHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63")).Padding(0, 1)
-	dirPickerSelectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("230")).Background(lipgloss.Color("63")).Bold(true)
-	dirPickerItemStyle = lipgloss.NewStyle().Padding(0, 1)
-	dirPickerBorderStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("63")).Padding(1, 2)
-	dirPickerErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
-)
+const browsePlaceholder = "Type to filter, or a path like ~/Documents"
+
+// dirPickerHeight is how many rows the picker shows when the terminal size is unknown.
+const dirPickerHeight = 12
+
+// dirPickerRowsTop is the screen line of the first row shown, below the title and the
+// input box.
+const dirPickerRowsTop = 4
+
+// DirectoryPickerOptions seed the directory picker with places the user keeps coming back to.
+type DirectoryPickerOptions struct {
+	Start     string   // Directory to start browsing in, the home directory if empty
+	Bookmarks []string // Bookmarked directories, toggled with ctrl+b
+	Recent    []string // Recently chosen directories, most recent first
+}
 
 type DirectoryPickerResult struct {
 	Path      string
 	Cancelled bool
 	Err       error
+
+	// Bookmarks is the bookmark list after the user's changes, if BookmarksChanged.
+	Bookmarks        []string
+	BookmarksChanged bool
+}
+
+// pickerView is what the picker lists: the folders of the browsed directory, or the
+// bookmarked and recent places.
+type pickerView int
+
+const (
+	viewBrowse pickerView = iota
+	viewPlaces
+)
+
+// pickerRow is a selectable directory or a section heading.
+type pickerRow struct {
+	heading   string
+	label     string
+	path      string
+	positions []int // Runes of label matched by the filter
 }
 
 type directoryPickerModel struct {
-	input         textinput.Model
-	dirs          []string
-	filteredDirs  []string
-	selectedIdx   int
-	result        DirectoryPickerResult
-	inputError    string
-	state         int // 0: input, 1: done
+	dir        string          // Directory being browsed
+	input      textinput.Model // Fuzzy filter, or a path to jump to
+	newDir     textinput.Model // Name of the folder being created inline
+	creating   bool
+	names      []string // Subdirectories of dir
+	rows       []pickerRow
+	cursor     int // Index into rows, always on a directory
+	offset     int // First row shown
+	height     int
+	showHidden bool
+	view       pickerView
+	bookmarks  []string
+	recent     []string
+	result     DirectoryPickerResult
+	inputError string
+	state      int // 0: input, 1: done
 }
 
-func NewDirectoryPickerModel() directoryPickerModel {
+func NewDirectoryPickerModel(opts DirectoryPickerOptions) directoryPickerModel {
 	ti := textinput.New()
-	ti.Placeholder = "~/Documents"
+	ti.Placeholder = browsePlaceholder
 	ti.CharLimit = 256
 	ti.Width = 48
-	return directoryPickerModel{
-		input: ti,
-		dirs:  getInitialDirs(),
-		filteredDirs: []string{},
-		selectedIdx: 0,
-		state: 0,
+	ti.Focus()
+	nd := textinput.New()
+	nd.Placeholder = "New folder name"
+	nd.CharLimit = 128
+	nd.Width = 40
+	m := directoryPickerModel{
+		input:     ti,
+		newDir:    nd,
+		height:    dirPickerHeight,
+		bookmarks: append([]string(nil), opts.Bookmarks...),
+		recent:    opts.Recent,
+	}
+	start := opts.Start
+	if start == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "/"
+		}
+		start = home
 	}
+	m.setDir(start)
+	return m
 }
 
-func getInitialDirs() []string {
-	home, err := os.UserHomeDir()
+// listDirs returns the names of the subdirectories of base, following symlinks, sorted
+// case-insensitively. Hidden directories are left out unless hidden is set.
+func listDirs(base string, hidden bool) ([]string, error) {
+	entries, err := os.ReadDir(base)
 	if err != nil {
-		home = "/"
+		return nil, err
+	}
+	var dirs []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !hidden && strings.HasPrefix(name, ".") {
+			continue
+		}
+		if entry.Type()&os.ModeSymlink != 0 {
+			if info, err := os.Stat(filepath.Join(base, name)); err != nil || !info.IsDir() {
+				continue
+			}
+		} else if !entry.IsDir() {
+			continue
+		}
+		dirs = append(dirs, name)
 	}
-	return listDirs(home)
+	sort.Slice(dirs, func(i, j int) bool { return strings.ToLower(dirs[i]) < strings.ToLower(dirs[j]) })
+	return dirs, nil
 }
 
-func listDirs(base string) []string {
-	entries, err := os.ReadDir(base)
+// setDir browses dir, clearing the filter.
+func (m *directoryPickerModel) setDir(dir string) {
+	m.dir = filepath.Clean(dir)
+	m.input.SetValue("")
+	m.reload()
+}
+
+// reload re-reads the browsed directory and rebuilds the rows.
+func (m *directoryPickerModel) reload() {
+	names, err := listDirs(m.dir, m.showHidden)
+	m.inputError = ""
 	if err != nil {
-		return []string{base}
+		m.inputError = "✗ " + err.Error()
 	}
-	dirs := []string{}
-	for _, entry := range entries {
-		if entry.IsDir() {
-			dirs = append(dirs, filepath.Join(base, entry.Name()))
+	m.names = names
+	m.refresh()
+}
+
+// refresh rebuilds the rows for the current view and filter.
+func (m *directoryPickerModel) refresh() {
+	query := m.input.Value()
+	m.rows = nil
+	switch m.view {
+	case viewBrowse:
+		if query == "" {
+			m.rows = append(m.rows, pickerRow{label: ". (this folder)", path: m.dir})
+		}
+		for _, c := range fuzzyFilter(query, m.names) {
+			name := m.names[c.Index]
+			m.rows = append(m.rows, pickerRow{label: name + "/", path: filepath.Join(m.dir, name), positions: c.Positions})
+		}
+	case viewPlaces:
+		m.addPlaces("Bookmarks", m.bookmarks, query)
+		m.addPlaces("Recent", m.recent, query)
+	}
+	m.cursor, m.offset = 0, 0
+	m.move(0)
+}
+
+func (m *directoryPickerModel) addPlaces(heading string, places []string, query string) {
+	labels := make([]string, len(places))
+	for i, p := range places {
+		labels[i] = shortenHome(p)
+	}
+	matches := fuzzyFilter(query, labels)
+	if len(matches) == 0 {
+		return
+	}
+	m.rows = append(m.rows, pickerRow{heading: heading})
+	for _, c := range matches {
+		m.rows = append(m.rows, pickerRow{label: labels[c.Index], path: places[c.Index], positions: c.Positions})
+	}
+}
+
+// move shifts the cursor by delta directories, skipping headings, and keeps it in view.
+func (m *directoryPickerModel) move(delta int) {
+	step := 1
+	if delta < 0 {
+		step = -1
+	}
+	next := m.cursor + delta
+	if next < 0 {
+		next = 0
+	}
+	if next >= len(m.rows) {
+		next = len(m.rows) - 1
+	}
+	for next >= 0 && next < len(m.rows) && m.rows[next].heading != "" {
+		next += step
+	}
+	if next < 0 || next >= len(m.rows) {
+		// Ran into a heading at the edge; search the other way
+		next = m.cursor
+		for next >= 0 && next < len(m.rows) && m.rows[next].heading != "" {
+			next -= step
+		}
+		if next < 0 || next >= len(m.rows) {
+			next = 0
+		}
+	}
+	m.cursor = next
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+		if m.offset > 0 && m.rows[m.offset-1].heading != "" {
+			m.offset--
+		}
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+// selected returns the highlighted directory, if any.
+func (m directoryPickerModel) selected() (pickerRow, bool) {
+	if m.cursor < len(m.rows) && m.rows[m.cursor].heading == "" {
+		return m.rows[m.cursor], true
+	}
+	return pickerRow{}, false
+}
+
+// jumpToTypedPath follows a typed path: its directory part becomes the browsed directory
+// and the rest stays as the filter, so "~/Doc" browses the home directory for "Doc".
+func (m *directoryPickerModel) jumpToTypedPath() bool {
+	value := m.input.Value()
+	if !strings.ContainsRune(value, '/') && !strings.HasPrefix(value, "~") {
+		return false
+	}
+	if value == "~" {
+		value = "~/"
+	}
+	expanded, err := expandPath(value)
+	if err != nil {
+		return false
+	}
+	if !filepath.IsAbs(expanded) {
+		expanded = filepath.Join(m.dir, expanded)
+	}
+	dir, rest := expanded, ""
+	if !strings.HasSuffix(value, "/") {
+		dir, rest = filepath.Dir(expanded), filepath.Base(expanded)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		return false
+	}
+	m.view = viewBrowse
+	m.input.Placeholder = browsePlaceholder
+	m.setDir(dir)
+	m.input.SetValue(rest)
+	m.input.CursorEnd()
+	m.refresh()
+	return true
+}
+
+// ascend browses the parent directory, keeping the directory just left highlighted.
+func (m *directoryPickerModel) ascend() {
+	parent := filepath.Dir(m.dir)
+	if parent == m.dir {
+		return
+	}
+	left := filepath.Base(m.dir)
+	m.setDir(parent)
+	for i, row := range m.rows {
+		if row.path == filepath.Join(parent, left) {
+			m.cursor = i
+			m.move(0)
+			break
 		}
 	}
-	sort.Strings(dirs)
-	return dirs
+}
+
+// toggleBookmark adds or removes dir from the bookmarks.
+func (m *directoryPickerModel) toggleBookmark(dir string) {
+	for i, b := range m.bookmarks {
+		if b == dir {
+			m.bookmarks = append(m.bookmarks[:i], m.bookmarks[i+1:]...)
+			m.result.BookmarksChanged = true
+			return
+		}
+	}
+	m.bookmarks = append(m.bookmarks, dir)
+	m.result.BookmarksChanged = true
+}
+
+func (m directoryPickerModel) bookmarked(dir string) bool {
+	for _, b := range m.bookmarks {
+		if b == dir {
+			return true
+		}
+	}
+	return false
 }
 
 func (m directoryPickerModel) Init() tea.Cmd {
-	return nil
+	return textinput.Blink
+}
+
+// finish ends the picker, handing back the bookmarks along with the choice.
+func (m directoryPickerModel) finish() (tea.Model, tea.Cmd) {
+	m.result.Bookmarks = m.bookmarks
+	m.state = 1
+	return m, tea.Quit
 }
 
 func (m directoryPickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	switch m.state {
-	case 0:
-		switch msg := msg.(type) {
-		case tea.KeyMsg:
-			switch msg.String() {
-			case "q", "esc":
-				m.result.Cancelled = true
-				m.state = 1
-				return m, tea.Quit
-			case "enter":
-				if m.selectedIdx >= 0 && m.selectedIdx < len(m.filteredDirs) {
-					m.result.Path = m.filteredDirs[m.selectedIdx]
-					m.state = 1
-					return m, tea.Quit
-				}
-				val := m.input.Value()
-				if val == "" {
-					m.inputError = "✗ Path cannot be empty."
-					return m, nil
-				}
-				expanded, err := expandPath(val)
-				if err != nil {
-					m.inputError = "✗ Invalid path."
-					return m, nil
-				}
-				m.result.Path = expanded
-				m.state = 1
-				return m, tea.Quit
-			case "up":
-				if m.selectedIdx > 0 {
-					m.selectedIdx--
+	if m.state != 0 {
+		return m, nil
+	}
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		// Leave room for the surrounding header, input box and help
+		m.height = msg.Height - 12
+		if m.height < 3 {
+			m.height = 3
+		}
+		m.input.Width = msg.Width - 8
+		if m.input.Width < 20 {
+			m.input.Width = 20
+		}
+		m.newDir.Width = m.input.Width
+		m.move(0)
+		return m, nil
+	}
+	if m.creating {
+		return m.updateCreate(msg)
+	}
+	if msg, ok := msg.(tea.MouseMsg); ok {
+		return m.updateMouse(msg)
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case matchKey(msg, true, keymap.Back, keymap.Quit):
+			m.result.Cancelled = true
+			return m.finish()
+		case matchKey(msg, true, keymap.Select):
+			row, ok := m.selected()
+			if !ok {
+				m.inputError = "✗ No folder matches."
+				if hint := helpText(true, keyHelp("create it", keymap.NewFolder)); hint != "" {
+					m.inputError += " " + hint
 				}
 				return m, nil
-			case "down":
-				if m.selectedIdx < len(m.filteredDirs)-1 {
-					m.selectedIdx++
-				}
+			}
+			m.result.Path = row.path
+			return m.finish()
+		case matchKey(msg, true, keymap.Up):
+			m.move(-1)
+			return m, nil
+		case matchKey(msg, true, keymap.Down):
+			m.move(1)
+			return m, nil
+		case matchKey(msg, true, keymap.NewFolder):
+			if m.view != viewBrowse {
 				return m, nil
 			}
+			m.creating = true
+			m.inputError = ""
+			m.newDir.SetValue(m.input.Value())
+			m.newDir.CursorEnd()
+			m.newDir.Focus()
+			m.input.Blur()
+			return m, textinput.Blink
+		case matchKey(msg, true, keymap.PageUp):
+			m.move(-m.height)
+			return m, nil
+		case matchKey(msg, true, keymap.PageDown):
+			m.move(m.height)
+			return m, nil
+		case matchKey(msg, true, keymap.Open):
+			if row, ok := m.selected(); ok && (row.path != m.dir || m.view == viewPlaces) {
+				m.view = viewBrowse
+				m.input.Placeholder = browsePlaceholder
+				m.setDir(row.path)
+			}
+			return m, nil
+		case matchKey(msg, true, keymap.Parent):
+			// Keys that delete text only go up once the filter is empty
+			if deletesText(msg) && m.input.Value() != "" {
+				break
+			}
+			if m.view == viewBrowse {
+				m.ascend()
+			}
+			return m, nil
+		case matchKey(msg, true, keymap.ToggleHidden):
+			m.showHidden = !m.showHidden
+			m.reload()
+			return m, nil
+		case matchKey(msg, true, keymap.Bookmark):
+			if row, ok := m.selected(); ok {
+				m.toggleBookmark(row.path)
+				if m.view == viewPlaces {
+					m.refresh()
+				}
+			}
+			return m, nil
+		case matchKey(msg, true, keymap.Places):
+			if m.view == viewBrowse {
+				m.view = viewPlaces
+				m.input.Placeholder = "Type to filter places"
+			} else {
+				m.view = viewBrowse
+				m.input.Placeholder = browsePlaceholder
+			}
+			m.input.SetValue("")
+			m.inputError = ""
+			m.refresh()
+			return m, nil
 		}
-		var cmd tea.Cmd
-		m.input, cmd = m.input.Update(msg)
-		m.filteredDirs = m.filterDirs(m.input.Value())
-		if m.selectedIdx >= len(m.filteredDirs) {
-			m.selectedIdx = len(m.filteredDirs) - 1
+	}
+	before := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != before {
+		m.inputError = ""
+		if m.view == viewPlaces || !m.jumpToTypedPath() {
+			m.refresh()
 		}
-		if m.selectedIdx < 0 {
-			m.selectedIdx = 0
+	}
+	return m, cmd
+}
+
+// updateMouse moves through the folders with the wheel. Clicking a folder highlights it
+// and clicking it again picks it.
+func (m directoryPickerModel) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if delta := wheelDelta(msg); delta != 0 {
+		m.move(delta)
+		return m, nil
+	}
+	top := dirPickerRowsTop
+	if m.offset > 0 {
+		// Below the "more" line
+		top++
+	}
+	i := clickedRow(msg, top, m.offset, m.height, len(m.rows))
+	if i < 0 || m.rows[i].heading != "" {
+		return m, nil
+	}
+	if i != m.cursor {
+		m.cursor = i
+		return m, nil
+	}
+	m.result.Path = m.rows[i].path
+	return m.finish()
+}
+
+// updateCreate handles keys while a new folder is being named.
+func (m directoryPickerModel) updateCreate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case matchKey(msg, true, keymap.Back, keymap.Quit):
+			m.creating = false
+			m.newDir.Blur()
+			m.input.Focus()
+			return m, nil
+		case matchKey(msg, true, keymap.Select):
+			name := strings.TrimSpace(m.newDir.Value())
+			if name == "" || name == "." || name == ".." || strings.ContainsRune(name, os.PathSeparator) {
+				m.inputError = "✗ Enter a folder name without slashes."
+				return m, nil
+			}
+			path := filepath.Join(m.dir, name)
+			if err := os.Mkdir(path, 0o755); err != nil {
+				m.inputError = "✗ " + err.Error()
+				return m, nil
+			}
+			m.creating = false
+			m.newDir.Blur()
+			m.input.Focus()
+			if strings.HasPrefix(name, ".") {
+				m.showHidden = true
+			}
+			m.input.SetValue("")
+			m.reload()
+			for i, row := range m.rows {
+				if row.path == path {
+					m.cursor = i
+					m.move(0)
+				}
+			}
+			return m, nil
 		}
-		return m, cmd
 	}
-	return m, nil
+	var cmd tea.Cmd
+	m.newDir, cmd = m.newDir.Update(msg)
+	return m, cmd
 }
 
 func (m directoryPickerModel) View() string {
 	if m.state == 1 {
 		return ""
 	}
-	prompt := dirPickerHeaderStyle.Render("Select or enter a directory:")
-	inputBox := dirPickerBorderStyle.Render(m.input.View())
-	dirList := ""
-	for i, dir := range m.filteredDirs {
-		name := dir
-		if i == m.selectedIdx {
-			dirList += dirPickerSelectedStyle.Render(name) + "\n"
+	title := "📁 " + shortenHome(m.dir)
+	if m.view == viewPlaces {
+		title = "★ Bookmarks and recent places"
+	}
+	status := ""
+	if m.showHidden {
+		status = dirPickerDimStyle.Render("  (showing hidden)")
+	}
+	prompt := dirPickerHeaderStyle.Render(title) + status
+
+	var box string
+	if m.creating {
+		box = dirPickerBorderStyle.Render("New folder in " + shortenHome(m.dir) + "\n" + m.newDir.View())
+	} else {
+		box = dirPickerBorderStyle.Render(m.input.View())
+	}
+
+	var lines []string
+	if m.offset > 0 {
+		lines = append(lines, dirPickerDimStyle.Render(fmt.Sprintf("  ↑ %d more", m.offset)))
+	}
+	end := m.offset + m.height
+	if end > len(m.rows) {
+		end = len(m.rows)
+	}
+	for i := m.offset; i < end; i++ {
+		row := m.rows[i]
+		if row.heading != "" {
+			lines = append(lines, dirPickerSectionStyle.Render(" "+row.heading))
+			continue
+		}
+		mark := "  "
+		if m.bookmarked(row.path) {
+			mark = dirPickerMarkStyle.Render("★ ")
+		}
+		if i == m.cursor {
+			lines = append(lines, mark+dirPickerSelectedStyle.Render(" ")+highlightMatches(row.label, row.positions, dirPickerSelectedStyle)+dirPickerSelectedStyle.Render(" "))
 		} else {
-			dirList += dirPickerItemStyle.Render(name) + "\n"
+			lines = append(lines, mark+" "+highlightMatches(row.label, row.positions, dirPickerItemStyle))
+		}
+	}
+	if rest := len(m.rows) - end; rest > 0 {
+		lines = append(lines, dirPickerDimStyle.Render(fmt.Sprintf("  ↓ %d more", rest)))
+	}
+	if len(m.rows) == 0 {
+		empty := "  No folders here."
+		switch {
+		case m.view == viewPlaces:
+			empty = "  No bookmarks or recent places yet."
+			if hint := helpText(true, keyHelp("bookmark a folder", keymap.Bookmark)); hint != "" {
+				empty += " " + hint
+			}
+		case m.input.Value() != "":
+			empty = "  No folder matches."
 		}
+		lines = append(lines, dirPickerDimStyle.Render(empty))
 	}
+
 	errMsg := ""
 	if m.inputError != "" {
-		errMsg = dirPickerErrorStyle.Render(m.inputError)
+		errMsg = dirPickerErrorStyle.Render(m.inputError) + "\n"
+	}
+	var keys string
+	switch {
+	case m.creating:
+		keys = helpText(true, keyHelp("Create", keymap.Select), keyHelp("Back", keymap.Back))
+	case m.view == viewPlaces:
+		keys = helpText(true, keyHelp("Select", keymap.Select), keyHelp("Move", keymap.Up, keymap.Down), keyHelp("Browse", keymap.Open), keyHelp("Cancel", keymap.Back)) + "\n" +
+			helpText(true, keyHelp("Remove bookmark", keymap.Bookmark), keyHelp("Back to folders", keymap.Places))
+	default:
+		keys = helpText(true, keyHelp("Select", keymap.Select), keyHelp("Move", keymap.Up, keymap.Down), keyHelp("Open", keymap.Open), keyHelp("Up", keymap.Parent), keyHelp("Cancel", keymap.Back)) + "\n" +
+			helpText(true, keyHelp("Hidden", keymap.ToggleHidden), keyHelp("Bookmark", keymap.Bookmark), keyHelp("Places", keymap.Places), keyHelp("New folder", keymap.NewFolder))
 	}
-	help := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Faint(true).Padding(0, 1).Render("[Enter] Select   [↑/↓] Navigate   [Esc] Cancel")
-	return prompt + "\n" + inputBox + "\n" + dirList + errMsg + "\n" + help
+	help := helpBarStyle.Render(keys)
+	return prompt + "\n" + box + "\n" + strings.Join(lines, "\n") + "\n" + errMsg + help
 }
 
-func (m directoryPickerModel) filterDirs(prefix string) []string {
-	if prefix == "" {
-		return m.dirs
+// shortenHome abbreviates the home directory in path to ~.
+func shortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" || home == "/" {
+		return path
 	}
-	// Expand ~
-	base, _ := expandPath(prefix)
-	parent := filepath.Dir(base)
-	if parent == "." || parent == "/" {
-		parent, _ = os.UserHomeDir()
+	if path == home {
+		return "~"
 	}
-	candidates := listDirs(parent)
-	filtered := []string{}
-	for _, d := range candidates {
-		if strings.HasPrefix(d, base) {
-			filtered = append(filtered, d)
-		}
+	if rest, ok := strings.CutPrefix(path, home+string(os.PathSeparator)); ok {
+		return "~" + string(os.PathSeparator) + rest
 	}
-	if len(filtered) == 0 && base != "" {
-		filtered = append(filtered, base)
-	}
-	return filtered
+	return path
 }
 
 // expandPath expands ~ to home directory
@@ -189,17 +602,13 @@ func expandPath(path string) (string, error) {
 	return path, nil
 }
 
-// LaunchDirectoryPicker launches the directory picker and returns the selected path.
-func LaunchDirectoryPicker() (string, error) {
-	m := NewDirectoryPickerModel()
-	p := tea.NewProgram(m)
+// LaunchDirectoryPicker launches the directory picker and returns its result.
+func LaunchDirectoryPicker(opts DirectoryPickerOptions) (DirectoryPickerResult, error) {
+	m := NewDirectoryPickerModel(opts)
+	p := tea.NewProgram(m, programOptions()...)
 	finalModel, err := p.Run()
 	if err != nil {
-		return "", err
-	}
-	result := finalModel.(directoryPickerModel).result
-	if result.Cancelled {
-		return "", os.ErrNotExist
+		return DirectoryPickerResult{}, err
 	}
-	return result.Path, result.Err
-} 
\ No newline at end of file
+	return finalModel.(directoryPickerModel).result, nil
+}
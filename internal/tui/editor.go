@@ -0,0 +1,420 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// editorCompletions is how many completions the editor shows at once.
+const editorCompletions = 5
+
+// ErrChangedOnDisk is returned by an editor's save function when the note changed on
+// disk since the editor loaded or last saved it.
+var ErrChangedOnDisk = errors.New("the note changed on disk")
+
+// EditorOptions describe the note the editor works on.
+type EditorOptions struct {
+	Title   string
+	Content string
+	Links   []string // Link targets completed after [[
+	Tags    []string // Tags completed after #, without the #
+	// Save writes content to the note. Unless force is set, it returns ErrChangedOnDisk
+	// when the note was changed by something else in the meantime.
+	Save func(content string, force bool) error
+}
+
+// EditorResult tells what became of the note in the editor.
+type EditorResult struct {
+	Saved   bool   // The note was saved at least once
+	Content string // The note as last saved
+}
+
+// LaunchEditor edits a note in the terminal until the user closes the editor.
+func LaunchEditor(opts EditorOptions) (EditorResult, error) {
+	p := tea.NewProgram(newEditorModel(opts), programOptions()...)
+	finalModel, err := p.Run()
+	if err != nil {
+		return EditorResult{}, err
+	}
+	return finalModel.(editorModel).result, nil
+}
+
+type completionKind int
+
+const (
+	completeNone completionKind = iota
+	completeLink
+	completeTag
+)
+
+// editorQuestion is a yes/no question the editor is waiting on.
+type editorQuestion int
+
+const (
+	askNothing editorQuestion = iota
+	askDiscard
+	askOverwrite
+)
+
+type editorModel struct {
+	title    string
+	textarea textarea.Model
+	links    []string
+	tags     []string
+	save     func(content string, force bool) error
+	saved    string // Content as last loaded or saved, to tell whether there are changes
+	question editorQuestion
+	status   string
+	failed   bool // status is an error
+	width    int
+	height   int
+
+	// Completion of the link or tag being typed
+	kind      completionKind
+	query     string
+	context   string // Where the completed text starts, as "row:column"
+	dismissed string // Context the user closed the completions for
+	matches   []fuzzyCandidate
+	cursor    int
+
+	result EditorResult
+}
+
+func newEditorModel(opts EditorOptions) editorModel {
+	ta := textarea.New()
+	ta.ShowLineNumbers = true
+	ta.CharLimit = 0
+	ta.MaxHeight = 0
+	ta.Prompt = ""
+	ta.FocusedStyle.CursorLine = lipgloss.NewStyle()
+	ta.FocusedStyle.LineNumber = editorLineNumberStyle
+	ta.FocusedStyle.CursorLineNumber = editorCursorLineStyle
+	ta.BlurredStyle = ta.FocusedStyle
+	ta.SetValue(opts.Content)
+	// SetValue leaves the cursor at the end of the note; start at the top instead
+	for ta.Line() > 0 {
+		ta.CursorUp()
+	}
+	ta.CursorStart()
+	ta.Focus()
+	m := editorModel{
+		title:    opts.Title,
+		textarea: ta,
+		links:    opts.Links,
+		tags:     opts.Tags,
+		save:     opts.Save,
+		saved:    opts.Content,
+		result:   EditorResult{Content: opts.Content},
+	}
+	m.layout()
+	return m
+}
+
+func (m editorModel) Init() tea.Cmd {
+	return textarea.Blink
+}
+
+// dirty reports whether there are unsaved changes.
+func (m editorModel) dirty() bool {
+	return m.textarea.Value() != m.saved
+}
+
+// layout sizes the text area to the window, leaving room for the title and the
+// completions or help bar.
+func (m *editorModel) layout() {
+	width, height := m.width, m.height
+	if width == 0 {
+		width, height = 80, 24
+	}
+	m.textarea.SetWidth(width)
+	rows := height - 2 - lipgloss.Height(m.footer())
+	if rows < 3 {
+		rows = 3
+	}
+	m.textarea.SetHeight(rows)
+}
+
+func (m editorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.layout()
+		return m, nil
+	case tea.MouseMsg:
+		if m.question == askNothing {
+			m.updateMouse(msg)
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if m.question != askNothing {
+			return m.answer(msg)
+		}
+		if m.kind != completeNone {
+			switch {
+			case matchKey(msg, true, keymap.Up):
+				m.moveCompletion(-1)
+				return m, nil
+			case matchKey(msg, true, keymap.Down):
+				m.moveCompletion(1)
+				return m, nil
+			case matchKey(msg, true, keymap.Complete, keymap.Select):
+				m.complete()
+				m.layout()
+				return m, nil
+			case matchKey(msg, true, keymap.Back):
+				m.dismissed = m.context
+				m.kind = completeNone
+				m.layout()
+				return m, nil
+			}
+		}
+		switch {
+		case matchKey(msg, true, keymap.Save):
+			m.write(false)
+			return m, nil
+		case matchKey(msg, true, keymap.Back, keymap.Quit):
+			if m.dirty() {
+				m.question = askDiscard
+				return m, nil
+			}
+			return m, tea.Quit
+		}
+		m.status = ""
+	}
+	var cmd tea.Cmd
+	m.textarea, cmd = m.textarea.Update(msg)
+	m.refreshCompletion()
+	m.layout()
+	return m, cmd
+}
+
+// updateMouse moves through the note, or the completions while there are any, with the
+// wheel. Clicking a completion highlights it and clicking it again inserts it.
+func (m *editorModel) updateMouse(msg tea.MouseMsg) {
+	delta := wheelDelta(msg)
+	switch {
+	case delta != 0 && m.kind != completeNone:
+		m.moveCompletion(delta)
+		return
+	case delta != 0:
+		for i := 0; i < 3; i++ {
+			if delta < 0 {
+				m.textarea.CursorUp()
+			} else {
+				m.textarea.CursorDown()
+			}
+		}
+		m.refreshCompletion()
+	case m.kind != completeNone:
+		start, end := m.completionWindow()
+		// The completions are drawn right below the title and the text area
+		i := clickedRow(msg, 1+m.textarea.Height(), start, end-start, len(m.matches))
+		if i < 0 {
+			return
+		}
+		if i != m.cursor {
+			m.cursor = i
+			return
+		}
+		m.complete()
+	}
+	m.layout()
+}
+
+// answer handles the answer to the question being asked.
+func (m editorModel) answer(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case matchKey(msg, false, keymap.Confirm):
+		question := m.question
+		m.question = askNothing
+		if question == askDiscard {
+			return m, tea.Quit
+		}
+		m.write(true)
+	case matchKey(msg, false, keymap.Deny, keymap.Back):
+		if m.question == askOverwrite {
+			m.status, m.failed = "Not saved.", true
+		}
+		m.question = askNothing
+	}
+	return m, nil
+}
+
+// write saves the note, asking before overwriting changes made on disk unless force is set.
+func (m *editorModel) write(force bool) {
+	content := m.textarea.Value()
+	err := m.save(content, force)
+	switch {
+	case errors.Is(err, ErrChangedOnDisk):
+		m.question = askOverwrite
+	case err != nil:
+		m.status, m.failed = err.Error(), true
+	default:
+		m.saved = content
+		m.result = EditorResult{Saved: true, Content: content}
+		m.status, m.failed = "Saved.", false
+	}
+}
+
+// cursorText returns the text of the cursor's line before the cursor.
+func (m editorModel) cursorText() string {
+	lines := strings.Split(m.textarea.Value(), "\n")
+	row := m.textarea.Line()
+	if row >= len(lines) {
+		return ""
+	}
+	info := m.textarea.LineInfo()
+	line := []rune(lines[row])
+	col := info.StartColumn + info.ColumnOffset
+	if col > len(line) {
+		col = len(line)
+	}
+	return string(line[:col])
+}
+
+// refreshCompletion looks for a link or tag being typed before the cursor and finds the
+// completions for it.
+func (m *editorModel) refreshCompletion() {
+	kind, query, start := completionAt(m.cursorText())
+	context := fmt.Sprintf("%d:%d", m.textarea.Line(), start)
+	if kind == completeNone || context == m.dismissed {
+		if context != m.dismissed {
+			m.dismissed = ""
+		}
+		m.kind = completeNone
+		return
+	}
+	m.dismissed = ""
+	items := m.links
+	if kind == completeTag {
+		items = m.tags
+	}
+	if kind != m.kind || query != m.query || context != m.context {
+		m.cursor = 0
+	}
+	m.kind, m.query, m.context = kind, query, context
+	m.matches = fuzzyFilter(query, items)
+	if len(m.matches) == 0 {
+		m.kind = completeNone
+	}
+	if m.cursor >= len(m.matches) {
+		m.cursor = 0
+	}
+}
+
+// completionAt finds the link or tag being typed at the end of before: the text after an
+// unclosed [[, or a #tag starting a word. start is the rune column the query begins at.
+func completionAt(before string) (kind completionKind, query string, start int) {
+	if i := strings.LastIndex(before, "[["); i >= 0 {
+		if q := before[i+2:]; !strings.ContainsAny(q, "[]|#") {
+			return completeLink, q, len([]rune(before[:i+2]))
+		}
+	}
+	word := before[strings.LastIndexFunc(before, unicode.IsSpace)+1:]
+	if !strings.HasPrefix(word, "#") {
+		return completeNone, "", 0
+	}
+	q := word[1:]
+	for _, r := range q {
+		if !unicode.IsLetter(r) && !unicode.IsNumber(r) && !strings.ContainsRune("_/-", r) {
+			return completeNone, "", 0
+		}
+	}
+	return completeTag, q, len([]rune(before)) - len([]rune(q))
+}
+
+func (m *editorModel) moveCompletion(delta int) {
+	m.cursor = (m.cursor + delta + len(m.matches)) % len(m.matches)
+}
+
+// complete replaces the query with the highlighted completion.
+func (m *editorModel) complete() {
+	items := m.links
+	if m.kind == completeTag {
+		items = m.tags
+	}
+	choice := items[m.matches[m.cursor].Index]
+	for range []rune(m.query) {
+		m.textarea, _ = m.textarea.Update(tea.KeyMsg{Type: tea.KeyBackspace})
+	}
+	if m.kind == completeLink {
+		lines := strings.Split(m.textarea.Value(), "\n")
+		after := strings.TrimPrefix(lines[m.textarea.Line()], m.cursorText())
+		if !strings.HasPrefix(after, "]]") {
+			choice += "]]"
+		}
+	}
+	m.textarea.InsertString(choice)
+	m.kind = completeNone
+	// A completed tag still reads as a tag being typed; leave it be
+	m.dismissed = m.context
+}
+
+// footer renders what goes below the text area: a question, the completions or the
+// help bar.
+func (m editorModel) footer() string {
+	switch {
+	case m.question == askDiscard:
+		return errorStyle.Render("Discard unsaved changes? " + confirmPrompt())
+	case m.question == askOverwrite:
+		return errorStyle.Render("The note changed on disk. Overwrite it? " + confirmPrompt())
+	case m.kind != completeNone:
+		return m.completionsView()
+	}
+	help := helpText(true, keyHelp("Save", keymap.Save), keyHelp("Close", keymap.Back, keymap.Quit))
+	return helpBarStyle.Render(help + "   [[: Link   #: Tag")
+}
+
+// completionWindow returns the range of matches shown, which keeps the cursor in view.
+func (m editorModel) completionWindow() (start, end int) {
+	if m.cursor >= editorCompletions {
+		start = m.cursor - editorCompletions + 1
+	}
+	end = start + editorCompletions
+	if end > len(m.matches) {
+		end = len(m.matches)
+	}
+	return start, end
+}
+
+func (m editorModel) completionsView() string {
+	items := m.links
+	prefix := "[["
+	if m.kind == completeTag {
+		items, prefix = m.tags, "#"
+	}
+	start, end := m.completionWindow()
+	var lines []string
+	for i := start; i < end; i++ {
+		match := m.matches[i]
+		text := items[match.Index]
+		if i == m.cursor {
+			lines = append(lines, selectedStyle.Render(" "+prefix)+highlightMatches(text, match.Positions, selectedStyle)+selectedStyle.Render(" "))
+		} else {
+			lines = append(lines, " "+prefix+highlightMatches(text, match.Positions, dirPickerItemStyle))
+		}
+	}
+	help := helpText(true, keyHelp("Move", keymap.Up, keymap.Down), keyHelp("Insert", keymap.Complete, keymap.Select), keyHelp("Close", keymap.Back))
+	return strings.Join(lines, "\n") + "\n" + helpBarStyle.Render(fmt.Sprintf("%d matches   %s", len(m.matches), help))
+}
+
+func (m editorModel) View() string {
+	header := headerStyle.Render("✎ " + m.title)
+	if m.dirty() {
+		header += editorDirtyStyle.Render("● modified")
+	}
+	if m.status != "" {
+		style := successStyle
+		if m.failed {
+			style = errorStyle
+		}
+		header += " " + style.Render(m.status)
+	}
+	return header + "\n" + m.textarea.View() + "\n" + m.footer()
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// fuzzyMatch reports whether the runes of pattern appear in s in order, ignoring case.
+// The score rewards matches at the start of s or of a word and runs of consecutive
+// matches, and penalizes gaps, so "nts" ranks "notes" above "mountains". It also returns
+// the rune positions in s that matched, for highlighting.
+func fuzzyMatch(pattern, s string) (score int, positions []int, ok bool) {
+	p := []rune(strings.ToLower(pattern))
+	if len(p) == 0 {
+		return 0, nil, true
+	}
+	runes := []rune(s)
+	lower := []rune(strings.ToLower(s))
+	pi, last := 0, -1
+	for i := 0; i < len(lower) && pi < len(p); i++ {
+		if lower[i] != p[pi] {
+			continue
+		}
+		switch {
+		case i == 0:
+			score += 8
+		case !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]):
+			score += 6
+		case unicode.IsUpper(runes[i]) && unicode.IsLower(runes[i-1]):
+			score += 4
+		}
+		if last >= 0 && i == last+1 {
+			score += 5
+		} else if last >= 0 {
+			score -= i - last - 1
+		}
+		positions = append(positions, i)
+		last = i
+		pi++
+	}
+	if pi < len(p) {
+		return 0, nil, false
+	}
+	// Prefer shorter candidates when everything else is equal
+	score -= len(lower) / 8
+	return score, positions, true
+}
+
+// fuzzyCandidate is a string that matched a fuzzy pattern.
+type fuzzyCandidate struct {
+	Index     int // Position in the list passed to fuzzyFilter
+	Score     int
+	Positions []int
+}
+
+// fuzzyFilter returns the items matching pattern, best matches first; ties keep their
+// original order. An empty pattern matches everything in order.
+func fuzzyFilter(pattern string, items []string) []fuzzyCandidate {
+	var matches []fuzzyCandidate
+	for i, item := range items {
+		if score, positions, ok := fuzzyMatch(pattern, item); ok {
+			matches = append(matches, fuzzyCandidate{Index: i, Score: score, Positions: positions})
+		}
+	}
+	if pattern != "" {
+		sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	}
+	return matches
+}
+
+// highlightMatches renders s with the runes at positions highlighted and the rest in base.
+func highlightMatches(s string, positions []int, base lipgloss.Style) string {
+	if len(positions) == 0 {
+		return base.Render(s)
+	}
+	matched := map[int]bool{}
+	for _, p := range positions {
+		matched[p] = true
+	}
+	var b strings.Builder
+	var run []rune
+	runMatched := false
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if runMatched {
+			b.WriteString(fuzzyMatchStyle.Inherit(base).Render(string(run)))
+		} else {
+			b.WriteString(base.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(s) {
+		if matched[i] != runMatched {
+			flush()
+			runMatched = matched[i]
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
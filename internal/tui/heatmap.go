@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"cobra-cli/internal/vault"
+)
+
+// RenderHeatmap renders the last weeks of activity as a calendar, one column per week
+// and one row per weekday, shaded by words written, followed by streaks and totals.
+func RenderHeatmap(a vault.Activity, weeks int, now time.Time) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	// Start on the Sunday weeks-1 weeks before the current week
+	start := today.AddDate(0, 0, -int(today.Weekday())-7*(weeks-1))
+
+	max := 0
+	for _, d := range a.Days {
+		if !d.Date.Before(start) && d.Words > max {
+			max = d.Words
+		}
+	}
+
+	// Label each month above the first week that starts in it, if there is room
+	months := []rune(strings.Repeat(" ", 4+2*weeks+3))
+	free := 0
+	for w := 0; w < weeks; w++ {
+		weekStart := start.AddDate(0, 0, 7*w)
+		if w > 0 && weekStart.Month() == weekStart.AddDate(0, 0, -7).Month() {
+			continue
+		}
+		pos := 4 + 2*w
+		if pos < free {
+			continue
+		}
+		copy(months[pos:], []rune(weekStart.Format("Jan")))
+		free = pos + 4
+	}
+
+	rows := []string{strings.TrimRight(string(months), " ")}
+	for wd := 0; wd < 7; wd++ {
+		var row strings.Builder
+		label := "   "
+		if wd%2 == 1 {
+			label = time.Weekday(wd).String()[:3]
+		}
+		row.WriteString(label + " ")
+		for w := 0; w < weeks; w++ {
+			day := start.AddDate(0, 0, 7*w+wd)
+			if day.After(today) {
+				break
+			}
+			row.WriteString(heatmapCell(heatmapLevel(a.Day(day), max)) + " ")
+		}
+		rows = append(rows, row.String())
+	}
+
+	legend := "    Less "
+	for l := range heatmapLevels {
+		legend += heatmapCell(l) + " "
+	}
+	rows = append(rows, legend+"More")
+
+	todayWords := a.Day(today).Words
+	summary := []string{
+		statsLine("Current streak", pluralDays(a.CurrentStreak)),
+		statsLine("Longest streak", pluralDays(a.LongestStreak)),
+		statsLine("Active days", a.ActiveDays),
+		statsLine("Words written", a.TotalWords),
+		statsLine("Today", fmt.Sprintf("%d words", todayWords)),
+	}
+	if a.ActiveDays > 0 {
+		summary = append(summary, statsLine("Per active day", fmt.Sprintf("%d words", a.TotalWords/a.ActiveDays)))
+	}
+
+	var week []string
+	for i := 6; i >= 0; i-- {
+		d := a.Day(today.AddDate(0, 0, -i))
+		week = append(week, fmt.Sprintf("%s %s", statsLabelStyle.Render(d.Date.Format("Mon 01-02")), statsValueStyle.Render(fmt.Sprintf("%6d words", d.Words))))
+	}
+
+	calendar := borderStyle.Padding(0, 1).Render(headerStyle.Render("🔥 Writing activity — "+a.Vault) + "\n" + strings.Join(rows, "\n"))
+	panels := lipgloss.JoinHorizontal(lipgloss.Top, statsPanel("Streaks", summary), " ", statsPanel("Last 7 days", week))
+	return lipgloss.JoinVertical(lipgloss.Left, calendar, panels)
+}
+
+// heatmapLevel buckets a day into one of the heatmap levels by its words relative to the
+// busiest day. Days with edits but no words written still get the lowest active level.
+func heatmapLevel(d vault.DayActivity, max int) int {
+	words := d.Words
+	if d.Notes == 0 {
+		return 0
+	}
+	if words <= 0 || max <= 0 {
+		return 1
+	}
+	top := len(heatmapLevels) - 1
+	level := 1 + words*(top-1)/max
+	if level > top {
+		level = top
+	}
+	if words == max {
+		level = top
+	}
+	return level
+}
+
+// heatmapCell renders a calendar cell of the given level. Without colors the levels
+// are told apart by shading instead.
+func heatmapCell(level int) string {
+	if noColor {
+		return heatmapLevels[level].Render(string([]rune(heatmapShades)[level]))
+	}
+	return heatmapLevels[level].Render("■")
+}
+
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
@@ -0,0 +1,403 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// DefaultKeyMap is the preset used when none is configured.
+const DefaultKeyMap = "default"
+
+// KeyMap holds the key bindings of every TUI screen. Screens that take typed text, such
+// as the filter of the note viewer, ignore bindings to plain characters like j and k, so
+// those keys still type.
+type KeyMap struct {
+	Name string
+
+	Up       key.Binding
+	Down     key.Binding
+	PageUp   key.Binding
+	PageDown key.Binding
+	Select   key.Binding
+	Back     key.Binding // Leaves a step, or the TUI on its first screen
+	Quit     key.Binding
+	Delete   key.Binding
+	Confirm  key.Binding // Answers yes to a question
+	Deny     key.Binding // Answers no to a question
+	Palette  key.Binding // Opens the command palette
+
+	// Preview pane
+	PreviewUp   key.Binding
+	PreviewDown key.Binding
+
+	// Note editor
+	Save     key.Binding
+	Complete key.Binding // Accepts the highlighted completion
+
+	// Task list
+	Toggle key.Binding // Checks or unchecks the highlighted task
+
+	// Kanban board
+	Left      key.Binding
+	Right     key.Binding
+	MoveLeft  key.Binding // Moves the highlighted card a column left
+	MoveRight key.Binding
+
+	// Directory picker
+	Open         key.Binding // Browses into the selected folder
+	Parent       key.Binding // Browses the parent folder
+	ToggleHidden key.Binding
+	Bookmark     key.Binding
+	Places       key.Binding
+	NewFolder    key.Binding
+}
+
+// keyAction names a binding of KeyMap in config files.
+type keyAction struct {
+	name    string
+	desc    string
+	binding func(*KeyMap) *key.Binding
+}
+
+var keyActions = []keyAction{
+	{"up", "Move up", func(k *KeyMap) *key.Binding { return &k.Up }},
+	{"down", "Move down", func(k *KeyMap) *key.Binding { return &k.Down }},
+	{"page_up", "Move up a page", func(k *KeyMap) *key.Binding { return &k.PageUp }},
+	{"page_down", "Move down a page", func(k *KeyMap) *key.Binding { return &k.PageDown }},
+	{"select", "Select or confirm", func(k *KeyMap) *key.Binding { return &k.Select }},
+	{"back", "Go back a step, or cancel", func(k *KeyMap) *key.Binding { return &k.Back }},
+	{"quit", "Quit", func(k *KeyMap) *key.Binding { return &k.Quit }},
+	{"delete", "Delete the selected item", func(k *KeyMap) *key.Binding { return &k.Delete }},
+	{"confirm", "Answer yes", func(k *KeyMap) *key.Binding { return &k.Confirm }},
+	{"deny", "Answer no", func(k *KeyMap) *key.Binding { return &k.Deny }},
+	{"palette", "Open the command palette", func(k *KeyMap) *key.Binding { return &k.Palette }},
+	{"preview_up", "Scroll the preview up", func(k *KeyMap) *key.Binding { return &k.PreviewUp }},
+	{"preview_down", "Scroll the preview down", func(k *KeyMap) *key.Binding { return &k.PreviewDown }},
+	{"save", "Save the note being edited", func(k *KeyMap) *key.Binding { return &k.Save }},
+	{"complete", "Accept the highlighted completion", func(k *KeyMap) *key.Binding { return &k.Complete }},
+	{"toggle", "Check or uncheck the highlighted task", func(k *KeyMap) *key.Binding { return &k.Toggle }},
+	{"left", "Move to the column on the left", func(k *KeyMap) *key.Binding { return &k.Left }},
+	{"right", "Move to the column on the right", func(k *KeyMap) *key.Binding { return &k.Right }},
+	{"move_left", "Move the card to the column on the left", func(k *KeyMap) *key.Binding { return &k.MoveLeft }},
+	{"move_right", "Move the card to the column on the right", func(k *KeyMap) *key.Binding { return &k.MoveRight }},
+	{"open", "Browse into the selected folder", func(k *KeyMap) *key.Binding { return &k.Open }},
+	{"parent", "Browse the parent folder", func(k *KeyMap) *key.Binding { return &k.Parent }},
+	{"toggle_hidden", "Show or hide hidden folders", func(k *KeyMap) *key.Binding { return &k.ToggleHidden }},
+	{"bookmark", "Bookmark the selected folder", func(k *KeyMap) *key.Binding { return &k.Bookmark }},
+	{"places", "Switch to bookmarks and recent places", func(k *KeyMap) *key.Binding { return &k.Places }},
+	{"new_folder", "Create a folder", func(k *KeyMap) *key.Binding { return &k.NewFolder }},
+}
+
+// keyPresets are the built-in keymaps, each listing the keys of every action. The first
+// key that can be shown on a screen is the one its help bar names.
+var keyPresets = map[string]map[string][]string{
+	"default": {
+		"up":            {"up", "k"},
+		"down":          {"down", "j"},
+		"page_up":       {"pgup"},
+		"page_down":     {"pgdown"},
+		"select":        {"enter"},
+		"back":          {"esc"},
+		"quit":          {"q", "ctrl+c"},
+		"delete":        {"D"},
+		"confirm":       {"y", "Y"},
+		"deny":          {"n", "N"},
+		"palette":       {"ctrl+p"},
+		"preview_up":    {"shift+up", "ctrl+u"},
+		"preview_down":  {"shift+down", "ctrl+d"},
+		"save":          {"ctrl+s"},
+		"complete":      {"tab"},
+		"toggle":        {" ", "x"},
+		"left":          {"left", "h"},
+		"right":         {"right", "l"},
+		"move_left":     {"shift+left", "H"},
+		"move_right":    {"shift+right", "L"},
+		"open":          {"tab"},
+		"parent":        {"backspace", "shift+tab"},
+		"toggle_hidden": {"ctrl+t"},
+		"bookmark":      {"ctrl+b"},
+		"places":        {"ctrl+o"},
+		"new_folder":    {"ctrl+f"},
+	},
+	"vim": {
+		"up":            {"k", "up", "ctrl+k"},
+		"down":          {"j", "down", "ctrl+j"},
+		"page_up":       {"ctrl+u", "pgup"},
+		"page_down":     {"ctrl+d", "pgdown"},
+		"select":        {"enter"},
+		"back":          {"esc"},
+		"quit":          {"q", "ctrl+c"},
+		"delete":        {"D", "x"},
+		"confirm":       {"y", "Y"},
+		"deny":          {"n", "N"},
+		"palette":       {"ctrl+p"},
+		"preview_up":    {"ctrl+y", "shift+up"},
+		"preview_down":  {"ctrl+e", "shift+down"},
+		"save":          {"ctrl+s"},
+		"complete":      {"tab"},
+		"toggle":        {"x", " "},
+		"left":          {"h", "left"},
+		"right":         {"l", "right"},
+		"move_left":     {"H", "shift+left"},
+		"move_right":    {"L", "shift+right"},
+		"open":          {"l", "tab", "ctrl+l"},
+		"parent":        {"h", "backspace", "ctrl+h", "shift+tab"},
+		"toggle_hidden": {"ctrl+t"},
+		"bookmark":      {"m", "ctrl+b"},
+		"places":        {"'", "ctrl+o"},
+		"new_folder":    {"ctrl+f"},
+	},
+	// The emacs preset leaves ctrl+b and ctrl+f to move the cursor in text inputs
+	"emacs": {
+		"up":            {"ctrl+p", "up"},
+		"down":          {"ctrl+n", "down"},
+		"page_up":       {"alt+v", "pgup"},
+		"page_down":     {"ctrl+v", "pgdown"},
+		"select":        {"enter"},
+		"back":          {"ctrl+g", "esc"},
+		"quit":          {"q", "ctrl+c"},
+		"delete":        {"D", "ctrl+d"},
+		"confirm":       {"y", "Y"},
+		"deny":          {"n", "N"},
+		"palette":       {"alt+x"},
+		"preview_up":    {"alt+up", "shift+up"},
+		"preview_down":  {"alt+down", "shift+down"},
+		"save":          {"ctrl+s"},
+		"complete":      {"tab"},
+		"toggle":        {" "},
+		"left":          {"ctrl+b", "left"},
+		"right":         {"ctrl+f", "right"},
+		"move_left":     {"alt+b", "shift+left"},
+		"move_right":    {"alt+f", "shift+right"},
+		"open":          {"tab"},
+		"parent":        {"backspace", "shift+tab"},
+		"toggle_hidden": {"ctrl+t"},
+		"bookmark":      {"alt+m"},
+		"places":        {"ctrl+o"},
+		"new_folder":    {"alt+n"},
+	},
+}
+
+// KeyMapNames returns the names of the built-in keymaps.
+func KeyMapNames() []string {
+	names := make([]string, 0, len(keyPresets))
+	for name := range keyPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// KeyActions returns the action names that keymaps bind, in display order.
+func KeyActions() []string {
+	names := make([]string, len(keyActions))
+	for i, a := range keyActions {
+		names[i] = a.name
+	}
+	return names
+}
+
+// LoadKeyMap builds the preset keymap called name with the keys of some actions replaced
+// by overrides, keyed by action name. An action overridden with no keys is unbound.
+func LoadKeyMap(name string, overrides map[string][]string) (KeyMap, error) {
+	preset, ok := keyPresets[name]
+	if !ok {
+		return KeyMap{}, fmt.Errorf("unknown keymap %q (want %s)", name, strings.Join(KeyMapNames(), ", "))
+	}
+	for action := range overrides {
+		if _, ok := lookupKeyAction(action); !ok {
+			return KeyMap{}, fmt.Errorf("unknown key action %q (want %s)", action, strings.Join(KeyActions(), ", "))
+		}
+	}
+	km := KeyMap{Name: name}
+	for _, a := range keyActions {
+		keys := preset[a.name]
+		if custom, ok := overrides[a.name]; ok {
+			keys = normalizeKeys(custom)
+		}
+		b := key.NewBinding(key.WithKeys(keys...), key.WithHelp(strings.Join(keys, " "), a.desc))
+		if len(keys) == 0 {
+			b.SetEnabled(false)
+		}
+		*a.binding(&km) = b
+	}
+	return km, nil
+}
+
+func lookupKeyAction(name string) (keyAction, bool) {
+	for _, a := range keyActions {
+		if a.name == name {
+			return a, true
+		}
+	}
+	return keyAction{}, false
+}
+
+// normalizeKeys accepts key names as written in config files, e.g. "Ctrl+S" or "space",
+// and returns them as Bubble Tea spells them.
+func normalizeKeys(keys []string) []string {
+	var out []string
+	for _, k := range keys {
+		k = strings.TrimSpace(k)
+		switch {
+		case k == "":
+			continue
+		case strings.EqualFold(k, "space"):
+			k = " "
+		case len([]rune(k)) > 1:
+			// Single characters keep their case so that D and d stay apart
+			k = strings.ToLower(k)
+		}
+		out = append(out, k)
+	}
+	return out
+}
+
+// keymap is the keymap every TUI screen reads its bindings from.
+var keymap KeyMap
+
+func init() {
+	keymap, _ = LoadKeyMap(DefaultKeyMap, nil)
+}
+
+// ActiveKeyMap returns the keymap the TUI currently uses.
+func ActiveKeyMap() KeyMap {
+	return keymap
+}
+
+// SetKeyMap makes km the keymap of every TUI screen.
+func SetKeyMap(km KeyMap) {
+	keymap = km
+}
+
+// KeyBinding describes the keys bound to an action, for listing a keymap.
+type KeyBinding struct {
+	Action      string
+	Keys        []string
+	Description string
+}
+
+// Bindings lists the bindings of km in display order.
+func (km KeyMap) Bindings() []KeyBinding {
+	var bindings []KeyBinding
+	for _, a := range keyActions {
+		b := *a.binding(&km)
+		var keys []string
+		if b.Enabled() {
+			for _, k := range b.Keys() {
+				keys = append(keys, keyLabel(k))
+			}
+		}
+		bindings = append(bindings, KeyBinding{Action: a.name, Keys: keys, Description: a.desc})
+	}
+	return bindings
+}
+
+// insertsText reports whether k is a key that types into a text input.
+func insertsText(k string) bool {
+	return len([]rune(k)) == 1
+}
+
+// matchKey reports whether msg triggers one of bindings. While typing, keys that insert
+// text are left to the text input.
+func matchKey(msg tea.KeyMsg, typing bool, bindings ...key.Binding) bool {
+	if typing && insertsText(msg.String()) {
+		return false
+	}
+	return key.Matches(msg, bindings...)
+}
+
+// helpEntry is one "keys: description" item of a help bar.
+type helpEntry struct {
+	desc     string
+	bindings []key.Binding
+}
+
+func keyHelp(desc string, bindings ...key.Binding) helpEntry {
+	return helpEntry{desc: desc, bindings: bindings}
+}
+
+// helpText renders entries for a help bar, naming for each binding the first key that
+// works on the screen. Entries with no usable key are left out, so the help always
+// matches the active keymap.
+func helpText(typing bool, entries ...helpEntry) string {
+	var items []string
+	for _, e := range entries {
+		var labels []string
+		for _, b := range e.bindings {
+			if !b.Enabled() {
+				continue
+			}
+			for _, k := range b.Keys() {
+				if typing && insertsText(k) {
+					continue
+				}
+				labels = append(labels, keyLabel(k))
+				break
+			}
+		}
+		if len(labels) > 0 {
+			items = append(items, strings.Join(labels, "/")+": "+e.desc)
+		}
+	}
+	return strings.Join(items, "   ")
+}
+
+// keyLabel spells a key the way help bars show it.
+func keyLabel(k string) string {
+	switch k {
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "backspace":
+		return "⌫"
+	case "shift+tab":
+		return "⇧Tab"
+	case "shift+up":
+		return "⇧↑"
+	case "shift+down":
+		return "⇧↓"
+	case "shift+left":
+		return "⇧←"
+	case "shift+right":
+		return "⇧→"
+	case "pgup":
+		return "PgUp"
+	case "pgdown":
+		return "PgDn"
+	case " ":
+		return "Space"
+	}
+	if rest, ok := strings.CutPrefix(k, "ctrl+"); ok && len(rest) == 1 {
+		return "^" + strings.ToUpper(rest)
+	}
+	if len([]rune(k)) > 1 {
+		return strings.ToUpper(k[:1]) + k[1:]
+	}
+	return k
+}
+
+// confirmPrompt spells the keys that answer a yes/no question, e.g. "[y/n]".
+func confirmPrompt() string {
+	first := func(b key.Binding) string {
+		if keys := b.Keys(); b.Enabled() && len(keys) > 0 {
+			return keyLabel(keys[0])
+		}
+		return "?"
+	}
+	return "[" + first(keymap.Confirm) + "/" + first(keymap.Deny) + "]"
+}
+
+// deletesText reports whether msg deletes a character in a text input.
+func deletesText(msg tea.KeyMsg) bool {
+	return msg.Type == tea.KeyBackspace || msg.Type == tea.KeyCtrlH
+}
@@ -0,0 +1,39 @@
+package tui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// programOptions are the options every TUI runs with. The alternate screen puts the view
+// at the top left of the terminal, so the mouse's coordinates line up with its rows.
+func programOptions() []tea.ProgramOption {
+	return []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+}
+
+// wheelDelta returns -1 when msg scrolls the wheel up, 1 when it scrolls down and 0 for
+// any other mouse event.
+func wheelDelta(msg tea.MouseMsg) int {
+	if msg.Action != tea.MouseActionPress {
+		return 0
+	}
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		return -1
+	case tea.MouseButtonWheelDown:
+		return 1
+	}
+	return 0
+}
+
+// clicked reports whether msg is a press of the left button.
+func clicked(msg tea.MouseMsg) bool {
+	return msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft
+}
+
+// clickedRow returns the index of the row under a click on a list whose first shown row,
+// offset, is drawn on line top of the screen, or -1 if the click missed the rows.
+func clickedRow(msg tea.MouseMsg, top, offset, shown, rows int) int {
+	i := msg.Y - top
+	if !clicked(msg) || i < 0 || i >= shown || offset+i >= rows {
+		return -1
+	}
+	return offset + i
+}
@@ -0,0 +1,227 @@
+package tui
+
+import (
+	"strings"
+
+	textinput "github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// paletteHeight is how many actions the command palette shows at once, at most.
+const paletteHeight = 10
+
+// paletteRowsTop is the screen line of the first action shown, below the border, the
+// header and the query.
+const paletteRowsTop = 5
+
+// PaletteAction is an action offered by the command palette.
+type PaletteAction struct {
+	ID          string
+	Title       string
+	Description string
+	Prompt      string // Asks for an argument before running the action, if set
+}
+
+// paletteModel fuzzy-searches actions and, for actions that take an argument, asks
+// for it.
+type paletteModel struct {
+	actions   []PaletteAction
+	input     textinput.Model
+	arg       textinput.Model
+	matches   []fuzzyCandidate
+	cursor    int
+	offset    int
+	prompting bool
+	open      bool
+	height    int // How many actions fit on screen
+
+	// Set once the user picked an action
+	chosen   *PaletteAction
+	argValue string
+}
+
+func newPaletteModel(actions []PaletteAction) paletteModel {
+	ti := textinput.New()
+	ti.Placeholder = "Type a command"
+	ti.CharLimit = 128
+	ti.Width = 48
+	ai := textinput.New()
+	ai.CharLimit = 256
+	ai.Width = 48
+	m := paletteModel{actions: actions, input: ti, arg: ai, height: paletteHeight}
+	m.refresh()
+	return m
+}
+
+// setHeight fits the palette into rows lines of actions.
+func (m *paletteModel) setHeight(rows int) {
+	m.height = rows
+	if m.height > paletteHeight {
+		m.height = paletteHeight
+	}
+	m.move(0)
+}
+
+// show opens the palette with an empty query.
+func (m *paletteModel) show() tea.Cmd {
+	m.open = true
+	m.prompting = false
+	m.input.SetValue("")
+	m.arg.Blur()
+	m.refresh()
+	return m.input.Focus()
+}
+
+func (m *paletteModel) refresh() {
+	titles := make([]string, len(m.actions))
+	for i, a := range m.actions {
+		titles[i] = a.Title
+	}
+	m.matches = fuzzyFilter(m.input.Value(), titles)
+	m.cursor, m.offset = 0, 0
+}
+
+func (m *paletteModel) move(delta int) {
+	m.cursor += delta
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	if m.cursor >= len(m.matches) {
+		m.cursor = len(m.matches) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+func (m paletteModel) Update(msg tea.Msg) (paletteModel, tea.Cmd) {
+	if m.prompting {
+		return m.updatePrompt(msg)
+	}
+	if msg, ok := msg.(tea.MouseMsg); ok {
+		if delta := wheelDelta(msg); delta != 0 {
+			m.move(delta)
+			return m, nil
+		}
+		i := clickedRow(msg, paletteRowsTop, m.offset, m.height, len(m.matches))
+		if i < 0 {
+			return m, nil
+		}
+		if i != m.cursor {
+			m.cursor = i
+			return m, nil
+		}
+		// A second click runs the action, as if it were selected with the keyboard
+		return m.pick()
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case matchKey(msg, true, keymap.Back, keymap.Quit, keymap.Palette):
+			m.open = false
+			m.input.Blur()
+			return m, nil
+		case matchKey(msg, true, keymap.Select):
+			return m.pick()
+		case matchKey(msg, true, keymap.Up):
+			m.move(-1)
+			return m, nil
+		case matchKey(msg, true, keymap.Down):
+			m.move(1)
+			return m, nil
+		case matchKey(msg, true, keymap.PageUp):
+			m.move(-m.height)
+			return m, nil
+		case matchKey(msg, true, keymap.PageDown):
+			m.move(m.height)
+			return m, nil
+		}
+	}
+	before := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != before {
+		m.refresh()
+	}
+	return m, cmd
+}
+
+// pick chooses the highlighted action, first asking for its argument if it takes one.
+func (m paletteModel) pick() (paletteModel, tea.Cmd) {
+	if len(m.matches) == 0 {
+		return m, nil
+	}
+	action := m.actions[m.matches[m.cursor].Index]
+	if action.Prompt == "" {
+		m.chosen = &action
+		return m, nil
+	}
+	m.prompting = true
+	m.input.Blur()
+	m.arg.Placeholder = action.Prompt
+	m.arg.SetValue("")
+	return m, m.arg.Focus()
+}
+
+// updatePrompt handles keys while the argument of an action is being typed.
+func (m paletteModel) updatePrompt(msg tea.Msg) (paletteModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case matchKey(msg, true, keymap.Back, keymap.Quit):
+			m.prompting = false
+			m.arg.Blur()
+			return m, m.input.Focus()
+		case matchKey(msg, true, keymap.Select):
+			value := strings.TrimSpace(m.arg.Value())
+			if value == "" {
+				return m, nil
+			}
+			action := m.actions[m.matches[m.cursor].Index]
+			m.chosen = &action
+			m.argValue = value
+			return m, nil
+		}
+	}
+	var cmd tea.Cmd
+	m.arg, cmd = m.arg.Update(msg)
+	return m, cmd
+}
+
+func (m paletteModel) View() string {
+	if m.prompting {
+		action := m.actions[m.matches[m.cursor].Index]
+		help := helpText(true, keyHelp("Run", keymap.Select), keyHelp("Back", keymap.Back))
+		return headerStyle.Render(action.Title) + "\n" + m.arg.View() + "\n\n" + helpBarStyle.Render(help)
+	}
+	var lines []string
+	end := m.offset + m.height
+	if end > len(m.matches) {
+		end = len(m.matches)
+	}
+	for i := m.offset; i < end; i++ {
+		match := m.matches[i]
+		action := m.actions[match.Index]
+		if i == m.cursor {
+			lines = append(lines, selectedStyle.Render(" ")+highlightMatches(action.Title, match.Positions, selectedStyle)+selectedStyle.Render(" ")+actionDescription(action))
+		} else {
+			lines = append(lines, " "+highlightMatches(action.Title, match.Positions, dirPickerItemStyle)+" "+actionDescription(action))
+		}
+	}
+	if len(lines) == 0 {
+		lines = append(lines, helpBarStyle.Render("No matching commands."))
+	}
+	help := helpText(true, keyHelp("Move", keymap.Up, keymap.Down), keyHelp("Run", keymap.Select), keyHelp("Close", keymap.Back, keymap.Palette))
+	return headerStyle.Render("Commands") + "\n" + m.input.View() + "\n\n" + strings.Join(lines, "\n") + "\n\n" + helpBarStyle.Render(help)
+}
+
+func actionDescription(a PaletteAction) string {
+	if a.Description == "" {
+		return ""
+	}
+	return "  " + helpBarStyle.Render(a.Description)
+}
@@ -0,0 +1,199 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	"github.com/charmbracelet/glamour"
+	"github.com/charmbracelet/lipgloss"
+
+	"cobra-cli/internal/notes"
+)
+
+// previewMinWidth is the narrowest terminal that gets a preview pane next to a list.
+const previewMinWidth = 90
+
+// previewModel shows a note rendered as markdown in a scrollable viewport. Rendered notes
+// are cached per width, so moving back to a note or resizing back is instant.
+type previewModel struct {
+	load     func(rel string) (string, error)
+	viewport viewport.Model
+	rel      string
+	width    int
+	height   int
+	renderer *glamour.TermRenderer
+	cache    map[string]string
+}
+
+func newPreviewModel(load func(rel string) (string, error)) previewModel {
+	return previewModel{load: load, viewport: viewport.New(0, 0), cache: map[string]string{}}
+}
+
+// enabled reports whether there is anything to preview with.
+func (m previewModel) enabled() bool {
+	return m.load != nil
+}
+
+// setSize resizes the pane, borders included, and reflows the note for the new width.
+func (m *previewModel) setSize(width, height int) {
+	// Border and padding take two rows and four columns
+	width, height = width-4, height-3
+	if width < 10 {
+		width = 10
+	}
+	if height < 1 {
+		height = 1
+	}
+	if width != m.width {
+		m.renderer = nil
+	}
+	m.width, m.height = width, height
+	m.viewport.Width, m.viewport.Height = width, height
+	if m.rel != "" {
+		rel := m.rel
+		m.rel = ""
+		m.show(rel)
+	}
+}
+
+// show previews the note rel, from the top.
+func (m *previewModel) show(rel string) {
+	if rel == m.rel || m.width == 0 {
+		return
+	}
+	m.rel = rel
+	key := fmt.Sprintf("%d:%s", m.width, rel)
+	content, ok := m.cache[key]
+	if !ok {
+		content = m.render(rel)
+		m.cache[key] = content
+	}
+	m.viewport.SetContent(content)
+	m.viewport.GotoTop()
+}
+
+func (m *previewModel) render(rel string) string {
+	if rel == "" {
+		return ""
+	}
+	source, err := m.load(rel)
+	if err != nil {
+		return dirPickerDimStyle.Render(err.Error())
+	}
+	if m.renderer == nil {
+		r, err := glamour.NewTermRenderer(
+			glamour.WithStyles(markdownStyle),
+			glamour.WithColorProfile(lipgloss.ColorProfile()),
+			glamour.WithWordWrap(m.width),
+		)
+		if err != nil {
+			return source
+		}
+		m.renderer = r
+	}
+	fm, body := notes.Frontmatter(source)
+	rendered, err := m.renderer.Render(markdownLinks(body))
+	if err != nil {
+		return source
+	}
+	return renderProperties(fm, m.width) + strings.Trim(rendered, "\n")
+}
+
+// markdownLinks turns wiki links into markdown links the renderer can style. Links to
+// "#" show their text only.
+func markdownLinks(body string) string {
+	return notes.ReplaceLinks(body, func(l notes.Link, embed bool) string {
+		text := l.Alias
+		if text == "" {
+			text = l.Target
+			if l.Heading != "" {
+				text += " › " + l.Heading
+			}
+			if l.CrossVault() {
+				text = l.Vault + ": " + text
+			}
+		}
+		if embed {
+			text = "↳ " + text
+		}
+		text = strings.NewReplacer(`\`, `\\`, "[", `\[`, "]", `\]`).Replace(text)
+		return "[" + text + "](#)"
+	})
+}
+
+// renderProperties renders frontmatter as a compact header of one property per line.
+func renderProperties(fm map[string]any, width int) string {
+	if len(fm) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(fm))
+	pad := 0
+	for k := range fm {
+		keys = append(keys, k)
+		if len(k) > pad {
+			pad = len(k)
+		}
+	}
+	sort.Strings(keys)
+	var lines []string
+	for _, k := range keys {
+		value := formatProperty(fm[k])
+		if max := width - pad - 2; max > 1 && len([]rune(value)) > max {
+			value = string([]rune(value)[:max-1]) + "…"
+		}
+		lines = append(lines, previewKeyStyle.Render(fmt.Sprintf("%-*s", pad, k))+"  "+value)
+	}
+	rule := previewKeyStyle.Render(strings.Repeat("─", width))
+	return strings.Join(lines, "\n") + "\n" + rule + "\n"
+}
+
+func formatProperty(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case []any:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = formatProperty(item)
+		}
+		return strings.Join(items, ", ")
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := make([]string, len(keys))
+		for i, k := range keys {
+			items[i] = k + ": " + formatProperty(v[k])
+		}
+		return strings.Join(items, ", ")
+	case time.Time:
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 {
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02 15:04")
+	}
+	return fmt.Sprint(v)
+}
+
+func (m previewModel) View() string {
+	title := previewTitleStyle.Render(truncateStart(m.rel, m.width))
+	scroll := ""
+	if m.viewport.TotalLineCount() > m.viewport.Height {
+		scroll = fmt.Sprintf(" %d%%", int(m.viewport.ScrollPercent()*100))
+	}
+	return previewBorderStyle.Render(title + helpBarStyle.Render(scroll) + "\n" + m.viewport.View())
+}
+
+// truncateStart shortens s to width columns, keeping its end.
+func truncateStart(s string, width int) string {
+	r := []rune(s)
+	if width < 2 || len(r) <= width {
+		return s
+	}
+	return "…" + string(r[len(r)-width+1:])
+}
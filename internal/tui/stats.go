@@ -0,0 +1,148 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"cobra-cli/internal/vault"
+)
+
+const (
+	statsPanelWidth = 40
+	statsRows       = 8  // Rows shown per ranked panel
+	statsBarWidth   = 14 // Width of the longest bar
+	statsMonths     = 12 // Months shown in the activity panel
+)
+
+// RenderStats renders vault statistics as a dashboard of panels, two per row.
+func RenderStats(s vault.Stats) string {
+	summary := []string{
+		statsLine("Notes", s.Notes),
+		statsLine("Folders", s.Folders),
+		statsLine("Words", s.Words),
+		statsLine("Size", formatBytes(s.Bytes)),
+		statsLine("Links", s.Links),
+		statsLine("Broken links", s.BrokenLinks),
+		statsLine("Orphans", len(s.Orphans)),
+		statsLine("Tags", len(s.Tags)),
+	}
+
+	var largest []string
+	for _, n := range s.Largest {
+		if len(largest) == statsRows {
+			break
+		}
+		largest = append(largest, fmt.Sprintf("%-26s %s", truncate(n.Rel, 26), statsValueStyle.Render(fmt.Sprintf("%6d w", n.Words))))
+	}
+
+	var orphans []string
+	for _, rel := range s.Orphans {
+		if len(orphans) == statsRows-1 {
+			orphans = append(orphans, statsLabelStyle.Render(fmt.Sprintf("… and %d more", len(s.Orphans)-len(orphans))))
+			break
+		}
+		orphans = append(orphans, truncate(rel, statsPanelWidth-2))
+	}
+
+	rows := [][2]string{
+		{statsPanel("📊 "+s.Vault, summary), statsPanel("📁 By folder", barChart(rankCounts(s.ByFolder)))},
+		{statsPanel("🏷  Top tags", barChart(rankCounts(s.Tags))), statsPanel("📄 By type", barChart(rankCounts(s.ByType)))},
+		{statsPanel("✏️  Created per month", barChart(lastPeriods(s.Created))), statsPanel("🕒 Modified per month", barChart(lastPeriods(s.Modified)))},
+		{statsPanel("📏 Largest notes", largest), statsPanel("🏝  Orphan notes", orphans)},
+	}
+	var out []string
+	for _, r := range rows {
+		out = append(out, lipgloss.JoinHorizontal(lipgloss.Top, r[0], " ", r[1]))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, out...)
+}
+
+func statsPanel(title string, lines []string) string {
+	if len(lines) == 0 {
+		lines = []string{statsLabelStyle.Render("(none)")}
+	}
+	return statsPanelStyle.Render(headerStyle.Render(title) + "\n" + strings.Join(lines, "\n"))
+}
+
+func statsLine(label string, value any) string {
+	return statsLabelStyle.Render(fmt.Sprintf("%-14s", label)) + " " + statsValueStyle.Render(fmt.Sprint(value))
+}
+
+// bar is one labelled row of a bar chart.
+type bar struct {
+	label string
+	count int
+}
+
+// rankCounts orders counts from largest to smallest, keeping the top rows.
+func rankCounts(counts map[string]int) []bar {
+	ranked := make([]bar, 0, len(counts))
+	for k, n := range counts {
+		ranked = append(ranked, bar{label: k, count: n})
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].count != ranked[j].count {
+			return ranked[i].count > ranked[j].count
+		}
+		return ranked[i].label < ranked[j].label
+	})
+	if len(ranked) > statsRows {
+		ranked = ranked[:statsRows]
+	}
+	return ranked
+}
+
+// lastPeriods keeps the most recent months.
+func lastPeriods(periods []vault.PeriodCount) []bar {
+	if len(periods) > statsMonths {
+		periods = periods[len(periods)-statsMonths:]
+	}
+	bars := make([]bar, len(periods))
+	for i, p := range periods {
+		bars[i] = bar{label: p.Period, count: p.Count}
+	}
+	return bars
+}
+
+// barChart renders one labelled bar per count, scaled to the largest count.
+func barChart(bars []bar) []string {
+	max := 0
+	for _, b := range bars {
+		if b.count > max {
+			max = b.count
+		}
+	}
+	lines := make([]string, 0, len(bars))
+	for _, b := range bars {
+		width := 1
+		if max > 0 {
+			width = b.count * statsBarWidth / max
+		}
+		if width < 1 {
+			width = 1
+		}
+		lines = append(lines, fmt.Sprintf("%-16s %s %d", truncate(b.label, 16), statsBarStyle.Render(strings.Repeat("█", width)), b.count))
+	}
+	return lines
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(n)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", n)
+}
@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// taskListHeight is how many tasks the task list shows until it knows the terminal size.
+const taskListHeight = 15
+
+// taskRowsTop is the screen line of the first task shown, below the border and the header.
+const taskRowsTop = 4
+
+// TaskItem is a task listed in the task list.
+type TaskItem struct {
+	Rel     string // Note the task is in
+	Line    int
+	Text    string
+	Done    bool
+	Detail  string // Shown after the text, e.g. when the task is due
+	Overdue bool   // Draws the detail as a warning
+}
+
+// TaskListOptions describe the tasks the task list shows.
+type TaskListOptions struct {
+	Title string
+	Tasks []TaskItem
+	// Toggle checks or unchecks a task in its note and returns whether it is now done.
+	Toggle func(t TaskItem) (bool, error)
+}
+
+// TaskListResult tells what happened in the task list.
+type TaskListResult struct {
+	Toggled int // How many times a task was checked or unchecked
+}
+
+// LaunchTaskList lists tasks and lets the user check them off until they quit.
+func LaunchTaskList(opts TaskListOptions) (TaskListResult, error) {
+	p := tea.NewProgram(newTaskListModel(opts), programOptions()...)
+	finalModel, err := p.Run()
+	if err != nil {
+		return TaskListResult{}, err
+	}
+	return finalModel.(taskListModel).result, nil
+}
+
+type taskListModel struct {
+	title  string
+	tasks  []TaskItem
+	toggle func(t TaskItem) (bool, error)
+	cursor int
+	offset int
+	height int // How many tasks fit on screen
+	err    string
+	result TaskListResult
+}
+
+func newTaskListModel(opts TaskListOptions) taskListModel {
+	return taskListModel{title: opts.Title, tasks: opts.Tasks, toggle: opts.Toggle, height: taskListHeight}
+}
+
+func (m taskListModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m *taskListModel) move(delta int) {
+	m.cursor += delta
+	if m.cursor >= len(m.tasks) {
+		m.cursor = len(m.tasks) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.height {
+		m.offset = m.cursor - m.height + 1
+	}
+}
+
+// toggleCurrent checks or unchecks the highlighted task in its note.
+func (m *taskListModel) toggleCurrent() {
+	if m.cursor >= len(m.tasks) {
+		return
+	}
+	done, err := m.toggle(m.tasks[m.cursor])
+	if err != nil {
+		m.err = "✗ " + err.Error()
+		return
+	}
+	m.err = ""
+	m.tasks[m.cursor].Done = done
+	m.result.Toggled++
+}
+
+func (m taskListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		// Border, padding, header, help bar and the blank lines around the tasks
+		m.height = msg.Height - 9
+		if m.height < 3 {
+			m.height = 3
+		}
+		m.move(0)
+	case tea.MouseMsg:
+		if delta := wheelDelta(msg); delta != 0 {
+			m.move(delta)
+			return m, nil
+		}
+		// A click highlights a task and a second click checks it off
+		if i := clickedRow(msg, taskRowsTop, m.offset, m.height, len(m.tasks)); i == m.cursor {
+			m.toggleCurrent()
+		} else if i >= 0 {
+			m.cursor = i
+		}
+	case tea.KeyMsg:
+		switch {
+		case matchKey(msg, false, keymap.Back, keymap.Quit):
+			return m, tea.Quit
+		case matchKey(msg, false, keymap.Toggle, keymap.Select):
+			m.toggleCurrent()
+		case matchKey(msg, false, keymap.Up):
+			m.move(-1)
+		case matchKey(msg, false, keymap.Down):
+			m.move(1)
+		case matchKey(msg, false, keymap.PageUp):
+			m.move(-m.height)
+		case matchKey(msg, false, keymap.PageDown):
+			m.move(m.height)
+		}
+	}
+	return m, nil
+}
+
+func (m taskListModel) View() string {
+	open := 0
+	for _, t := range m.tasks {
+		if !t.Done {
+			open++
+		}
+	}
+	header := headerStyle.Render("☑ "+m.title) + helpBarStyle.Render(fmt.Sprintf("%d open of %d", open, len(m.tasks)))
+	var lines []string
+	end := m.offset + m.height
+	if end > len(m.tasks) {
+		end = len(m.tasks)
+	}
+	for i := m.offset; i < end; i++ {
+		lines = append(lines, m.renderTask(i))
+	}
+	if len(lines) == 0 {
+		lines = append(lines, helpBarStyle.Render("No tasks."))
+	}
+	help := helpText(false, keyHelp("Move", keymap.Up, keymap.Down), keyHelp("Check off", keymap.Toggle), keyHelp("Quit", keymap.Back, keymap.Quit))
+	footer := helpBarStyle.Render(help)
+	if m.err != "" {
+		footer = errorStyle.Render(m.err) + "\n" + footer
+	}
+	return borderStyle.Render(header + "\n\n" + strings.Join(lines, "\n") + "\n\n" + footer)
+}
+
+func (m taskListModel) renderTask(i int) string {
+	t := m.tasks[i]
+	box, text := "[ ]", itemStyle
+	if t.Done {
+		box, text = "[x]", dirPickerDimStyle.Padding(0, 1).Strikethrough(!noColor)
+	}
+	line := box + " " + t.Text
+	if i == m.cursor {
+		line = selectedStyle.Render(" " + line + " ")
+	} else {
+		line = text.Render(line)
+	}
+	detail := helpBarStyle.Render(fmt.Sprintf("%s:%d", t.Rel, t.Line))
+	if t.Detail != "" {
+		style := helpBarStyle
+		if t.Overdue && !t.Done {
+			style = errorStyle.Padding(0, 1)
+		}
+		detail = style.Render(t.Detail) + detail
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, line, " ", detail)
+}
@@ -0,0 +1,204 @@
+package tui
+
+import (
+	"github.com/charmbracelet/glamour/ansi"
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"cobra-cli/internal/theme"
+)
+
+// The styles every component draws from, built from the active theme by SetTheme.
+var (
+	headerStyle    lipgloss.Style
+	selectedStyle  lipgloss.Style
+	itemStyle      lipgloss.Style
+	buttonStyle    lipgloss.Style
+	helpBarStyle   lipgloss.Style
+	errorStyle     lipgloss.Style
+	successStyle   lipgloss.Style
+	borderStyle    lipgloss.Style
+	modalStyle     lipgloss.Style
+	infoPanelStyle lipgloss.Style
+
+	viewerSectionStyle lipgloss.Style
+	fuzzyMatchStyle    lipgloss.Style
+
+	statsPanelStyle lipgloss.Style
+	statsLabelStyle lipgloss.Style
+	statsValueStyle lipgloss.Style
+	statsBarStyle   lipgloss.Style
+
+	dirPickerHeaderStyle   lipgloss.Style
+	dirPickerSelectedStyle lipgloss.Style
+	dirPickerItemStyle     lipgloss.Style
+	dirPickerBorderStyle   lipgloss.Style
+	dirPickerErrorStyle    lipgloss.Style
+	dirPickerSectionStyle  lipgloss.Style
+	dirPickerDimStyle      lipgloss.Style
+	dirPickerMarkStyle     lipgloss.Style
+
+	previewBorderStyle lipgloss.Style
+	previewTitleStyle  lipgloss.Style
+	previewKeyStyle    lipgloss.Style
+
+	editorLineNumberStyle lipgloss.Style
+	editorCursorLineStyle lipgloss.Style
+	editorDirtyStyle      lipgloss.Style
+
+	boardColumnStyle       lipgloss.Style
+	boardActiveColumnStyle lipgloss.Style
+
+	// markdownStyle renders notes in the preview pane.
+	markdownStyle ansi.StyleConfig
+
+	// heatmapLevels are the cell styles from no activity to the most active days.
+	heatmapLevels []lipgloss.Style
+)
+
+// heatmapShades stand in for the heatmap colors when there are none.
+const heatmapShades = "·░▒▓█"
+
+// noColor is set when the terminal shows no colors, e.g. because NO_COLOR is set or
+// output is not a terminal. Styles then fall back to reverse video and underlines.
+var noColor bool
+
+// activeTheme is the theme the styles were last built from.
+var activeTheme theme.Theme
+
+func init() {
+	t, _ := theme.Builtin(theme.Default)
+	SetTheme(t)
+}
+
+// ActiveTheme returns the theme the TUI currently draws with.
+func ActiveTheme() theme.Theme {
+	return activeTheme
+}
+
+// SetTheme rebuilds every TUI style from t. lipgloss picks the color depth the terminal
+// supports, down to none at all when NO_COLOR is set.
+func SetTheme(t theme.Theme) {
+	activeTheme = t
+	noColor = lipgloss.ColorProfile() == termenv.Ascii
+
+	primary := t.Primary.Lipgloss()
+	accent := t.Accent.Lipgloss()
+	muted := t.Muted.Lipgloss()
+	text := t.Text.Lipgloss()
+
+	headerStyle = lipgloss.NewStyle().Bold(true).Foreground(primary).Padding(0, 1)
+	selectedStyle = lipgloss.NewStyle().Foreground(t.OnPrimary.Lipgloss()).Background(primary).Bold(true).Reverse(noColor)
+	itemStyle = lipgloss.NewStyle().Foreground(text).Padding(0, 1)
+	buttonStyle = lipgloss.NewStyle().Foreground(accent).Bold(true)
+	helpBarStyle = lipgloss.NewStyle().Foreground(muted).Faint(t.FaintMuted).Padding(0, 1)
+	errorStyle = lipgloss.NewStyle().Foreground(t.Error.Lipgloss()).Bold(true)
+	successStyle = lipgloss.NewStyle().Foreground(t.Success.Lipgloss()).Bold(true)
+	borderStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(primary).Padding(1, 2)
+	modalStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(accent).Padding(1, 2).Background(t.Surface.Lipgloss())
+	infoPanelStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(muted).Padding(1, 2).Margin(1, 0)
+
+	viewerSectionStyle = lipgloss.NewStyle().Foreground(muted).Bold(true).Padding(0, 1)
+	fuzzyMatchStyle = lipgloss.NewStyle().Foreground(t.Highlight.Lipgloss()).Bold(true).Underline(noColor)
+
+	statsPanelStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(primary).Padding(0, 1).Width(statsPanelWidth)
+	statsLabelStyle = lipgloss.NewStyle().Foreground(muted)
+	statsValueStyle = lipgloss.NewStyle().Bold(true).Foreground(t.Strong.Lipgloss())
+	statsBarStyle = lipgloss.NewStyle().Foreground(accent)
+
+	dirPickerHeaderStyle = headerStyle
+	dirPickerSelectedStyle = selectedStyle
+	dirPickerItemStyle = lipgloss.NewStyle().Foreground(text)
+	dirPickerBorderStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(primary).Padding(0, 1)
+	dirPickerErrorStyle = errorStyle
+	dirPickerSectionStyle = lipgloss.NewStyle().Foreground(muted).Bold(true)
+	dirPickerDimStyle = lipgloss.NewStyle().Foreground(muted).Faint(t.FaintMuted)
+	dirPickerMarkStyle = lipgloss.NewStyle().Foreground(t.Warning.Lipgloss())
+
+	previewBorderStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(muted).Padding(0, 1)
+	previewTitleStyle = lipgloss.NewStyle().Foreground(primary).Bold(true)
+	previewKeyStyle = lipgloss.NewStyle().Foreground(muted)
+	markdownStyle = newMarkdownStyle(t)
+
+	editorLineNumberStyle = lipgloss.NewStyle().Foreground(muted).Faint(t.FaintMuted)
+	editorCursorLineStyle = lipgloss.NewStyle().Foreground(primary)
+	editorDirtyStyle = lipgloss.NewStyle().Foreground(t.Warning.Lipgloss()).Bold(true)
+
+	boardColumnStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(muted).Padding(0, 1)
+	boardActiveColumnStyle = boardColumnStyle.BorderForeground(primary)
+
+	heatmapLevels = make([]lipgloss.Style, theme.HeatmapLevels)
+	for i := range heatmapLevels {
+		heatmapLevels[i] = lipgloss.NewStyle()
+		if i < len(t.Heatmap) {
+			heatmapLevels[i] = heatmapLevels[i].Foreground(t.Heatmap[i].Lipgloss())
+		}
+	}
+}
+
+// newMarkdownStyle adapts glamour's dark or light style to t: headings take the primary
+// color and wiki links the accent color.
+func newMarkdownStyle(t theme.Theme) ansi.StyleConfig {
+	if noColor {
+		return styles.NoTTYStyleConfig
+	}
+	s := styles.DarkStyleConfig
+	if t.Light() {
+		s = styles.LightStyleConfig
+	}
+	color := func(c theme.Color) *string {
+		if c.IsZero() {
+			return nil
+		}
+		v := c.TrueColor
+		return &v
+	}
+	s.Heading.StylePrimitive.Color = color(t.Primary)
+	s.H1.StylePrimitive.Color = color(t.OnPrimary)
+	s.H1.StylePrimitive.BackgroundColor = color(t.Primary)
+	s.LinkText.Color = color(t.Accent)
+	s.Link.Color = color(t.Muted)
+	s.Document.Margin = nil
+	return s
+}
+
+// RenderThemePreview shows the colors of t and a sample of the components drawn with it.
+func RenderThemePreview(t theme.Theme) string {
+	previous := activeTheme
+	SetTheme(t)
+	defer SetTheme(previous)
+
+	swatch := func(name string, c theme.Color) string {
+		block := lipgloss.NewStyle().Foreground(c.Lipgloss()).Render("██")
+		if c.IsZero() {
+			block = "--"
+		}
+		return block + " " + statsLabelStyle.Render(name)
+	}
+	colors := []string{
+		swatch("primary", t.Primary), swatch("on_primary", t.OnPrimary), swatch("accent", t.Accent),
+		swatch("text", t.Text), swatch("strong", t.Strong), swatch("muted", t.Muted),
+		swatch("error", t.Error), swatch("success", t.Success), swatch("warning", t.Warning),
+		swatch("highlight", t.Highlight), swatch("surface", t.Surface),
+	}
+	heatmap := ""
+	for l := range heatmapLevels {
+		heatmap += heatmapCell(l) + " "
+	}
+	colors = append(colors, heatmap+statsLabelStyle.Render("heatmap"))
+
+	sample := []string{
+		headerStyle.Render("📂 " + t.Name),
+		selectedStyle.Render(" " + highlightMatches("projects/noted.md", []int{0, 1, 2}, selectedStyle) + " "),
+		itemStyle.Render(highlightMatches("projects/plan.md", []int{0, 1, 2}, dirPickerItemStyle)),
+		itemStyle.Render("ideas.md") + " " + dirPickerMarkStyle.Render("★"),
+		buttonStyle.Render("+ Create New Vault"),
+		statsLine("Words", 1234),
+		successStyle.Render("✓ Saved") + " " + errorStyle.Render("✗ Failed"),
+		helpBarStyle.Render("↑/↓: Move   Enter: Open   Esc: Quit"),
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		statsPanel("Colors", colors), " ",
+		borderStyle.Render(lipgloss.JoinVertical(lipgloss.Left, sample...)))
+}
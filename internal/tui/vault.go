@@ -1,33 +1,21 @@
 package tui
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 	list "github.com/charmbracelet/bubbles/list"
 	textinput "github.com/charmbracelet/bubbles/textinput"
 
 	"github.com/charmbracelet/lipgloss"
-	log "github.com/charmbracelet/log"
 
+	"cobra-cli/internal/config"
 	"cobra-cli/internal/models"
-)
-
-// --- Lip Gloss Styles ---
-var (
-	headerStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("63")).Padding(0, 1)
-	selectedStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("230")).Background(lipgloss.Color("63")).Bold(true)
-	itemStyle = lipgloss.NewStyle().Padding(0, 1)
-	buttonStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("99")).Bold(true)
-	helpBarStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Faint(true).Padding(0, 1)
-	errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
-	successStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true)
-	borderStyle = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("63")).Padding(1, 2)
-	modalStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("99")).Padding(1, 2).Background(lipgloss.Color("236"))
-	infoPanelStyle = lipgloss.NewStyle().Border(lipgloss.NormalBorder()).BorderForeground(lipgloss.Color("245")).Padding(1, 2).Margin(1, 0)
+	"cobra-cli/internal/vault"
 )
 
 // VaultTUIResult holds the result of the TUI
@@ -35,23 +23,30 @@ var (
 // Path: the selected or created vault path
 // Cancelled: true if the user cancelled
 // Err: any error
+// Created: the preset name if the wizard created a new vault
+// Deleted: vaults whose config was deleted, even if the user then cancelled
 //
 type VaultTUIResult struct {
 	Name      string
 	Path      string
 	Cancelled bool
 	Err       error
+	Created   string
+	Deleted   []models.Vault
+
+	// PickedDir is the directory chosen in the directory picker, if it was used.
+	PickedDir string
+	// Bookmarks is the picker's bookmark list after the user's changes, if BookmarksChanged.
+	Bookmarks        []string
+	BookmarksChanged bool
 }
 
 // LaunchVaultTUI launches the Bubble Tea TUI for vault selection/creation
 func LaunchVaultTUI(vaults []models.Vault, currentVault string) (models.Vault, error) {
-	m := newVaultModel(vaults)
-	p := tea.NewProgram(m)
-	finalModel, err := p.Run()
+	result, err := RunVaultTUI(vaults, currentVault, DirectoryPickerOptions{})
 	if err != nil {
 		return models.Vault{}, err
 	}
-	result := finalModel.(vaultModel).result
 	if result.Cancelled {
 		return models.Vault{}, fmt.Errorf("vault selection cancelled")
 	}
@@ -61,6 +56,18 @@ func LaunchVaultTUI(vaults []models.Vault, currentVault string) (models.Vault, e
 	return models.Vault{Name: result.Name, Path: result.Path}, nil
 }
 
+// RunVaultTUI runs the vault TUI and returns everything that happened in it. places seeds
+// the directory picker used to create vaults.
+func RunVaultTUI(vaults []models.Vault, currentVault string, places DirectoryPickerOptions) (VaultTUIResult, error) {
+	m := newVaultModel(vaults, places)
+	p := tea.NewProgram(m, programOptions()...)
+	finalModel, err := p.Run()
+	if err != nil {
+		return VaultTUIResult{}, err
+	}
+	return finalModel.(vaultModel).result, nil
+}
+
 // --- Bubble Tea Model ---
 
 type vaultState int
@@ -73,6 +80,8 @@ const (
 	stateDone
 	stateDirPicker
 	stateDeleteConfirm
+	statePresetSelect
+	stateReview
 )
 
 type vaultMainMenu int
@@ -103,12 +112,22 @@ type vaultModel struct {
 	editingConfig models.VaultConfig
 
 	dirPicker   directoryPickerModel // Directory picker component
+	places      DirectoryPickerOptions
 	showDirPicker bool
 	showDeleteConfirm bool
 	deleteIdx   int
+	presetIdx   int // Preset highlighted in the creation wizard
+	width       int // Terminal size, zero until known
+	height      int
 }
 
-func newVaultModel(vaults []models.Vault) vaultModel {
+// Screen lines of the first vault and the first preset, below the border and the header
+const (
+	vaultRowsTop  = 3
+	presetRowsTop = 4
+)
+
+func newVaultModel(vaults []models.Vault, places DirectoryPickerOptions) vaultModel {
 	items := make([]list.Item, len(vaults)+1)
 	for i, v := range vaults {
 		items[i] = vaultListItem{v.Name, v.Path}
@@ -119,6 +138,13 @@ func newVaultModel(vaults []models.Vault) vaultModel {
 	l.SetShowStatusBar(false)
 	l.SetShowHelp(false)
 	l.SetShowPagination(false)
+	l.KeyMap.CursorUp = keymap.Up
+	l.KeyMap.CursorDown = keymap.Down
+	l.KeyMap.PrevPage = keymap.PageUp
+	l.KeyMap.NextPage = keymap.PageDown
+	// Quitting goes through Update, which records the cancellation
+	l.KeyMap.Quit.SetEnabled(false)
+	l.KeyMap.ForceQuit.SetEnabled(false)
 	ti := textinput.New()
 	ti.Placeholder = "~/Documents/PersonalKnowledge"
 	ti.CharLimit = 256
@@ -133,7 +159,8 @@ func newVaultModel(vaults []models.Vault) vaultModel {
 		input:     ti,
 		nameInput: ni,
 		state:     stateList,
-		dirPicker: NewDirectoryPickerModel(),
+		places:    places,
+		dirPicker: NewDirectoryPickerModel(places),
 	}
 }
 
@@ -150,28 +177,85 @@ func (m vaultModel) Init() tea.Cmd {
 	return nil
 }
 
+// newDirPicker starts a directory picker sized to the terminal.
+func (m *vaultModel) newDirPicker() {
+	m.dirPicker = NewDirectoryPickerModel(m.places)
+	if m.width > 0 {
+		m.resizeDirPicker()
+	}
+}
+
+// resizeDirPicker fits the directory picker below the wizard's step header.
+func (m *vaultModel) resizeDirPicker() {
+	model, _ := m.dirPicker.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height - 1})
+	m.dirPicker = model.(directoryPickerModel)
+}
+
+// listWindow returns the range of vaults shown, which keeps the highlighted one in view.
+func (m vaultModel) listWindow() (start, end int) {
+	end = len(m.list.Items())
+	// Border, padding, header, help bar and the blank line above it
+	rows := m.height - 8
+	if m.height == 0 || rows >= end {
+		return 0, end
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	if idx := m.list.Index(); idx >= rows {
+		start = idx - rows + 1
+	}
+	return start, start + rows
+}
+
+// chooseListItem picks the highlighted vault, or starts creating one.
+func (m vaultModel) chooseListItem() (tea.Model, tea.Cmd) {
+	idx := m.list.Index()
+	if idx == len(m.vaults) {
+		m.state = stateDirPicker
+		m.newDirPicker()
+		return m, nil
+	}
+	m.result.Name = m.vaults[idx].Name
+	m.result.Path = m.vaults[idx].Path
+	m.state = stateDone
+	return m, tea.Quit
+}
+
 func (m vaultModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width, m.height = msg.Width, msg.Height
+		m.list.SetSize(msg.Width, msg.Height)
+		m.resizeDirPicker()
+		return m, nil
+	}
 	switch m.state {
 	case stateList:
 		switch msg := msg.(type) {
+		case tea.MouseMsg:
+			if delta := wheelDelta(msg); delta < 0 {
+				m.list.CursorUp()
+			} else if delta > 0 {
+				m.list.CursorDown()
+			}
+			start, end := m.listWindow()
+			if i := clickedRow(msg, vaultRowsTop, start, end-start, len(m.list.Items())); i >= 0 {
+				// A click highlights a vault and a second click picks it
+				if i == m.list.Index() {
+					return m.chooseListItem()
+				}
+				m.list.Select(i)
+			}
+			return m, nil
 		case tea.KeyMsg:
-			switch msg.String() {
-			case "q", "esc":
+			switch {
+			case matchKey(msg, false, keymap.Quit, keymap.Back):
 				m.result.Cancelled = true
 				m.state = stateDone
 				return m, tea.Quit
-			case "enter":
-				idx := m.list.Index()
-				if idx == len(m.vaults) {
-					m.state = stateDirPicker
-					m.dirPicker = NewDirectoryPickerModel()
-					return m, nil
-				}
-				m.result.Name = m.vaults[idx].Name
-				m.result.Path = m.vaults[idx].Path
-				m.state = stateDone
-				return m, tea.Quit
-			case "D":
+			case matchKey(msg, false, keymap.Select):
+				return m.chooseListItem()
+			case matchKey(msg, false, keymap.Delete):
 				idx := m.list.Index()
 				if idx < len(m.vaults) {
 					m.state = stateDeleteConfirm
@@ -184,14 +268,27 @@ func (m vaultModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.list, cmd = m.list.Update(msg)
 		return m, cmd
 	case stateDirPicker:
+		if mouse, ok := msg.(tea.MouseMsg); ok {
+			// The picker is drawn below the step header
+			mouse.Y--
+			msg = mouse
+		}
 		model, cmd := m.dirPicker.Update(msg)
 		m.dirPicker = model.(directoryPickerModel)
 		if m.dirPicker.state == 1 {
-			if m.dirPicker.result.Cancelled {
+			picked := m.dirPicker.result
+			// Bookmarks survive a cancelled pick and carry over to the next picker
+			if picked.BookmarksChanged {
+				m.places.Bookmarks = picked.Bookmarks
+				m.result.Bookmarks = picked.Bookmarks
+				m.result.BookmarksChanged = true
+			}
+			if picked.Cancelled {
 				m.state = stateList
 				return m, nil
 			}
-			m.result.Path = m.dirPicker.result.Path
+			m.result.Path = picked.Path
+			m.result.PickedDir = picked.Path
 			m.state = stateNameInput
 			m.nameInput.SetValue(filepath.Base(m.result.Path))
 			m.nameInput.Focus()
@@ -201,50 +298,90 @@ func (m vaultModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case stateNameInput:
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			switch msg.String() {
-			case "esc":
+			switch {
+			case matchKey(msg, true, keymap.Back):
 				m.nameInput.Blur() // Blur name input when leaving
 				m.state = stateInput
 				m.input.Focus() // Refocus path input
 				return m, nil
-			case "enter":
+			case matchKey(msg, true, keymap.Select):
 				name := m.nameInput.Value()
 				if name == "" {
 					m.inputError = "✗ Name cannot be empty."
 					return m, nil
 				}
+				if other, ok := models.FindVault(m.vaults, name); ok && other.Path != m.result.Path {
+					m.inputError = "✗ A vault named " + other.Name + " already exists."
+					return m, nil
+				}
 				m.nameInput.Blur() // Blur name input on done
 				m.result.Name = name
-				// Write VaultConfig to the selected directory
-				cfg := models.VaultConfig{
-					Name: name,
-					TemplatesPath: filepath.Join(m.result.Path, "templates"),
-					LogPath: filepath.Join(m.result.Path, "vault.log"),
-					HistoryPath: filepath.Join(m.result.Path, "history.log"),
-					SupportedTypes: []string{".md", ".pdf"},
-					IgnorePatterns: []string{".git", "node_modules"},
-					Metadata: map[string]string{},
-					Settings: map[string]any{},
-				}
-				if err := writeVaultConfig(m.result.Path, cfg); err != nil {
-					log.Error("Failed to write vault config", "err", err)
-					m.result.Err = err
+				// An existing vault keeps its config; a new one goes through the preset wizard
+				if _, err := os.Stat(config.VaultConfigPath(m.result.Path)); err == nil {
 					m.state = stateDone
 					return m, tea.Quit
 				}
-				log.Info("Vault created", "path", m.result.Path)
-				m.state = stateDone
-				return m, tea.Quit
+				m.state = statePresetSelect
+				return m, nil
 			}
 		}
 		var cmd tea.Cmd
 		m.nameInput, cmd = m.nameInput.Update(msg)
 		return m, cmd
+	case statePresetSelect:
+		switch msg := msg.(type) {
+		case tea.MouseMsg:
+			delta := wheelDelta(msg)
+			if i := m.presetIdx + delta; delta != 0 && i >= 0 && i < len(vault.Presets) {
+				m.presetIdx = i
+			}
+			if i := clickedRow(msg, presetRowsTop, 0, len(vault.Presets), len(vault.Presets)); i >= 0 {
+				if i == m.presetIdx {
+					m.state = stateReview
+				}
+				m.presetIdx = i
+			}
+		case tea.KeyMsg:
+			switch {
+			case matchKey(msg, false, keymap.Up):
+				if m.presetIdx > 0 {
+					m.presetIdx--
+				}
+			case matchKey(msg, false, keymap.Down):
+				if m.presetIdx < len(vault.Presets)-1 {
+					m.presetIdx++
+				}
+			case matchKey(msg, false, keymap.Select):
+				m.state = stateReview
+			case matchKey(msg, false, keymap.Back):
+				m.state = stateNameInput
+				m.nameInput.Focus()
+			}
+		}
+		return m, nil
+	case stateReview:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch {
+			case matchKey(msg, false, keymap.Select, keymap.Confirm):
+				preset := vault.Presets[m.presetIdx]
+				if _, _, err := vault.Create(m.result.Path, m.result.Name, preset); err != nil {
+					m.result.Err = fmt.Errorf("failed to create vault: %w", err)
+				} else {
+					m.result.Created = preset.Name
+				}
+				m.state = stateDone
+				return m, tea.Quit
+			case matchKey(msg, false, keymap.Back, keymap.Deny):
+				m.state = statePresetSelect
+			}
+		}
+		return m, nil
 	case stateConfirmCreate:
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			switch msg.String() {
-			case "y", "Y":
+			switch {
+			case matchKey(msg, false, keymap.Confirm):
 				if err := os.MkdirAll(m.confirmPath, 0o755); err != nil {
 					m.result.Err = fmt.Errorf("failed to create directory: %w", err)
 					m.state = stateDone
@@ -255,7 +392,7 @@ func (m vaultModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.nameInput.SetValue(filepath.Base(m.confirmPath))
 				m.nameInput.Focus() // Focus name input after creating dir
 				return m, nil
-			case "n", "N", "esc":
+			case matchKey(msg, false, keymap.Deny, keymap.Back):
 				m.state = stateInput
 				m.input.Focus() // Refocus path input
 				return m, nil
@@ -265,15 +402,15 @@ func (m vaultModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case stateDeleteConfirm:
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
-			switch msg.String() {
-			case "y", "Y":
+			switch {
+			case matchKey(msg, false, keymap.Confirm):
 				idx := m.deleteIdx
 				if idx < len(m.vaults) {
 					vault := m.vaults[idx]
 					// Remove vault config file and directory (optional: prompt for full delete)
 					cfgPath := filepath.Join(vault.Path, "vault.json")
 					os.Remove(cfgPath)
-					log.Info("Vault deleted", "path", vault.Path)
+					m.result.Deleted = append(m.result.Deleted, vault)
 					m.vaults = append(m.vaults[:idx], m.vaults[idx+1:]...)
 					items := make([]list.Item, len(m.vaults)+1)
 					for i, v := range m.vaults {
@@ -284,7 +421,7 @@ func (m vaultModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.state = stateList
 					return m, nil
 				}
-			case "n", "N", "esc":
+			case matchKey(msg, false, keymap.Deny, keymap.Back):
 				m.state = stateList
 				return m, nil
 			}
@@ -299,7 +436,9 @@ func (m vaultModel) View() string {
 	case stateList:
 		// Styled vault list
 		var items []string
-		for i, item := range m.list.Items() {
+		start, end := m.listWindow()
+		for i, item := range m.list.Items()[start:end] {
+			i += start
 			str := item.(vaultListItem).name
 			if i == m.list.Index() {
 				if str == "+ Create New Vault" {
@@ -317,7 +456,7 @@ func (m vaultModel) View() string {
 			items = append(items, str)
 		}
 		listView := headerStyle.Render("Select a Vault for Noted") + "\n" + lipgloss.JoinVertical(lipgloss.Left, items...)
-		help := helpBarStyle.Render("↑/↓: Move   Enter: Select   q: Quit")
+		help := helpBarStyle.Render(helpText(false, keyHelp("Move", keymap.Up, keymap.Down), keyHelp("Select", keymap.Select), keyHelp("Delete", keymap.Delete), keyHelp("Quit", keymap.Quit)))
 		return borderStyle.Render(listView+"\n\n"+help)
 	case stateInput:
 		prompt := headerStyle.Render(m.inputPrompt)
@@ -326,25 +465,42 @@ func (m vaultModel) View() string {
 		if m.inputError != "" {
 			errMsg = errorStyle.Render(m.inputError)
 		}
-		help := helpBarStyle.Render("[Enter] Confirm   [Esc] Cancel")
+		help := helpBarStyle.Render(helpText(true, keyHelp("Confirm", keymap.Select), keyHelp("Cancel", keymap.Back)))
 		return prompt + "\n\n" + inputBox + "\n" + errMsg + "\n" + help
 	case stateNameInput:
-		prompt := headerStyle.Render("Enter a name for your new vault (default: folder name):")
+		prompt := headerStyle.Render("Step 2 of 4: Enter a name for your new vault (default: folder name):")
 		inputBox := borderStyle.Render(m.nameInput.View())
 		errMsg := ""
 		if m.inputError != "" {
 			errMsg = errorStyle.Render(m.inputError)
 		}
-		help := helpBarStyle.Render("[Enter] Confirm   [Esc] Back")
+		help := helpBarStyle.Render(helpText(true, keyHelp("Confirm", keymap.Select), keyHelp("Back", keymap.Back)))
 		return prompt + "\n\n" + inputBox + "\n" + errMsg + "\n" + help
+	case statePresetSelect:
+		var items []string
+		for i, p := range vault.Presets {
+			line := p.Title + " — " + p.Description
+			if i == m.presetIdx {
+				items = append(items, selectedStyle.Render(line))
+			} else {
+				items = append(items, itemStyle.Render(line))
+			}
+		}
+		view := headerStyle.Render("Step 3 of 4: Choose a preset for "+m.result.Name) + "\n\n" + lipgloss.JoinVertical(lipgloss.Left, items...)
+		help := helpBarStyle.Render(helpText(false, keyHelp("Move", keymap.Up, keymap.Down), keyHelp("Select", keymap.Select), keyHelp("Back", keymap.Back)))
+		return borderStyle.Render(view + "\n\n" + help)
+	case stateReview:
+		view := headerStyle.Render("Step 4 of 4: Review") + "\n\n" + renderPresetSummary(m.result.Name, m.result.Path, vault.Presets[m.presetIdx])
+		help := helpBarStyle.Render(helpText(false, keyHelp("Create vault", keymap.Select), keyHelp("Back", keymap.Back)))
+		return borderStyle.Render(view + "\n\n" + help)
 	case stateConfirmCreate:
-		modal := modalStyle.Render("Vault directory does not exist.\nCreate it? [y/N]")
+		modal := modalStyle.Render("Vault directory does not exist.\nCreate it? " + confirmPrompt())
 		return "\n" + modal
 	case stateDirPicker:
-		return m.dirPicker.View()
+		return headerStyle.Render("Step 1 of 4: Choose the vault directory") + "\n" + m.dirPicker.View()
 	case stateDeleteConfirm:
 		vault := m.vaults[m.deleteIdx]
-		modal := modalStyle.Render(fmt.Sprintf("Delete vault '%s'? [y/N]", vault.Name))
+		modal := modalStyle.Render(fmt.Sprintf("Delete vault '%s'? %s", vault.Name, confirmPrompt()))
 		return "\n" + modal
 	case stateDone:
 		if m.result.Err != nil {
@@ -353,7 +509,7 @@ func (m vaultModel) View() string {
 		if m.result.Cancelled {
 			return errorStyle.Render("Cancelled.")
 		}
-		msg := successStyle.Render("✓ Vault set!") + "\n" + headerStyle.Render("Current vault: "+m.result.Name) + "\n" + itemStyle.Render(m.result.Path) + "\n" + helpBarStyle.Render("[Enter] Continue")
+		msg := successStyle.Render("✓ Vault set!") + "\n" + headerStyle.Render("Current vault: "+m.result.Name) + "\n" + itemStyle.Render(m.result.Path) + "\n" + helpBarStyle.Render(helpText(false, keyHelp("Continue", keymap.Select)))
 		return borderStyle.Render(msg)
 	}
 	return ""
@@ -378,16 +534,43 @@ func renderVaultDetails(v models.Vault) string {
 	)
 }
 
-// Helper to write VaultConfig to disk
-func writeVaultConfig(path string, cfg models.VaultConfig) error {
-	cfgPath := filepath.Join(path, "vault.json")
-	f, err := os.Create(cfgPath)
-	if err != nil {
-		return err
+// renderPresetSummary lists what creating a vault from preset p will set up
+func renderPresetSummary(name, path string, p vault.Preset) string {
+	cfg := p.Config(path, name)
+	obsidian := ""
+	if vault.HasObsidian(path) {
+		if oc, err := vault.ReadObsidian(path); err == nil {
+			obsidian = strings.Join(oc.Apply(path, &cfg), "; ")
+		}
+	}
+	folders := "(none)"
+	if len(p.Folders) > 0 {
+		folders = strings.Join(p.Folders, ", ")
+	}
+	var templates []string
+	for file := range p.Templates {
+		templates = append(templates, file)
+	}
+	sort.Strings(templates)
+	templateList := "(none)"
+	if len(templates) > 0 {
+		templateList = strings.Join(templates, ", ")
+	}
+	return itemStyle.Render("Name: ") + name + "\n" +
+		itemStyle.Render("Path: ") + path + "\n" +
+		itemStyle.Render("Preset: ") + p.Title + "\n" +
+		itemStyle.Render("Folders: ") + folders + "\n" +
+		itemStyle.Render("Templates: ") + templateList + "\n" +
+		itemStyle.Render("Supported Types: ") + fmt.Sprintf("%v", cfg.SupportedTypes) + "\n" +
+		itemStyle.Render("Ignore Patterns: ") + fmt.Sprintf("%v", cfg.IgnorePatterns) + "\n" +
+		itemStyle.Render("Settings: ") + fmt.Sprintf("%v", cfg.Settings) +
+		renderObsidianImport(obsidian)
+}
+
+// renderObsidianImport notes the settings taken over from an existing Obsidian vault
+func renderObsidianImport(summary string) string {
+	if summary == "" {
+		return ""
 	}
-	defer f.Close()
-	// Use encoding/json to marshal config
-	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
-	return enc.Encode(cfg)
-} 
\ No newline at end of file
+	return "\n" + itemStyle.Render("From Obsidian: ") + summary
+}
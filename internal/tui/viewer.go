@@ -0,0 +1,340 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	textinput "github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// viewerHeight is how many notes the viewer shows at once until it knows the terminal size.
+const viewerHeight = 15
+
+// viewerRowsTop is the screen line of the first note shown, below the border, the header
+// and the filter.
+const viewerRowsTop = 5
+
+// ViewerNote is a note listed in the vault viewer.
+type ViewerNote struct {
+	Rel    string // Vault-relative path
+	Detail string // Shown next to the path, e.g. when the note was last opened
+}
+
+// VaultViewerResult holds the note chosen in the vault viewer, or the action run from
+// its command palette.
+type VaultViewerResult struct {
+	Note      string
+	Action    string // ID of the palette action to run
+	ActionArg string // Argument typed for the action, if it asked for one
+	Cancelled bool
+}
+
+// VaultViewerOptions describe what the vault viewer lists.
+type VaultViewerOptions struct {
+	Title   string          // Shown above the list, e.g. the vault name
+	Recent  []ViewerNote    // Listed first while there is no filter
+	Notes   []ViewerNote    // Everything that can be picked
+	Actions []PaletteAction // Offered by the command palette
+	// Preview loads a note for the preview pane shown next to the list on wide
+	// terminals. Without it there is no preview.
+	Preview func(rel string) (string, error)
+}
+
+// LaunchVaultViewer lists notes, recent notes first, and returns the one the user picks.
+// Typing filters the list; the command palette offers actions.
+func LaunchVaultViewer(opts VaultViewerOptions) (VaultViewerResult, error) {
+	p := tea.NewProgram(newViewerModel(opts), programOptions()...)
+	finalModel, err := p.Run()
+	if err != nil {
+		return VaultViewerResult{}, err
+	}
+	return finalModel.(viewerModel).result, nil
+}
+
+// viewerRow is a selectable note or a section heading.
+type viewerRow struct {
+	heading string
+	note    ViewerNote
+}
+
+type viewerModel struct {
+	title     string
+	recent    []ViewerNote
+	notes     []ViewerNote
+	filter    textinput.Model
+	rows      []viewerRow
+	cursor    int // Index into rows, always on a note
+	offset    int // First row shown
+	palette   paletteModel
+	preview   previewModel
+	width     int // Terminal size, zero until known
+	height    int
+	rowsShown int // How many rows fit in the list
+	result    VaultViewerResult
+}
+
+func newViewerModel(opts VaultViewerOptions) viewerModel {
+	ti := textinput.New()
+	ti.Placeholder = "Type to filter notes"
+	ti.CharLimit = 256
+	ti.Width = 48
+	ti.Focus()
+	m := viewerModel{
+		title:     opts.Title,
+		recent:    opts.Recent,
+		notes:     opts.Notes,
+		filter:    ti,
+		palette:   newPaletteModel(opts.Actions),
+		preview:   newPreviewModel(opts.Preview),
+		rowsShown: viewerHeight,
+	}
+	m.refresh()
+	return m
+}
+
+// showPreview reports whether the preview pane fits next to the list.
+func (m viewerModel) showPreview() bool {
+	return m.preview.enabled() && m.width >= previewMinWidth
+}
+
+// listWidth is the width of the list pane, borders included, when the preview is shown.
+func (m viewerModel) listWidth() int {
+	w := m.width * 2 / 5
+	if w < 40 {
+		w = 40
+	}
+	if w > 70 {
+		w = 70
+	}
+	return w
+}
+
+// resize lays the panes out for a terminal of the given size.
+func (m *viewerModel) resize(width, height int) {
+	m.width, m.height = width, height
+	// Border, padding, header, filter, help bar and the blank lines between them
+	m.rowsShown = height - 10 - lipgloss.Height(m.helpBar())
+	if m.rowsShown < 3 {
+		m.rowsShown = 3
+	}
+	if m.showPreview() {
+		m.preview.setSize(m.width-m.listWidth(), m.height-1)
+		m.filter.Width = m.listWidth() - 10
+	}
+	m.palette.setHeight(m.rowsShown)
+	m.move(0)
+}
+
+// current returns the note under the cursor, if any.
+func (m viewerModel) current() string {
+	if m.cursor < len(m.rows) && m.rows[m.cursor].heading == "" {
+		return m.rows[m.cursor].note.Rel
+	}
+	return ""
+}
+
+// refresh rebuilds the rows for the current filter.
+func (m *viewerModel) refresh() {
+	query := strings.ToLower(m.filter.Value())
+	m.rows = nil
+	if query == "" {
+		if len(m.recent) > 0 {
+			m.rows = append(m.rows, viewerRow{heading: "Recent notes"})
+			for _, n := range m.recent {
+				m.rows = append(m.rows, viewerRow{note: n})
+			}
+			m.rows = append(m.rows, viewerRow{heading: "All notes"})
+		}
+		for _, n := range m.notes {
+			m.rows = append(m.rows, viewerRow{note: n})
+		}
+	} else {
+		for _, n := range m.notes {
+			if strings.Contains(strings.ToLower(n.Rel), query) {
+				m.rows = append(m.rows, viewerRow{note: n})
+			}
+		}
+	}
+	m.cursor, m.offset = 0, 0
+	m.move(0)
+}
+
+// move shifts the cursor by delta notes, skipping headings, and keeps it in view.
+func (m *viewerModel) move(delta int) {
+	step := 1
+	if delta < 0 {
+		step = -1
+	}
+	next := m.cursor + delta
+	for next >= 0 && next < len(m.rows) && m.rows[next].heading != "" {
+		next += step
+	}
+	if next < 0 || next >= len(m.rows) {
+		if delta != 0 {
+			return
+		}
+		next = m.cursor
+	}
+	m.cursor = next
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+		// Keep the heading above the first note in view
+		if m.offset > 0 && m.rows[m.offset-1].heading != "" {
+			m.offset--
+		}
+	}
+	if m.cursor >= m.offset+m.rowsShown {
+		m.offset = m.cursor - m.rowsShown + 1
+	}
+	if m.showPreview() {
+		m.preview.show(m.current())
+	}
+}
+
+func (m viewerModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m viewerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.resize(msg.Width, msg.Height)
+		return m, nil
+	}
+	if m.palette.open {
+		var cmd tea.Cmd
+		m.palette, cmd = m.palette.Update(msg)
+		if a := m.palette.chosen; a != nil {
+			m.result.Action = a.ID
+			m.result.ActionArg = m.palette.argValue
+			return m, tea.Quit
+		}
+		if !m.palette.open {
+			return m, m.filter.Focus()
+		}
+		return m, cmd
+	}
+	if msg, ok := msg.(tea.MouseMsg); ok {
+		return m.updateMouse(msg)
+	}
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case len(m.palette.actions) > 0 && matchKey(msg, true, keymap.Palette):
+			m.filter.Blur()
+			return m, m.palette.show()
+		case matchKey(msg, true, keymap.Back, keymap.Quit):
+			m.result.Cancelled = true
+			return m, tea.Quit
+		case matchKey(msg, true, keymap.Select):
+			if note := m.current(); note != "" {
+				m.result.Note = note
+				return m, tea.Quit
+			}
+			return m, nil
+		case m.showPreview() && matchKey(msg, true, keymap.PreviewUp):
+			m.preview.viewport.HalfPageUp()
+			return m, nil
+		case m.showPreview() && matchKey(msg, true, keymap.PreviewDown):
+			m.preview.viewport.HalfPageDown()
+			return m, nil
+		case matchKey(msg, true, keymap.Up):
+			m.move(-1)
+			return m, nil
+		case matchKey(msg, true, keymap.Down):
+			m.move(1)
+			return m, nil
+		}
+	}
+	before := m.filter.Value()
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	if m.filter.Value() != before {
+		m.refresh()
+	}
+	return m, cmd
+}
+
+// updateMouse scrolls the list or the preview under the wheel. Clicking a note
+// highlights it and clicking it again opens it.
+func (m viewerModel) updateMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	overPreview := m.showPreview() && msg.X >= m.listWidth()
+	switch delta := wheelDelta(msg); {
+	case delta != 0 && overPreview:
+		if delta < 0 {
+			m.preview.viewport.ScrollUp(3)
+		} else {
+			m.preview.viewport.ScrollDown(3)
+		}
+	case delta != 0:
+		m.move(delta)
+	case !overPreview:
+		i := clickedRow(msg, viewerRowsTop, m.offset, m.rowsShown, len(m.rows))
+		if i < 0 || m.rows[i].heading != "" {
+			return m, nil
+		}
+		if i == m.cursor {
+			m.result.Note = m.current()
+			return m, tea.Quit
+		}
+		m.move(i - m.cursor)
+	}
+	return m, nil
+}
+
+func (m viewerModel) View() string {
+	if m.palette.open {
+		return borderStyle.Render(m.palette.View())
+	}
+	var lines []string
+	end := m.offset + m.rowsShown
+	if end > len(m.rows) {
+		end = len(m.rows)
+	}
+	for i := m.offset; i < end; i++ {
+		row := m.rows[i]
+		if row.heading != "" {
+			lines = append(lines, viewerSectionStyle.Render(row.heading))
+			continue
+		}
+		var line string
+		if i == m.cursor {
+			line = selectedStyle.Render(" "+row.note.Rel+" ") + detailSuffix(row.note)
+		} else {
+			line = itemStyle.Render(row.note.Rel) + detailSuffix(row.note)
+		}
+		if m.showPreview() {
+			line = lipgloss.NewStyle().MaxWidth(m.listWidth() - 6).Render(line)
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, helpBarStyle.Render("No matching notes."))
+	}
+	count := fmt.Sprintf("%d notes", len(m.notes))
+	header := headerStyle.Render("📂 "+m.title) + helpBarStyle.Render(count)
+	list := header + "\n" + m.filter.View() + "\n\n" + strings.Join(lines, "\n") + "\n\n" + m.helpBar()
+	if !m.showPreview() {
+		return borderStyle.Render(list)
+	}
+	left := borderStyle.Width(m.listWidth() - 2).Height(m.height - 3).Render(list)
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, m.preview.View())
+}
+
+// helpBar renders the key help, wrapped to the list pane when the preview is shown.
+func (m viewerModel) helpBar() string {
+	entries := []helpEntry{keyHelp("Move", keymap.Up, keymap.Down), keyHelp("Open", keymap.Select)}
+	if !m.showPreview() {
+		entries = append(entries, keyHelp("Commands", keymap.Palette), keyHelp("Quit", keymap.Back, keymap.Quit))
+		return helpBarStyle.Render(helpText(true, entries...))
+	}
+	entries = append(entries, keyHelp("Scroll", keymap.PreviewUp, keymap.PreviewDown), keyHelp("Commands", keymap.Palette), keyHelp("Quit", keymap.Back, keymap.Quit))
+	return helpBarStyle.Width(m.listWidth() - 6).Render(helpText(true, entries...))
+}
+
+func detailSuffix(n ViewerNote) string {
+	if n.Detail == "" {
+		return ""
+	}
+	return "  " + helpBarStyle.Render(n.Detail)
+}
@@ -0,0 +1,143 @@
+package vault
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"cobra-cli/internal/history"
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/notes"
+)
+
+// DayActivity is the writing activity of a single day.
+type DayActivity struct {
+	Date  time.Time `json:"date"`
+	Notes int       `json:"notes"` // Distinct notes created or edited
+	Words int       `json:"words"` // Words written
+}
+
+// Activity is the writing activity of a vault, one entry per active day.
+type Activity struct {
+	Vault         string        `json:"vault"`
+	Days          []DayActivity `json:"days"` // Oldest first
+	CurrentStreak int           `json:"current_streak"`
+	LongestStreak int           `json:"longest_streak"`
+	TotalWords    int           `json:"total_words"`
+	ActiveDays    int           `json:"active_days"`
+	byDate        map[string]*DayActivity
+}
+
+// CollectActivity combines the vault's history log with the modification times of its notes.
+// Days recorded in the history log use its word counts. A note edited outside noted only
+// leaves its last modification time, so its whole word count is credited to that day.
+func CollectActivity(v models.Vault, now time.Time) (Activity, error) {
+	a := Activity{Vault: v.Name, byDate: map[string]*DayActivity{}}
+	touched := map[string]map[string]bool{} // date → notes
+	touch := func(t time.Time, note string, words int) {
+		day := dayKey(t)
+		d := a.byDate[day]
+		if d == nil {
+			d = &DayActivity{Date: startOfDay(t)}
+			a.byDate[day] = d
+			touched[day] = map[string]bool{}
+		}
+		if !touched[day][note] {
+			touched[day][note] = true
+			d.Notes++
+		}
+		if words > 0 {
+			d.Words += words
+		}
+	}
+
+	if v.Config.HistoryPath != "" {
+		entries, err := history.Read(v.Config.HistoryPath)
+		if err != nil {
+			return a, err
+		}
+		for _, e := range entries {
+			if e.Action == history.ActionCreate || e.Action == history.ActionEdit {
+				touch(e.Time, e.Note, e.Words)
+			}
+		}
+	}
+
+	err := Walk(v.Path, v.Config, func(e Entry) error {
+		if e.IsDir {
+			return nil
+		}
+		mod := e.Info.ModTime()
+		if touched[dayKey(mod)][e.Rel] {
+			return nil
+		}
+		words := 0
+		if IsText(e.Rel) {
+			data, err := os.ReadFile(e.Path)
+			if err != nil {
+				return err
+			}
+			words = notes.CountWords(string(data))
+		}
+		touch(mod, e.Rel, words)
+		return nil
+	})
+	if err != nil {
+		return a, err
+	}
+
+	for _, d := range a.byDate {
+		a.Days = append(a.Days, *d)
+		a.TotalWords += d.Words
+	}
+	sort.Slice(a.Days, func(i, j int) bool { return a.Days[i].Date.Before(a.Days[j].Date) })
+	a.ActiveDays = len(a.Days)
+	a.CurrentStreak, a.LongestStreak = a.streaks(now)
+	return a, nil
+}
+
+// Day returns the activity on the day of t.
+func (a Activity) Day(t time.Time) DayActivity {
+	if d, ok := a.byDate[dayKey(t)]; ok {
+		return *d
+	}
+	return DayActivity{Date: startOfDay(t)}
+}
+
+// streaks counts consecutive active days. The current streak still counts if today has
+// no activity yet but yesterday did.
+func (a Activity) streaks(now time.Time) (current, longest int) {
+	run := 0
+	var prev time.Time
+	for _, d := range a.Days {
+		if run > 0 && startOfDay(prev.AddDate(0, 0, 1)).Equal(d.Date) {
+			run++
+		} else {
+			run = 1
+		}
+		prev = d.Date
+		if run > longest {
+			longest = run
+		}
+	}
+	day := startOfDay(now)
+	if _, ok := a.byDate[dayKey(day)]; !ok {
+		day = day.AddDate(0, 0, -1)
+	}
+	for {
+		if _, ok := a.byDate[dayKey(day)]; !ok {
+			return current, longest
+		}
+		current++
+		day = day.AddDate(0, 0, -1)
+	}
+}
+
+func dayKey(t time.Time) string {
+	return t.Local().Format("2006-01-02")
+}
+
+func startOfDay(t time.Time) time.Time {
+	t = t.Local()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,123 @@
+package vault
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"cobra-cli/internal/history"
+	"cobra-cli/internal/models"
+)
+
+// inZone runs the test with time.Local set to a fixed zone, so days split at its midnight
+func inZone(t *testing.T, offset time.Duration) *time.Location {
+	t.Helper()
+	saved := time.Local
+	time.Local = time.FixedZone("test", int(offset.Seconds()))
+	t.Cleanup(func() { time.Local = saved })
+	return time.Local
+}
+
+func TestCollectActivity(t *testing.T) {
+	loc := inZone(t, -5*time.Hour)
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2025, 3, day, hour, min, 0, 0, loc)
+	}
+	dir := t.TempDir()
+	v := models.Vault{Name: "test", Path: dir, Config: DefaultConfig(dir, "test")}
+	for _, e := range []history.Entry{
+		{Time: at(5, 9, 0), Action: history.ActionOpen, Note: "a.md"},
+		{Time: at(8, 23, 59), Action: history.ActionCreate, Note: "a.md", Words: 10},
+		{Time: at(9, 0, 1), Action: history.ActionEdit, Note: "a.md", Words: 5},
+		{Time: at(9, 12, 0), Action: history.ActionEdit, Note: "b.md", Words: 3},
+		{Time: at(9, 13, 0), Action: history.ActionEdit, Note: "a.md", Words: -2},
+		// 02:00 UTC on the 10th is still the 9th here
+		{Time: time.Date(2025, 3, 10, 2, 0, 0, 0, time.UTC), Action: history.ActionCreate, Note: "c.md"},
+		{Time: time.Date(2025, 3, 11, 4, 30, 0, 0, time.UTC), Action: history.ActionEdit, Note: "c.md", Words: 4},
+	} {
+		if err := history.Append(v.Config.HistoryPath, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	files := []struct {
+		rel     string
+		content string
+		mod     time.Time
+	}{
+		{"a.md", "already in the history", at(9, 10, 0)},
+		{"b.md", "also in the history", at(9, 12, 0)},
+		{"c.md", "edited last", at(10, 23, 30)},
+		{"d.md", "written outside noted", at(1, 12, 0)},
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.rel)
+		if err := os.WriteFile(path, []byte(f.content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, f.mod, f.mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	a, err := CollectActivity(v, at(11, 12, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var days []string
+	for _, d := range a.Days {
+		days = append(days, fmt.Sprintf("%s notes=%d words=%d", d.Date.Format("01-02 15:04"), d.Notes, d.Words))
+	}
+	want := []string{
+		"03-01 00:00 notes=1 words=3",
+		"03-08 00:00 notes=1 words=10",
+		"03-09 00:00 notes=3 words=8",
+		"03-10 00:00 notes=1 words=4",
+	}
+	if !reflect.DeepEqual(days, want) {
+		t.Errorf("Days = %q, want %q", days, want)
+	}
+	if a.ActiveDays != 4 || a.TotalWords != 25 {
+		t.Errorf("ActiveDays, TotalWords = %d, %d, want 4, 25", a.ActiveDays, a.TotalWords)
+	}
+	if d := a.Day(time.Date(2025, 3, 10, 1, 0, 0, 0, time.UTC)); d.Notes != 3 {
+		t.Errorf("Day(01:00 UTC on the 10th) = %+v, want the 9th local time", d)
+	}
+
+	tests := []struct {
+		name             string
+		now              time.Time
+		current, longest int
+	}{
+		{name: "active today", now: at(10, 23, 59), current: 3, longest: 3},
+		{name: "not yet today", now: at(11, 0, 0), current: 3, longest: 3},
+		{name: "broken streak", now: at(12, 8, 0), current: 0, longest: 3},
+		{name: "before the first day", now: at(1, 0, 0), current: 1, longest: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current, longest := a.streaks(tt.now)
+			if current != tt.current || longest != tt.longest {
+				t.Errorf("streaks(%v) = %d, %d, want %d, %d", tt.now, current, longest, tt.current, tt.longest)
+			}
+		})
+	}
+}
+
+func TestCollectActivityEmptyVault(t *testing.T) {
+	dir := t.TempDir()
+	v := models.Vault{Name: "test", Path: dir, Config: DefaultConfig(dir, "test")}
+	now := time.Now()
+	a, err := CollectActivity(v, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a.Days) != 0 || a.ActiveDays != 0 || a.TotalWords != 0 || a.CurrentStreak != 0 || a.LongestStreak != 0 {
+		t.Errorf("CollectActivity() of an empty vault = %+v", a)
+	}
+	if d := a.Day(now); d.Notes != 0 || !d.Date.Equal(startOfDay(now)) {
+		t.Errorf("Day(now) = %+v, want no activity", d)
+	}
+}
@@ -0,0 +1,44 @@
+package vault
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+
+	"cobra-cli/internal/models"
+)
+
+// Export copies every note of v into dest, keeping the folder layout, and returns how many were copied.
+func Export(v models.Vault, dest string) (int, error) {
+	notes, err := Notes(v.Path, v.Config)
+	if err != nil {
+		return 0, err
+	}
+	for i, n := range notes {
+		target := filepath.Join(dest, filepath.FromSlash(n.Rel))
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return i, err
+		}
+		if err := copyNote(n.Path, target); err != nil {
+			return i, err
+		}
+	}
+	return len(notes), nil
+}
+
+func copyNote(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
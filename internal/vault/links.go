@@ -0,0 +1,179 @@
+package vault
+
+import (
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/notes"
+)
+
+// Index maps note names and paths of one vault to their vault-relative paths.
+type Index struct {
+	byPath map[string]string   // lower-cased rel path without extension
+	byName map[string][]string // lower-cased base name without extension
+	notes  []Entry
+}
+
+// BuildIndex indexes the notes of v for link resolution.
+func BuildIndex(v models.Vault) (*Index, error) {
+	entries, err := Notes(v.Path, v.Config)
+	if err != nil {
+		return nil, err
+	}
+	ix := &Index{byPath: map[string]string{}, byName: map[string][]string{}, notes: entries}
+	for _, e := range entries {
+		key := strings.ToLower(trimExt(e.Rel))
+		ix.byPath[key] = e.Rel
+		name := path.Base(key)
+		ix.byName[name] = append(ix.byName[name], e.Rel)
+	}
+	return ix, nil
+}
+
+// Notes returns the indexed notes.
+func (ix *Index) Notes() []Entry {
+	return ix.notes
+}
+
+// Resolve finds the note a link target refers to: an exact vault-relative path first,
+// then a unique note name, the way Obsidian resolves shortest-path links.
+func (ix *Index) Resolve(target string) (string, bool) {
+	key := strings.ToLower(strings.Trim(trimExt(target), "/"))
+	if rel, ok := ix.byPath[key]; ok {
+		return rel, true
+	}
+	if rels := ix.byName[path.Base(key)]; len(rels) == 1 {
+		return rels[0], true
+	}
+	return "", false
+}
+
+func trimExt(p string) string {
+	return strings.TrimSuffix(p, path.Ext(p))
+}
+
+// Registry resolves vault names to vaults and caches their link indexes.
+type Registry struct {
+	vaults  []models.Vault
+	indexes map[string]*Index
+
+	// OnIndex, if set, is called after an index is built, with the number of notes it holds.
+	OnIndex func(v models.Vault, notes int, took time.Duration)
+}
+
+// NewRegistry builds a registry from vaults whose configs are already loaded.
+func NewRegistry(vaults []models.Vault) *Registry {
+	return &Registry{vaults: vaults, indexes: map[string]*Index{}}
+}
+
+// Vaults returns the registered vaults.
+func (r *Registry) Vaults() []models.Vault {
+	return r.vaults
+}
+
+// Vault looks a vault up by name, case-insensitively.
+func (r *Registry) Vault(name string) (models.Vault, bool) {
+	return models.FindVault(r.vaults, name)
+}
+
+// Index returns the cached link index of v, building it on first use.
+func (r *Registry) Index(v models.Vault) (*Index, error) {
+	if ix, ok := r.indexes[v.Path]; ok {
+		return ix, nil
+	}
+	start := time.Now()
+	ix, err := BuildIndex(v)
+	if err != nil {
+		return nil, err
+	}
+	r.indexes[v.Path] = ix
+	if r.OnIndex != nil {
+		r.OnIndex(v, len(ix.notes), time.Since(start))
+	}
+	return ix, nil
+}
+
+// Ref is a link from one note to another, possibly in a different vault.
+type Ref struct {
+	FromVault string
+	FromRel   string
+	Link      notes.Link
+	ToVault   string // Vault the link points into
+	ToRel     string // Resolved target, empty when the link is broken
+}
+
+// Resolved reports whether the link target exists.
+func (r Ref) Resolved() bool {
+	return r.ToRel != ""
+}
+
+// Links returns the links of every markdown note in v. Local links resolve against v,
+// cross-vault links against the registry.
+func (r *Registry) Links(v models.Vault) ([]Ref, error) {
+	ix, err := r.Index(v)
+	if err != nil {
+		return nil, err
+	}
+	var refs []Ref
+	for _, e := range ix.Notes() {
+		noteRefs, err := r.NoteLinks(v, e)
+		if err != nil {
+			return nil, err
+		}
+		refs = append(refs, noteRefs...)
+	}
+	return refs, nil
+}
+
+// NoteLinks returns the resolved links of a single note.
+func (r *Registry) NoteLinks(v models.Vault, e Entry) ([]Ref, error) {
+	if !strings.EqualFold(path.Ext(e.Rel), ".md") {
+		return nil, nil
+	}
+	data, err := os.ReadFile(e.Path)
+	if err != nil {
+		return nil, err
+	}
+	var refs []Ref
+	for _, l := range notes.ParseLinks(string(data)) {
+		ref := Ref{FromVault: v.Name, FromRel: e.Rel, Link: l, ToVault: v.Name}
+		target := v
+		if l.CrossVault() {
+			ref.ToVault = l.Vault
+			tv, ok := r.Vault(l.Vault)
+			if !ok {
+				refs = append(refs, ref)
+				continue
+			}
+			ref.ToVault = tv.Name
+			target = tv
+		}
+		ix, err := r.Index(target)
+		if err != nil {
+			return nil, err
+		}
+		ref.ToRel, _ = ix.Resolve(l.Target)
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// Backlinks returns every link, from any registered vault, that resolves to rel in v.
+func (r *Registry) Backlinks(v models.Vault, rel string) ([]Ref, error) {
+	var backlinks []Ref
+	for _, source := range r.vaults {
+		refs, err := r.Links(source)
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			if ref.ToVault == v.Name && ref.ToRel == rel {
+				backlinks = append(backlinks, ref)
+			}
+		}
+	}
+	return backlinks, nil
+}
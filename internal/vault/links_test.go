@@ -0,0 +1,139 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"cobra-cli/internal/models"
+)
+
+// testVaults creates the vaults work and home, filled with the files listed under their names
+func testVaults(t *testing.T, files map[string]map[string]string) []models.Vault {
+	t.Helper()
+	var vaults []models.Vault
+	for _, name := range []string{"work", "home"} {
+		dir := t.TempDir()
+		for rel, content := range files[name] {
+			path := filepath.Join(dir, filepath.FromSlash(rel))
+			if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		vaults = append(vaults, models.Vault{Name: name, Path: dir, Config: DefaultConfig(dir, name)})
+	}
+	return vaults
+}
+
+func refTargets(refs []Ref) []string {
+	var got []string
+	for _, ref := range refs {
+		got = append(got, ref.FromVault+":"+ref.FromRel+" -> "+ref.ToVault+":"+ref.ToRel)
+	}
+	return got
+}
+
+func TestRegistryLinks(t *testing.T) {
+	vaults := testVaults(t, map[string]map[string]string{
+		"work": {
+			"index.md": "[[plan]] [[projects/plan.md]] [[vault:Home/journal/today#Morning|today]]\n" +
+				"[[vault:home/missing]] [[vault:nowhere/x]] [[notes]] [[a/notes]]\n" +
+				"```\n[[vault:home/journal/today]]\n```\n",
+			"projects/plan.md": "# Plan\n",
+			"a/notes.md":       "",
+			"b/notes.md":       "",
+			"scan.pdf":         "[[plan]]",
+		},
+		"home": {
+			"journal/today.md": "[[vault:WORK/plan]] and [[plan]]\n",
+		},
+	})
+	work, home := vaults[0], vaults[1]
+	indexed := map[string]int{}
+	r := NewRegistry(vaults)
+	r.OnIndex = func(v models.Vault, notes int, took time.Duration) { indexed[v.Name]++ }
+
+	if v, ok := r.Vault("HOME"); !ok || v.Path != home.Path {
+		t.Errorf("Vault(HOME) = %v, %v, want the home vault", v, ok)
+	}
+	if _, ok := r.Vault("nowhere"); ok {
+		t.Error("Vault(nowhere) found a vault")
+	}
+
+	refs, err := r.Links(work)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"work:index.md -> work:projects/plan.md",
+		"work:index.md -> work:projects/plan.md",
+		"work:index.md -> home:journal/today.md",
+		"work:index.md -> home:",
+		"work:index.md -> nowhere:",
+		"work:index.md -> work:",
+		"work:index.md -> work:a/notes.md",
+	}
+	if got := refTargets(refs); !reflect.DeepEqual(got, want) {
+		t.Errorf("Links(work) =\n%q\nwant\n%q", got, want)
+	}
+	if refs[2].Link.Heading != "Morning" || refs[2].Link.Alias != "today" || refs[4].Resolved() {
+		t.Errorf("refs = %+v", refs)
+	}
+
+	// A local link in home does not resolve into work
+	refs, err = r.Links(home)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = []string{"home:journal/today.md -> work:projects/plan.md", "home:journal/today.md -> home:"}
+	if got := refTargets(refs); !reflect.DeepEqual(got, want) {
+		t.Errorf("Links(home) = %q, want %q", got, want)
+	}
+
+	if want := map[string]int{"work": 1, "home": 1}; !reflect.DeepEqual(indexed, want) {
+		t.Errorf("indexes built = %v, want each vault indexed once", indexed)
+	}
+}
+
+func TestRegistryBacklinks(t *testing.T) {
+	vaults := testVaults(t, map[string]map[string]string{
+		"work": {
+			"index.md":         "[[plan]] [[vault:home/journal/today]]\n",
+			"projects/plan.md": "[[index]]\n",
+			"other/plan.md":    "",
+		},
+		"home": {
+			"journal/today.md": "[[vault:work/projects/plan]] [[vault:work/other/plan]]\n",
+			"plan.md":          "",
+		},
+	})
+	work, home := vaults[0], vaults[1]
+	r := NewRegistry(vaults)
+	tests := []struct {
+		v    models.Vault
+		rel  string
+		want []string
+	}{
+		{v: work, rel: "projects/plan.md", want: []string{"home:journal/today.md -> work:projects/plan.md"}},
+		{v: work, rel: "other/plan.md", want: []string{"home:journal/today.md -> work:other/plan.md"}},
+		{v: work, rel: "index.md", want: []string{"work:projects/plan.md -> work:index.md"}},
+		{v: home, rel: "journal/today.md", want: []string{"work:index.md -> home:journal/today.md"}},
+		{v: home, rel: "plan.md"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.v.Name+"/"+tt.rel, func(t *testing.T) {
+			refs, err := r.Backlinks(tt.v, tt.rel)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := refTargets(refs); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Backlinks() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
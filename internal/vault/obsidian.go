@@ -0,0 +1,234 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cobra-cli/internal/models"
+)
+
+// ObsidianDir is the folder in which Obsidian keeps a vault's configuration.
+const ObsidianDir = ".obsidian"
+
+// ObsidianConfig holds the parts of an Obsidian vault's configuration noted understands.
+// Folder paths are relative to the vault root, with forward slashes.
+type ObsidianConfig struct {
+	AttachmentFolder string   // app.json attachmentFolderPath
+	IgnoreFilters    []string // app.json userIgnoreFilters
+	TemplatesFolder  string   // templates.json folder
+	DailyFolder      string   // daily-notes.json folder
+	DailyFormat      string   // daily-notes.json format, a moment.js date format
+	DailyTemplate    string   // daily-notes.json template, without the .md extension
+}
+
+// HasObsidian reports whether dir is an Obsidian vault.
+func HasObsidian(dir string) bool {
+	info, err := os.Stat(filepath.Join(dir, ObsidianDir))
+	return err == nil && info.IsDir()
+}
+
+// ReadObsidian reads app.json, templates.json and daily-notes.json from dir/.obsidian.
+// Files Obsidian has not written yet are skipped.
+func ReadObsidian(dir string) (ObsidianConfig, error) {
+	var oc ObsidianConfig
+
+	var app struct {
+		AttachmentFolderPath string   `json:"attachmentFolderPath"`
+		UserIgnoreFilters    []string `json:"userIgnoreFilters"`
+	}
+	if err := readObsidianFile(dir, "app.json", &app); err != nil {
+		return oc, err
+	}
+	oc.AttachmentFolder = obsidianFolder(app.AttachmentFolderPath)
+	oc.IgnoreFilters = app.UserIgnoreFilters
+
+	var templates struct {
+		Folder string `json:"folder"`
+	}
+	if err := readObsidianFile(dir, "templates.json", &templates); err != nil {
+		return oc, err
+	}
+	oc.TemplatesFolder = obsidianFolder(templates.Folder)
+
+	var daily struct {
+		Folder   string `json:"folder"`
+		Format   string `json:"format"`
+		Template string `json:"template"`
+	}
+	if err := readObsidianFile(dir, "daily-notes.json", &daily); err != nil {
+		return oc, err
+	}
+	oc.DailyFolder = obsidianFolder(daily.Folder)
+	oc.DailyFormat = daily.Format
+	oc.DailyTemplate = obsidianFolder(daily.Template)
+	return oc, nil
+}
+
+func readObsidianFile(dir, name string, v any) error {
+	data, err := os.ReadFile(filepath.Join(dir, ObsidianDir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("parse %s/%s: %w", ObsidianDir, name, err)
+	}
+	return nil
+}
+
+// obsidianFolder normalizes an Obsidian folder setting to a clean vault-relative path.
+// Obsidian uses "/" or "./" for the vault root and "./x" for folders relative to the current note.
+func obsidianFolder(p string) string {
+	p = strings.TrimSpace(p)
+	if p == "" || p == "/" || p == "./" || p == "." {
+		return ""
+	}
+	return strings.TrimPrefix(path.Clean("/"+p), "/")
+}
+
+// Apply maps the Obsidian settings onto cfg for a vault at dir and returns one line per
+// setting it changed. .obsidian is always added to the ignore patterns.
+func (oc ObsidianConfig) Apply(dir string, cfg *models.VaultConfig) []string {
+	var changes []string
+	if cfg.Settings == nil {
+		cfg.Settings = map[string]any{}
+	}
+	if cfg.Metadata == nil {
+		cfg.Metadata = map[string]string{}
+	}
+
+	ignore := []string{ObsidianDir}
+	for _, filter := range oc.IgnoreFilters {
+		// Filters wrapped in slashes are regular expressions, which ignore patterns cannot express
+		if len(filter) > 1 && strings.HasPrefix(filter, "/") && strings.HasSuffix(filter, "/") {
+			changes = append(changes, fmt.Sprintf("skipped regular expression filter %s", filter))
+			continue
+		}
+		if pattern := strings.Trim(filter, "/"); pattern != "" {
+			ignore = append(ignore, pattern)
+		}
+	}
+	for _, pattern := range ignore {
+		if !containsString(cfg.IgnorePatterns, pattern) {
+			cfg.IgnorePatterns = append(cfg.IgnorePatterns, pattern)
+			changes = append(changes, fmt.Sprintf("ignoring %s", pattern))
+		}
+	}
+
+	if oc.TemplatesFolder != "" {
+		cfg.TemplatesPath = filepath.Join(dir, filepath.FromSlash(oc.TemplatesFolder))
+		changes = append(changes, fmt.Sprintf("templates from %s", oc.TemplatesFolder))
+	}
+	if oc.AttachmentFolder != "" {
+		cfg.Settings["attachment_folder"] = oc.AttachmentFolder
+		changes = append(changes, fmt.Sprintf("attachments in %s", oc.AttachmentFolder))
+	}
+	if oc.DailyFolder != "" {
+		cfg.Settings["daily_folder"] = oc.DailyFolder
+		changes = append(changes, fmt.Sprintf("daily notes in %s", oc.DailyFolder))
+	}
+	if oc.DailyTemplate != "" {
+		cfg.Settings["daily_template"] = oc.DailyTemplate + ".md"
+		changes = append(changes, fmt.Sprintf("daily notes from template %s.md", oc.DailyTemplate))
+	}
+	if oc.DailyFormat != "" {
+		cfg.Metadata["obsidian_daily_format"] = oc.DailyFormat
+		if layout, ok := MomentToGoLayout(oc.DailyFormat); ok {
+			cfg.Settings["date_format"] = layout
+			changes = append(changes, fmt.Sprintf("date format %s (from %s)", layout, oc.DailyFormat))
+		} else {
+			changes = append(changes, fmt.Sprintf("kept the default date format, %s cannot be converted", oc.DailyFormat))
+		}
+	}
+	cfg.Metadata["imported_from"] = "obsidian"
+	return changes
+}
+
+// momentTokens maps moment.js date tokens to Go layout elements, longest first.
+var momentTokens = []struct{ moment, layout string }{
+	{"YYYY", "2006"}, {"YY", "06"},
+	{"MMMM", "January"}, {"MMM", "Jan"}, {"MM", "01"}, {"M", "1"},
+	{"dddd", "Monday"}, {"ddd", "Mon"},
+	{"DD", "02"}, {"D", "2"},
+	{"HH", "15"}, {"hh", "03"}, {"h", "3"},
+	{"mm", "04"}, {"m", "4"},
+	{"ss", "05"}, {"s", "5"},
+	{"A", "PM"}, {"a", "pm"},
+}
+
+// MomentToGoLayout converts a moment.js date format, as used by Obsidian, to a Go time layout.
+// It reports false if the format uses tokens Go layouts cannot express, such as week numbers,
+// or literal text a Go layout would read as a date element, such as [Monday notes].
+func MomentToGoLayout(format string) (string, bool) {
+	var pieces []layoutPiece
+	for i := 0; i < len(format); {
+		// [text] is literal in moment.js
+		if format[i] == '[' {
+			end := strings.IndexByte(format[i:], ']')
+			if end < 0 {
+				return "", false
+			}
+			pieces = append(pieces, layoutPiece{text: format[i+1 : i+end], literal: true})
+			i += end + 1
+			continue
+		}
+		matched := false
+		for _, t := range momentTokens {
+			if strings.HasPrefix(format[i:], t.moment) {
+				pieces = append(pieces, layoutPiece{text: t.layout})
+				i += len(t.moment)
+				matched = true
+				break
+			}
+		}
+		if matched {
+			continue
+		}
+		c := format[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') {
+			return "", false
+		}
+		pieces = append(pieces, layoutPiece{text: string(c), literal: true})
+		i++
+	}
+	var b strings.Builder
+	for _, p := range pieces {
+		b.WriteString(p.text)
+	}
+	layout := b.String()
+	// Go layouts have no escapes, so the literal text must come out of the layout unchanged
+	for _, t := range layoutProbes {
+		var want strings.Builder
+		for _, p := range pieces {
+			if p.literal {
+				want.WriteString(p.text)
+			} else {
+				want.WriteString(t.Format(p.text))
+			}
+		}
+		if t.Format(layout) != want.String() {
+			return "", false
+		}
+	}
+	return layout, true
+}
+
+// layoutPiece is a date element or literal text of a converted moment.js format.
+type layoutPiece struct {
+	text    string
+	literal bool
+}
+
+// layoutProbes are times that differ in every element a Go layout can show.
+var layoutProbes = []time.Time{
+	time.Date(2009, time.November, 10, 23, 4, 5, 0, time.UTC),
+	time.Date(1998, time.February, 3, 7, 38, 41, 120_000_000, time.FixedZone("CET", 3600)),
+}
@@ -0,0 +1,34 @@
+package vault
+
+import "testing"
+
+func TestMomentToGoLayout(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+		wantOK bool
+	}{
+		{format: "YYYY-MM-DD", want: "2006-01-02", wantOK: true},
+		{format: "dddd, MMMM D, YYYY", want: "Monday, January 2, 2006", wantOK: true},
+		{format: "YY/M/D h:mm a", want: "06/1/2 3:04 pm", wantOK: true},
+		{format: "DD.MM.YYYY HH:mm:ss", want: "02.01.2006 15:04:05", wantOK: true},
+		{format: "[Daily] YYYY-MM-DD", want: "Daily 2006-01-02", wantOK: true},
+		{format: "YYYY-MM-DD [notes]", want: "2006-01-02 notes", wantOK: true},
+		{format: "[Monday notes] YYYY"},
+		{format: "[Week 1] YYYY"},
+		{format: "YYYY[0]DD"},
+		{format: "[Jan] YYYY"},
+		{format: "YYYY-[W]ww"},
+		{format: "gggg-[W]ww"},
+		{format: "[unclosed YYYY"},
+		{format: "Do MMMM"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, ok := MomentToGoLayout(tt.format)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("MomentToGoLayout(%q) = %q, %v, want %q, %v", tt.format, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
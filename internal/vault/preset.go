@@ -0,0 +1,188 @@
+package vault
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cobra-cli/internal/config"
+	"cobra-cli/internal/models"
+)
+
+// DefaultPreset is the preset used when none is chosen: an empty vault with the default config.
+const DefaultPreset = "basic"
+
+// Preset describes how a new vault is laid out: the folders to create, starter templates
+// written to the templates directory, and what goes into its vault.json.
+// Templates may use {{title}} and {{date}}, which are filled in when a note is created.
+type Preset struct {
+	Name           string
+	Title          string
+	Description    string
+	Folders        []string          // Created relative to the vault root
+	Templates      map[string]string // File name in the templates directory → contents
+	IgnorePatterns []string          // Added to the default ignore patterns
+	SupportedTypes []string          // Replaces the default supported types when set
+	Settings       map[string]any    // Written to the vault's settings
+}
+
+// Presets lists the built-in vault presets, in the order they are offered.
+var Presets = []Preset{
+	{
+		Name:        DefaultPreset,
+		Title:       "Basic",
+		Description: "An empty vault with the default settings",
+	},
+	{
+		Name:        "zettelkasten",
+		Title:       "Zettelkasten",
+		Description: "Atomic, densely linked notes named by timestamp IDs",
+		Folders:     []string{"inbox", "zettels", "references"},
+		Templates: map[string]string{
+			"zettel.md":    "# {{title}}\n\nID: {{date}}\nTags: \n\n\n\n## Links\n\n- \n",
+			"reference.md": "# {{title}}\n\nSource: \nAuthor: \nRead: {{date}}\n\n## Summary\n\n\n## Notes\n\n",
+		},
+		SupportedTypes: []string{".md"},
+		Settings:       map[string]any{"date_format": "200601021504"},
+	},
+	{
+		Name:        "para",
+		Title:       "PARA",
+		Description: "Projects, Areas, Resources and Archives",
+		Folders:     []string{"1 Projects", "2 Areas", "3 Resources", "4 Archives"},
+		Templates: map[string]string{
+			"project.md":  "# {{title}}\n\nStarted: {{date}}\nDeadline: \nStatus: active\n\n## Goal\n\n\n## Tasks\n\n- [ ] \n",
+			"area.md":     "# {{title}}\n\n## Standard to maintain\n\n\n## Projects\n\n",
+			"resource.md": "# {{title}}\n\nAdded: {{date}}\nSource: \n\n",
+		},
+	},
+	{
+		Name:        "journal",
+		Title:       "Journal",
+		Description: "One note per day in journal/, with daily and weekly templates",
+		Folders:     []string{"journal"},
+		Templates: map[string]string{
+			"daily.md":  "# {{date}}\n\n## Today\n\n- [ ] \n\n## Notes\n\n\n## Reflection\n\n",
+			"weekly.md": "# Week of {{date}}\n\n## Highlights\n\n\n## Next week\n\n- [ ] \n",
+		},
+		SupportedTypes: []string{".md"},
+		Settings: map[string]any{
+			"date_format":    "2006-01-02",
+			"daily_folder":   "journal",
+			"daily_template": "templates/daily.md",
+		},
+	},
+	{
+		Name:        "project-docs",
+		Title:       "Project docs",
+		Description: "Documentation for a software project: guides, decisions and meetings",
+		Folders:     []string{"docs", "docs/decisions", "meetings"},
+		Templates: map[string]string{
+			"adr.md":     "# {{title}}\n\nDate: {{date}}\nStatus: proposed\n\n## Context\n\n\n## Decision\n\n\n## Consequences\n\n",
+			"meeting.md": "# {{title}}\n\nDate: {{date}}\nAttendees: \n\n## Agenda\n\n\n## Notes\n\n\n## Action items\n\n- [ ] \n",
+			"guide.md":   "# {{title}}\n\n## Overview\n\n\n## Steps\n\n1. \n",
+		},
+		IgnorePatterns: []string{"vendor", "dist", "build", ".venv"},
+		SupportedTypes: []string{".md", ".txt", ".pdf"},
+	},
+}
+
+// PresetNames returns the names of the built-in presets.
+func PresetNames() []string {
+	names := make([]string, len(Presets))
+	for i, p := range Presets {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// LookupPreset finds a preset by name, ignoring case.
+func LookupPreset(name string) (Preset, error) {
+	for _, p := range Presets {
+		if strings.EqualFold(p.Name, name) {
+			return p, nil
+		}
+	}
+	return Preset{}, fmt.Errorf("unknown preset %q (available: %s)", name, strings.Join(PresetNames(), ", "))
+}
+
+// Config returns the vault config the preset produces for a vault at dir.
+func (p Preset) Config(dir, name string) models.VaultConfig {
+	cfg := DefaultConfig(dir, name)
+	for _, pattern := range p.IgnorePatterns {
+		if !containsString(cfg.IgnorePatterns, pattern) {
+			cfg.IgnorePatterns = append(cfg.IgnorePatterns, pattern)
+		}
+	}
+	if len(p.SupportedTypes) > 0 {
+		cfg.SupportedTypes = append([]string(nil), p.SupportedTypes...)
+	}
+	for k, v := range p.Settings {
+		cfg.Settings[k] = v
+	}
+	cfg.Metadata["preset"] = p.Name
+	return cfg
+}
+
+// Create sets up a new vault at dir from preset p: its folders, starter templates and
+// vault.json. Existing files are never overwritten, and an existing vault.json is an error.
+// If dir is an Obsidian vault its settings are imported on top of the preset; the returned
+// lines describe what was imported.
+func Create(dir, name string, p Preset) (models.VaultConfig, []string, error) {
+	if _, err := os.Stat(config.VaultConfigPath(dir)); err == nil {
+		return models.VaultConfig{}, nil, fmt.Errorf("%s is already a vault", dir)
+	}
+	cfg := p.Config(dir, name)
+	var imported []string
+	if HasObsidian(dir) {
+		oc, err := ReadObsidian(dir)
+		if err != nil {
+			return cfg, nil, err
+		}
+		imported = oc.Apply(dir, &cfg)
+	}
+	for _, folder := range p.Folders {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.FromSlash(folder)), 0o755); err != nil {
+			return cfg, imported, err
+		}
+	}
+	if err := os.MkdirAll(cfg.TemplatesPath, 0o755); err != nil {
+		return cfg, imported, err
+	}
+	for file, contents := range p.Templates {
+		if err := writeNew(filepath.Join(cfg.TemplatesPath, file), contents); err != nil {
+			return cfg, imported, err
+		}
+	}
+	if err := config.WriteVault(dir, cfg); err != nil {
+		return cfg, imported, err
+	}
+	return cfg, imported, nil
+}
+
+// writeNew writes a file only if it does not exist yet.
+func writeNew(path, contents string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if errors.Is(err, os.ErrExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(contents); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
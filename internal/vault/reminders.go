@@ -0,0 +1,115 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"cobra-cli/internal/config"
+)
+
+// RemindersFile is the name of the file in a vault's data folder that records which
+// reminders were sent.
+const RemindersFile = "reminders.json"
+
+// remindedKeep is how long a sent reminder is remembered.
+const remindedKeep = 30 * 24 * time.Hour
+
+// Reminder is the moment an open task with a due date is to be brought up: its due day at
+// the time of its remind:, or at a default time of day.
+type Reminder struct {
+	Task
+	At time.Time
+}
+
+// Key identifies the reminder across runs, as long as the task's text and due date stay
+// the same.
+func (r Reminder) Key() string {
+	return r.Rel + "\x00" + r.Text + "\x00" + r.At.Format(time.RFC3339)
+}
+
+// ParseClock parses a time of day written as 15:04.
+func ParseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time of day like 09:30", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// Reminders returns the reminders of the open tasks with a due date, earliest first. Tasks
+// without a valid remind: are reminded at the time of day def.
+func Reminders(tasks []Task, def time.Duration) []Reminder {
+	var reminders []Reminder
+	for _, t := range tasks {
+		if t.Done || !t.HasDue() {
+			continue
+		}
+		at := def
+		if t.Remind != "" {
+			if clock, err := ParseClock(t.Remind); err == nil {
+				at = clock
+			}
+		}
+		day := t.Due
+		reminders = append(reminders, Reminder{Task: t, At: time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, day.Location()).Add(at)})
+	}
+	sort.SliceStable(reminders, func(i, j int) bool { return reminders[i].At.Before(reminders[j].At) })
+	return reminders
+}
+
+// remindersPath returns the sent-reminders file of the vault at dir.
+func remindersPath(dir string) string {
+	return config.VaultDataPath(dir, RemindersFile)
+}
+
+// ReadReminded loads when each reminder of the vault at dir was sent, by Key.
+func ReadReminded(dir string) (map[string]time.Time, error) {
+	sent := map[string]time.Time{}
+	data, err := os.ReadFile(remindersPath(dir))
+	if errors.Is(err, os.ErrNotExist) {
+		return sent, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &sent); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", remindersPath(dir), err)
+	}
+	return sent, nil
+}
+
+// MarkReminded records that the reminders were sent at now, forgetting reminders sent
+// long ago.
+func MarkReminded(dir string, reminders []Reminder, now time.Time) error {
+	path := remindersPath(dir)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	lock, err := config.LockFile(path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	sent, err := ReadReminded(dir)
+	if err != nil {
+		return err
+	}
+	for key, at := range sent {
+		if now.Sub(at) > remindedKeep {
+			delete(sent, key)
+		}
+	}
+	for _, r := range reminders {
+		sent[r.Key()] = now
+	}
+	data, err := json.Marshal(sent)
+	if err != nil {
+		return err
+	}
+	return config.WriteFileAtomic(path, data, 0o644)
+}
@@ -0,0 +1,88 @@
+package vault
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+
+	"cobra-cli/internal/models"
+)
+
+// Query describes what Search looks for.
+type Query struct {
+	Text  string
+	Files bool // Match note names only
+	Dirs  bool // Match directory names only
+}
+
+// Match is a single search hit. Line is 0 for name matches.
+type Match struct {
+	Vault string
+	Rel   string
+	Line  int
+	Text  string
+	IsDir bool
+}
+
+// Search looks for q.Text (case-insensitively) in the names and contents of the notes of v.
+// With q.Files or q.Dirs only names of that kind are matched.
+func Search(v models.Vault, q Query) ([]Match, error) {
+	needle := strings.ToLower(q.Text)
+	var matches []Match
+	err := Walk(v.Path, v.Config, func(e Entry) error {
+		nameHit := strings.Contains(strings.ToLower(e.Rel), needle)
+		switch {
+		case q.Dirs:
+			if e.IsDir && nameHit {
+				matches = append(matches, Match{Vault: v.Name, Rel: e.Rel, IsDir: true})
+			}
+			return nil
+		case e.IsDir:
+			return nil
+		case nameHit:
+			matches = append(matches, Match{Vault: v.Name, Rel: e.Rel})
+		}
+		if q.Files || needle == "" || !IsText(e.Rel) {
+			return nil
+		}
+		hits, err := grepFile(e.Path, needle)
+		if err != nil {
+			return err
+		}
+		for _, h := range hits {
+			h.Vault, h.Rel = v.Name, e.Rel
+			matches = append(matches, h)
+		}
+		return nil
+	})
+	return matches, err
+}
+
+// grepFile returns the lines of the file at path that contain needle. A file with a
+// line too long for the scanner is not a note to search and gives no hits.
+func grepFile(path, needle string) ([]Match, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var hits []Match
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if strings.Contains(strings.ToLower(text), needle) {
+			hits = append(hits, Match{Line: line, Text: strings.TrimSpace(text)})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return hits, nil
+}
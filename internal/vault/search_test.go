@@ -0,0 +1,38 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cobra-cli/internal/models"
+)
+
+func TestSearchSkipsUnreadableContents(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"plan.md":         "# Plan\n\nFind the needle\n",
+		"needle-scan.pdf": "%PDF-1.7 needle\n",
+		"long.md":         strings.Repeat("needle ", 300*1024) + "\nneedle\n",
+	}
+	for rel, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v := models.Vault{Name: "test", Path: dir, Config: DefaultConfig(dir, "test")}
+
+	matches, err := Search(v, Query{Text: "Needle"})
+	if err != nil {
+		t.Fatalf("Search() = %v, want the over-long file skipped", err)
+	}
+	var got []string
+	for _, m := range matches {
+		got = append(got, m.Rel+":"+m.Text)
+	}
+	want := []string{"needle-scan.pdf:", "plan.md:Find the needle"}
+	if strings.Join(got, ", ") != strings.Join(want, ", ") {
+		t.Errorf("Search() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,187 @@
+package vault
+
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/notes"
+)
+
+// TextTypes are the note extensions whose contents are read for words, tags and links.
+var TextTypes = []string{".md", ".markdown", ".txt"}
+
+// Stats summarizes the contents of a vault.
+type Stats struct {
+	Vault       string         `json:"vault"`
+	Path        string         `json:"path"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Notes       int            `json:"notes"`
+	Folders     int            `json:"folders"`
+	Words       int            `json:"words"`
+	Bytes       int64          `json:"bytes"`
+	Links       int            `json:"links"`
+	BrokenLinks int            `json:"broken_links"`
+	ByFolder    map[string]int `json:"by_folder"` // Notes per top-level folder, "/" for the vault root
+	ByType      map[string]int `json:"by_type"`   // Notes per extension
+	Tags        map[string]int `json:"tags"`      // Notes per tag
+	Orphans     []string       `json:"orphans"`   // Notes with no links in or out
+	Largest     []NoteSize     `json:"largest"`
+	Created     []PeriodCount  `json:"created"`  // Notes created per month
+	Modified    []PeriodCount  `json:"modified"` // Notes last modified per month
+}
+
+// NoteSize is the size of a single note.
+type NoteSize struct {
+	Rel   string `json:"path"`
+	Words int    `json:"words"`
+	Bytes int64  `json:"bytes"`
+}
+
+// PeriodCount counts notes in a period, such as a month formatted 2006-01.
+type PeriodCount struct {
+	Period string `json:"period"`
+	Count  int    `json:"count"`
+}
+
+// LargestNotes is how many notes Stats lists in Largest.
+const LargestNotes = 10
+
+// CollectStats walks v and gathers its statistics; cross-vault links resolve through r.
+// A note's creation date is read from a created or date frontmatter field, falling back
+// to its modification time.
+func CollectStats(r *Registry, v models.Vault) (Stats, error) {
+	s := Stats{
+		Vault:       v.Name,
+		Path:        v.Path,
+		GeneratedAt: time.Now(),
+		ByFolder:    map[string]int{},
+		ByType:      map[string]int{},
+		Tags:        map[string]int{},
+		Orphans:     []string{},
+	}
+	ix, err := r.Index(v)
+	if err != nil {
+		return s, err
+	}
+
+	err = Walk(v.Path, v.Config, func(e Entry) error {
+		if e.IsDir {
+			s.Folders++
+		}
+		return nil
+	})
+	if err != nil {
+		return s, err
+	}
+
+	linked := map[string]bool{}
+	created := map[string]int{}
+	modified := map[string]int{}
+	var sizes []NoteSize
+	for _, e := range ix.Notes() {
+		s.Notes++
+		s.Bytes += e.Info.Size()
+		s.ByFolder[topFolder(e.Rel)]++
+		s.ByType[strings.ToLower(path.Ext(e.Rel))]++
+		modified[e.Info.ModTime().Format("2006-01")]++
+		size := NoteSize{Rel: e.Rel, Bytes: e.Info.Size()}
+		createdAt := e.Info.ModTime()
+
+		if IsText(e.Rel) {
+			data, err := os.ReadFile(e.Path)
+			if err != nil {
+				return s, err
+			}
+			content := string(data)
+			size.Words = notes.CountWords(content)
+			s.Words += size.Words
+			for _, tag := range notes.ParseTags(content) {
+				s.Tags[tag]++
+			}
+			fm, _ := notes.Frontmatter(content)
+			if t, ok := frontmatterTime(fm); ok {
+				createdAt = t
+			}
+		}
+		created[createdAt.Format("2006-01")]++
+		sizes = append(sizes, size)
+
+		refs, err := r.NoteLinks(v, e)
+		if err != nil {
+			return s, err
+		}
+		for _, ref := range refs {
+			s.Links++
+			if !ref.Resolved() {
+				s.BrokenLinks++
+				continue
+			}
+			linked[e.Rel] = true
+			if ref.ToVault == v.Name {
+				linked[ref.ToRel] = true
+			}
+		}
+	}
+
+	for _, e := range ix.Notes() {
+		if !linked[e.Rel] {
+			s.Orphans = append(s.Orphans, e.Rel)
+		}
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		if sizes[i].Words != sizes[j].Words {
+			return sizes[i].Words > sizes[j].Words
+		}
+		return sizes[i].Bytes > sizes[j].Bytes
+	})
+	if len(sizes) > LargestNotes {
+		sizes = sizes[:LargestNotes]
+	}
+	s.Largest = sizes
+	s.Created = periodCounts(created)
+	s.Modified = periodCounts(modified)
+	return s, nil
+}
+
+// IsText reports whether a note's contents can be read as text.
+func IsText(rel string) bool {
+	return containsString(TextTypes, strings.ToLower(path.Ext(rel)))
+}
+
+func topFolder(rel string) string {
+	if i := strings.IndexByte(rel, '/'); i >= 0 {
+		return rel[:i]
+	}
+	return "/"
+}
+
+// frontmatterTime reads the note's creation time from its created or date field.
+func frontmatterTime(fm map[string]any) (time.Time, bool) {
+	for _, key := range []string{"created", "date"} {
+		switch v := fm[key].(type) {
+		case time.Time:
+			return v, true
+		case string:
+			for _, layout := range []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"} {
+				if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+					return t, true
+				}
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// periodCounts sorts per-period counts chronologically.
+func periodCounts(counts map[string]int) []PeriodCount {
+	periods := make([]PeriodCount, 0, len(counts))
+	for p, n := range counts {
+		periods = append(periods, PeriodCount{Period: p, Count: n})
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Period < periods[j].Period })
+	return periods
+}
@@ -0,0 +1,92 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"cobra-cli/internal/models"
+)
+
+func TestCollectStats(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"README.md":           "---\ncreated: 2024-01-15\ntags: [Start]\n---\nHello world, see [[projects/plan]] #idea\n",
+		"orphan.md":           "alone\n",
+		"projects/plan.md":    "one two three [[missing]] #idea\n",
+		"projects/scan.pdf":   "%PDF-1.7 not words\n",
+		"projects/notes.tmp":  "not a supported type\n",
+		"templates/daily.md":  "# {{date}} #daily [[README]]\n",
+		".trash/old.md":       "deleted words here #gone\n",
+		".noted/scratch.md":   "noted's own state\n",
+		".git/COMMIT_EDITMSG": "ignored\n",
+	}
+	modTime := time.Date(2025, 3, 10, 12, 0, 0, 0, time.Local)
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v := models.Vault{Name: "test", Path: dir, Config: DefaultConfig(dir, "test")}
+
+	s, err := CollectStats(NewRegistry([]models.Vault{v}), v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]int{
+		"notes":        s.Notes,
+		"folders":      s.Folders,
+		"words":        s.Words,
+		"links":        s.Links,
+		"broken links": s.BrokenLinks,
+	}
+	wantCounts := map[string]int{"notes": 4, "folders": 1, "words": 11, "links": 2, "broken links": 1}
+	if !reflect.DeepEqual(counts, wantCounts) {
+		t.Errorf("counts = %v, want %v", counts, wantCounts)
+	}
+	checks := []struct {
+		name      string
+		got, want any
+	}{
+		{"ByFolder", s.ByFolder, map[string]int{"/": 2, "projects": 2}},
+		{"ByType", s.ByType, map[string]int{".md": 3, ".pdf": 1}},
+		{"Tags", s.Tags, map[string]int{"start": 1, "idea": 2}},
+		{"Orphans", s.Orphans, []string{"orphan.md", "projects/scan.pdf"}},
+		{"Created", s.Created, []PeriodCount{{Period: "2024-01", Count: 1}, {Period: "2025-03", Count: 3}}},
+		{"Modified", s.Modified, []PeriodCount{{Period: "2025-03", Count: 4}}},
+	}
+	for _, c := range checks {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, c.got, c.want)
+		}
+	}
+	var largest []string
+	for _, n := range s.Largest {
+		largest = append(largest, n.Rel)
+	}
+	// Ties in words are broken by size
+	if want := []string{"README.md", "projects/plan.md", "orphan.md", "projects/scan.pdf"}; !reflect.DeepEqual(largest, want) {
+		t.Errorf("Largest = %v, want %v", largest, want)
+	}
+}
+
+func TestCollectStatsEmptyVault(t *testing.T) {
+	dir := t.TempDir()
+	v := models.Vault{Name: "test", Path: dir, Config: DefaultConfig(dir, "test")}
+	s, err := CollectStats(NewRegistry([]models.Vault{v}), v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Notes != 0 || s.Words != 0 || len(s.Orphans) != 0 || len(s.Created) != 0 {
+		t.Errorf("CollectStats() of an empty vault = %+v", s)
+	}
+}
@@ -0,0 +1,64 @@
+package vault
+
+import (
+	"os"
+	"sort"
+	"time"
+
+	"cobra-cli/internal/models"
+	"cobra-cli/internal/notes"
+)
+
+// Task is a checkbox item found in a note of a vault.
+type Task struct {
+	notes.Task
+	Rel string // Note the task is in
+}
+
+// Overdue reports whether the task is open and was due before the day of now.
+func (t Task) Overdue(now time.Time) bool {
+	return !t.Done && t.HasDue() && t.Due.Before(startOfDay(now))
+}
+
+// DueOn reports whether the task is due on the day of now.
+func (t Task) DueOn(now time.Time) bool {
+	return t.HasDue() && t.Due.Equal(startOfDay(now))
+}
+
+// CollectTasks indexes the tasks of every text note of v, in note and line order.
+func CollectTasks(v models.Vault) ([]Task, error) {
+	entries, err := Notes(v.Path, v.Config)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []Task
+	for _, e := range entries {
+		if !IsText(e.Rel) {
+			continue
+		}
+		data, err := os.ReadFile(e.Path)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range notes.ParseTasks(string(data)) {
+			tasks = append(tasks, Task{Task: t, Rel: e.Rel})
+		}
+	}
+	return tasks, nil
+}
+
+// SortAgenda orders tasks the way an agenda lists them: by due date, tasks without one
+// last, then by priority and position.
+func SortAgenda(tasks []Task) {
+	rank := map[string]int{"high": 0, "medium": 1, "": 2, "low": 3}
+	sort.SliceStable(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if a.HasDue() != b.HasDue() {
+			return a.HasDue()
+		}
+		if !a.Due.Equal(b.Due) {
+			return a.Due.Before(b.Due)
+		}
+		return rank[a.Priority] < rank[b.Priority]
+	})
+}
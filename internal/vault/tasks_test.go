@@ -0,0 +1,48 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cobra-cli/internal/models"
+)
+
+func TestCollectTasksSkipsTemplates(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"templates/daily.md":  "# {{date}}\n\n- [ ] \n- [ ] Review the inbox\n",
+		"archive/day1.md":     "# Day 1\n\n- [ ] \n",
+		"projects/plan.md":    "- [ ] Write the plan\n- [x] Pick a name\n",
+		".noted/scratch.md":   "- [ ] Not a note\n",
+		"templates/weekly.md": "- [ ] Weekly review\n",
+	}
+	for rel, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	v := models.Vault{Name: "test", Path: dir, Config: DefaultConfig(dir, "test")}
+
+	tasks, err := CollectTasks(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, task := range tasks {
+		got = append(got, task.Rel+": "+task.Text)
+	}
+	want := []string{"projects/plan.md: Write the plan", "projects/plan.md: Pick a name"}
+	if len(got) != len(want) {
+		t.Fatalf("CollectTasks() = %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("CollectTasks()[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package vault
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"cobra-cli/internal/config"
+	"cobra-cli/internal/journal"
+)
+
+// trashIDLayout names trashed items after their deletion time, so they sort chronologically.
+const trashIDLayout = "20060102-150405.000000000"
+
+// TrashItem is a deleted note waiting in a vault's trash. The note is kept at
+// .trash/<id>/<name> and its metadata next to it at .trash/<id>.json.
+type TrashItem struct {
+	ID        string    `json:"id"`
+	Path      string    `json:"path"` // Original vault-relative path
+	DeletedAt time.Time `json:"deleted_at"`
+	Size      int64     `json:"size"`
+}
+
+// Rel returns where the trashed note is kept, relative to the vault root.
+func (t TrashItem) Rel() string {
+	return path.Join(config.VaultTrashDir, t.ID, path.Base(t.Path))
+}
+
+// MetaRel returns where the item's metadata is kept, relative to the vault root.
+func (t TrashItem) MetaRel() string {
+	return path.Join(config.VaultTrashDir, t.ID+".json")
+}
+
+// Expired reports whether the item is older than retention days; a retention of 0 or
+// less keeps items forever.
+func (t TrashItem) Expired(retention int, now time.Time) bool {
+	return retention > 0 && now.Sub(t.DeletedAt) > time.Duration(retention)*24*time.Hour
+}
+
+// TrashChanges plans moving the note at rel, whose current contents are data and
+// permissions mode, into the trash. The changes are applied through the journal, so the
+// deletion can be undone.
+func TrashChanges(rel string, data []byte, mode os.FileMode, now time.Time) (TrashItem, []journal.Change, error) {
+	item := TrashItem{ID: now.UTC().Format(trashIDLayout), Path: rel, DeletedAt: now, Size: int64(len(data))}
+	meta, err := json.MarshalIndent(item, "", "  ")
+	if err != nil {
+		return item, nil, err
+	}
+	return item, []journal.Change{
+		{Path: rel, Before: journal.Contents(data)},
+		{Path: item.Rel(), After: journal.Contents(data), Mode: mode},
+		{Path: item.MetaRel(), After: journal.Contents(append(meta, '\n'))},
+	}, nil
+}
+
+// RestoreChanges plans moving a trashed item back to dest, relative to the vault at dir.
+func RestoreChanges(dir string, item TrashItem, dest string) ([]journal.Change, error) {
+	path := filepath.Join(dir, filepath.FromSlash(item.Rel()))
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	meta, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(item.MetaRel())))
+	if err != nil {
+		return nil, err
+	}
+	return []journal.Change{
+		{Path: item.Rel(), Before: journal.Contents(data)},
+		{Path: item.MetaRel(), Before: journal.Contents(meta)},
+		{Path: dest, After: journal.Contents(data), Mode: info.Mode().Perm()},
+	}, nil
+}
+
+// ListTrash returns the items in the trash of the vault at dir, most recently deleted first.
+// Metadata without a trashed note next to it is skipped.
+func ListTrash(dir string) ([]TrashItem, error) {
+	trash := filepath.Join(dir, config.VaultTrashDir)
+	entries, err := os.ReadDir(trash)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var items []TrashItem
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(trash, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var item TrashItem
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", e.Name(), err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(item.Rel()))); err != nil {
+			continue
+		}
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].DeletedAt.After(items[j].DeletedAt) })
+	return items, nil
+}
+
+// FindTrash looks up a trashed item by ID, or by original path or note name, in which case
+// the most recently deleted match wins.
+func FindTrash(items []TrashItem, name string) (TrashItem, bool) {
+	key := strings.ToLower(strings.Trim(trimExt(filepath.ToSlash(name)), "/"))
+	for _, item := range items {
+		if item.ID == name {
+			return item, true
+		}
+	}
+	for _, item := range items {
+		rel := strings.ToLower(trimExt(item.Path))
+		if rel == key || path.Base(rel) == key {
+			return item, true
+		}
+	}
+	return TrashItem{}, false
+}
+
+// RemoveTrash permanently deletes an item from the trash of the vault at dir. The journal
+// forgets the operations that kept the note's contents: its deletion, its restores, and
+// the edits of its original path up to the deletion.
+func RemoveTrash(dir string, item TrashItem) error {
+	if err := os.RemoveAll(filepath.Join(dir, config.VaultTrashDir, item.ID)); err != nil {
+		return err
+	}
+	err := os.Remove(filepath.Join(dir, filepath.FromSlash(item.MetaRel())))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return forgetTrash(dir, item)
+}
+
+// forgetTrash drops the journal operations of the vault at dir that hold the contents of
+// a trashed item.
+func forgetTrash(dir string, item TrashItem) error {
+	j, err := journal.Read(dir)
+	if err != nil {
+		return err
+	}
+	touches := func(op journal.Operation, rels ...string) bool {
+		for _, c := range op.Changes {
+			for _, rel := range rels {
+				if c.Path == rel {
+					return true
+				}
+			}
+		}
+		return false
+	}
+	// The note's history ends with the operation that moved it into the trash
+	deleted := 0
+	for _, op := range j.Operations {
+		if touches(op, item.MetaRel()) {
+			deleted = op.ID
+			break
+		}
+	}
+	_, err = journal.Forget(dir, func(op journal.Operation) bool {
+		return touches(op, item.Rel(), item.MetaRel()) || (op.ID <= deleted && touches(op, item.Path))
+	})
+	return err
+}
+
+// PurgeTrash permanently deletes the items of the vault at dir that are older than
+// retention days and returns them.
+func PurgeTrash(dir string, retention int, now time.Time) ([]TrashItem, error) {
+	items, err := ListTrash(dir)
+	if err != nil {
+		return nil, err
+	}
+	var purged []TrashItem
+	for _, item := range items {
+		if !item.Expired(retention, now) {
+			continue
+		}
+		if err := RemoveTrash(dir, item); err != nil {
+			return purged, err
+		}
+		purged = append(purged, item)
+	}
+	return purged, nil
+}
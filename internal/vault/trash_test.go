@@ -0,0 +1,76 @@
+package vault
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"cobra-cli/internal/journal"
+)
+
+func TestRemoveTrashForgetsContents(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	apply := func(changes ...journal.Change) {
+		if _, err := journal.Apply(dir, journal.Operation{Kind: journal.KindEdit, Changes: changes}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	edit := func(rel, before, after string) journal.Change {
+		return journal.Change{Path: rel, Before: journal.Contents([]byte(before)), After: journal.Contents([]byte(after))}
+	}
+
+	write("secret.md", "draft")
+	write("other.md", "1")
+	apply(edit("secret.md", "draft", "password"))
+	apply(edit("other.md", "1", "2"))
+	now := time.Now()
+	item, changes, err := TrashChanges("secret.md", []byte("password"), 0o644, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apply(changes...)
+	// A new note at the same path after the deletion keeps its history
+	apply(journal.Change{Path: "secret.md", After: journal.Contents([]byte("new"))})
+
+	if err := RemoveTrash(dir, item); err != nil {
+		t.Fatal(err)
+	}
+	j, err := journal.Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kept []string
+	for _, op := range j.Operations {
+		var paths []string
+		for _, c := range op.Changes {
+			paths = append(paths, c.Path)
+		}
+		kept = append(kept, strings.Join(paths, ","))
+	}
+	if want := []string{"other.md", "secret.md"}; strings.Join(kept, " ") != strings.Join(want, " ") || j.Cursor != 2 {
+		t.Errorf("journal kept %q with cursor %d, want %q and 2", kept, j.Cursor, want)
+	}
+	err = filepath.WalkDir(filepath.Join(dir, ".noted", journal.BlobsDir), func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err == nil && (string(data) == "password" || string(data) == "draft") {
+			t.Errorf("the journal still keeps %q", data)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(item.Rel()))); !os.IsNotExist(err) {
+		t.Errorf("the trashed note is still there")
+	}
+}
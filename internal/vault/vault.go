@@ -0,0 +1,138 @@
+package vault
+
+import (
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cobra-cli/internal/config"
+	"cobra-cli/internal/models"
+)
+
+// DefaultSupportedTypes are the note extensions used when a vault does not list any.
+var DefaultSupportedTypes = []string{".md"}
+
+// DefaultConfig returns the config a vault gets when nothing else is specified.
+func DefaultConfig(dir, name string) models.VaultConfig {
+	now := time.Now()
+	return models.VaultConfig{
+		Name:           name,
+		TemplatesPath:  filepath.Join(dir, "templates"),
+		LogPath:        filepath.Join(dir, "vault.log"),
+		HistoryPath:    filepath.Join(dir, "history.log"),
+		SupportedTypes: []string{".md", ".pdf"},
+		IgnorePatterns: []string{".git", "node_modules"},
+		CreatedAt:      now,
+		ModifiedAt:     now,
+		Metadata:       map[string]string{},
+		Settings:       map[string]any{},
+	}
+}
+
+// Entry is a file or directory inside a vault.
+type Entry struct {
+	Path  string // Absolute path
+	Rel   string // Path relative to the vault root, with forward slashes
+	IsDir bool
+	Info  fs.FileInfo
+}
+
+// Walk calls fn for every directory and note in the vault at root, skipping noted's data
+// and trash folders, the templates folder, anything matched by cfg.IgnorePatterns and files
+// whose extension is not in cfg.SupportedTypes.
+func Walk(root string, cfg models.VaultConfig, fn func(e Entry) error) error {
+	templates := templatesRel(root, cfg)
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == root {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if (rel == config.VaultDataDir || rel == config.VaultTrashDir || rel == templates) && d.IsDir() {
+			return filepath.SkipDir
+		}
+		if Ignored(cfg, rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && !IsNote(cfg, path) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(Entry{Path: path, Rel: rel, IsDir: d.IsDir(), Info: info})
+	})
+}
+
+// templatesRel returns the vault-relative path of the templates folder of cfg, or "" when
+// it lies outside the vault at root.
+func templatesRel(root string, cfg models.VaultConfig) string {
+	if cfg.TemplatesPath == "" {
+		return ""
+	}
+	dir := cfg.TemplatesPath
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	rel, err := filepath.Rel(root, dir)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Notes returns every note in the vault at root.
+func Notes(root string, cfg models.VaultConfig) ([]Entry, error) {
+	var notes []Entry
+	err := Walk(root, cfg, func(e Entry) error {
+		if !e.IsDir {
+			notes = append(notes, e)
+		}
+		return nil
+	})
+	return notes, err
+}
+
+// IsNote reports whether path has one of the vault's supported extensions.
+func IsNote(cfg models.VaultConfig, path string) bool {
+	types := cfg.SupportedTypes
+	if len(types) == 0 {
+		types = DefaultSupportedTypes
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	for _, t := range types {
+		if strings.ToLower(t) == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// Ignored reports whether a vault-relative path matches one of the ignore patterns.
+// A pattern matches either the whole relative path or any single path segment.
+func Ignored(cfg models.VaultConfig, rel string) bool {
+	segments := strings.Split(rel, "/")
+	for _, pattern := range cfg.IgnorePatterns {
+		pattern = strings.Trim(filepath.ToSlash(pattern), "/")
+		if ok, _ := filepath.Match(pattern, rel); ok {
+			return true
+		}
+		for _, seg := range segments {
+			if ok, _ := filepath.Match(pattern, seg); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
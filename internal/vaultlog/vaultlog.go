@@ -0,0 +1,251 @@
+package vaultlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	log "github.com/charmbracelet/log"
+
+	"cobra-cli/internal/config"
+)
+
+// Events written to the vault log.
+const (
+	EventCreate   = "create"
+	EventDelete   = "delete"
+	EventRename   = "rename"
+	EventTemplate = "template"
+	EventIndex    = "index"
+	EventRemind   = "remind"
+	EventError    = "error"
+)
+
+// Logger appends structured JSON records to a vault's log at VaultConfig.LogPath,
+// rotating the file once it grows past MaxSize. Every record carries an "event" key
+// naming what happened. The file is opened per record, so a Logger needs no closing
+// and several noted processes can share a log.
+type Logger struct {
+	Path    string
+	Level   log.Level
+	MaxSize int64 // Rotate once the log reaches this many bytes, 0 to never rotate
+	Keep    int   // Rotated logs to keep as <path>.1 … <path>.Keep
+}
+
+// Debug records an event at debug level.
+func (l *Logger) Debug(event, msg string, keyvals ...any) error {
+	return l.write(log.DebugLevel, event, msg, keyvals...)
+}
+
+// Info records an event at info level.
+func (l *Logger) Info(event, msg string, keyvals ...any) error {
+	return l.write(log.InfoLevel, event, msg, keyvals...)
+}
+
+// Warn records an event at warn level.
+func (l *Logger) Warn(event, msg string, keyvals ...any) error {
+	return l.write(log.WarnLevel, event, msg, keyvals...)
+}
+
+// Error records err at error level.
+func (l *Logger) Error(event string, err error, keyvals ...any) error {
+	return l.write(log.ErrorLevel, event, err.Error(), keyvals...)
+}
+
+func (l *Logger) write(level log.Level, event, msg string, keyvals ...any) error {
+	if l == nil || l.Path == "" || level < l.Level {
+		return nil
+	}
+	lock, err := config.LockFile(l.Path)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+	if err := l.rotate(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	logger := log.NewWithOptions(f, log.Options{
+		Level:           l.Level,
+		ReportTimestamp: true,
+		TimeFormat:      time.RFC3339,
+		Formatter:       log.JSONFormatter,
+	})
+	logger.Log(level, msg, append([]any{"event", event}, keyvals...)...)
+	return f.Close()
+}
+
+// rotate shifts <path> to <path>.1, <path>.1 to <path>.2 and so on once the log is full,
+// dropping the oldest beyond Keep.
+func (l *Logger) rotate() error {
+	if l.MaxSize <= 0 {
+		return nil
+	}
+	info, err := os.Stat(l.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Size() < l.MaxSize {
+		return nil
+	}
+	if l.Keep <= 0 {
+		return os.Remove(l.Path)
+	}
+	os.Remove(rotated(l.Path, l.Keep))
+	for i := l.Keep - 1; i >= 1; i-- {
+		if err := os.Rename(rotated(l.Path, i), rotated(l.Path, i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Rename(l.Path, rotated(l.Path, 1))
+}
+
+func rotated(path string, n int) string {
+	return fmt.Sprintf("%s.%d", path, n)
+}
+
+// Record is one parsed line of a vault log.
+type Record struct {
+	Time   time.Time
+	Level  log.Level
+	Event  string
+	Msg    string
+	Fields map[string]any // Every other key, such as the note a record is about
+}
+
+// Matches reports whether the record is at least level and, if event is set, is that event.
+func (r Record) Matches(level log.Level, event string) bool {
+	return r.Level >= level && (event == "" || strings.EqualFold(r.Event, event))
+}
+
+// Read returns the records of the log at path, including its rotated files, oldest first.
+func Read(path string, keep int) ([]Record, error) {
+	var records []Record
+	for i := keep; i >= 0; i-- {
+		p := path
+		if i > 0 {
+			p = rotated(path, i)
+		}
+		recs, err := readFile(p)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, recs...)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
+
+func readFile(path string) ([]Record, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var records []Record
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if r, ok := ParseRecord(scanner.Bytes()); ok {
+			records = append(records, r)
+		}
+	}
+	return records, scanner.Err()
+}
+
+// Follower reads the records appended to a log since it last looked, starting over from
+// the top of the file when the log is rotated. A line still being written is held back
+// until it is complete.
+type Follower struct {
+	Path    string
+	info    os.FileInfo
+	offset  int64
+	partial string
+}
+
+// NewFollower returns a Follower of the log at path that starts at its current end.
+func NewFollower(path string) *Follower {
+	f := &Follower{Path: path}
+	if info, err := os.Stat(path); err == nil {
+		f.info, f.offset = info, info.Size()
+	}
+	return f
+}
+
+// Next returns the records appended since the previous call. A missing log, as between
+// rotating it and writing the next record, has nothing new; once it is back it is read
+// from the top.
+func (f *Follower) Next() ([]Record, error) {
+	info, err := os.Stat(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		f.info, f.offset, f.partial = nil, 0, ""
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if (f.info != nil && !os.SameFile(f.info, info)) || info.Size() < f.offset {
+		f.offset, f.partial = 0, ""
+	}
+	f.info = info
+	if info.Size() == f.offset {
+		return nil, nil
+	}
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data := make([]byte, info.Size()-f.offset)
+	n, err := file.ReadAt(data, f.offset)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	f.offset += int64(n)
+	lines := strings.Split(f.partial+string(data[:n]), "\n")
+	f.partial = lines[len(lines)-1]
+	var records []Record
+	for _, line := range lines[:len(lines)-1] {
+		if r, ok := ParseRecord([]byte(line)); ok {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+// ParseRecord decodes one JSON log line, reporting false for lines that are not records.
+func ParseRecord(line []byte) (Record, bool) {
+	fields := map[string]any{}
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return Record{}, false
+	}
+	var r Record
+	if s, ok := fields[log.TimestampKey].(string); ok {
+		r.Time, _ = time.Parse(time.RFC3339, s)
+	}
+	if s, ok := fields[log.LevelKey].(string); ok {
+		r.Level, _ = log.ParseLevel(s)
+	}
+	r.Msg, _ = fields[log.MessageKey].(string)
+	r.Event, _ = fields["event"].(string)
+	for _, key := range []string{log.TimestampKey, log.LevelKey, log.MessageKey, "event"} {
+		delete(fields, key)
+	}
+	r.Fields = fields
+	return r, !r.Time.IsZero()
+}
@@ -0,0 +1,153 @@
+package vaultlog
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	log "github.com/charmbracelet/log"
+)
+
+func messages(records []Record) []string {
+	var msgs []string
+	for _, r := range records {
+		msgs = append(msgs, r.Msg)
+	}
+	return msgs
+}
+
+func TestRotation(t *testing.T) {
+	tests := []struct {
+		name    string
+		maxSize int64
+		keep    int
+		want    []string // Messages left in the log and its rotated files, oldest first
+		files   []string // Files left in the folder
+	}{
+		{name: "never rotate", maxSize: 0, keep: 2, want: []string{"1", "2", "3", "4", "5"}, files: []string{"vault.log"}},
+		{name: "keep two", maxSize: 1, keep: 2, want: []string{"3", "4", "5"}, files: []string{"vault.log", "vault.log.1", "vault.log.2"}},
+		{name: "keep none", maxSize: 1, keep: 0, want: []string{"5"}, files: []string{"vault.log"}},
+		// Records are about 80 bytes, so a log of 100 holds two
+		{name: "room for two records", maxSize: 100, keep: 1, want: []string{"3", "4", "5"}, files: []string{"vault.log", "vault.log.1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l := &Logger{Path: filepath.Join(dir, "vault.log"), Level: log.InfoLevel, MaxSize: tt.maxSize, Keep: tt.keep}
+			for i := 1; i <= 5; i++ {
+				if err := l.Info(EventCreate, fmt.Sprint(i), "note", "a.md"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			records, err := Read(l.Path, tt.keep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := messages(records); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Read() = %v, want %v", got, tt.want)
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var files []string
+			for _, e := range entries {
+				if filepath.Ext(e.Name()) != ".lock" {
+					files = append(files, e.Name())
+				}
+			}
+			if !reflect.DeepEqual(files, tt.files) {
+				t.Errorf("files = %v, want %v", files, tt.files)
+			}
+		})
+	}
+}
+
+func TestLoggerLevel(t *testing.T) {
+	l := &Logger{Path: filepath.Join(t.TempDir(), "vault.log"), Level: log.WarnLevel}
+	if err := l.Info(EventIndex, "skipped"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Error(EventError, fmt.Errorf("kept"), "command", "noted mv"); err != nil {
+		t.Fatal(err)
+	}
+	records, err := Read(l.Path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Read() = %v, want only the error", records)
+	}
+	r := records[0]
+	if r.Msg != "kept" || r.Event != EventError || r.Level != log.ErrorLevel || r.Fields["command"] != "noted mv" {
+		t.Errorf("record = %+v", r)
+	}
+	if !r.Matches(log.WarnLevel, "ERROR") || r.Matches(log.WarnLevel, EventCreate) || r.Matches(log.FatalLevel, "") {
+		t.Errorf("Matches() disagrees with the record %+v", r)
+	}
+}
+
+func TestFollower(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vault.log")
+	l := &Logger{Path: path, Level: log.InfoLevel}
+	if err := l.Info(EventCreate, "before"); err != nil {
+		t.Fatal(err)
+	}
+	f := NewFollower(path)
+	next := func(want ...string) {
+		t.Helper()
+		records, err := f.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := messages(records); !reflect.DeepEqual(got, want) {
+			t.Errorf("Next() = %v, want %v", got, want)
+		}
+	}
+	next()
+
+	if err := l.Info(EventCreate, "one"); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Info(EventRename, "two"); err != nil {
+		t.Fatal(err)
+	}
+	next("one", "two")
+	next()
+
+	// A record written in two parts is read once it is complete
+	line := `{"time":"2025-03-10T12:00:00Z","level":"info","msg":"three","event":"create"}` + "\n"
+	appendLog := func(s string) {
+		t.Helper()
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		if _, err := file.WriteString(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	appendLog(line[:20])
+	next()
+	appendLog(line[20:] + "not a record\n")
+	next("three")
+
+	// After a rotation the new log is read from the top
+	l.MaxSize, l.Keep = 1, 1
+	if err := l.Info(EventCreate, "rotated"); err != nil {
+		t.Fatal(err)
+	}
+	next("rotated")
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	next()
+	if err := l.Info(EventCreate, "recreated"); err != nil {
+		t.Fatal(err)
+	}
+	next("recreated")
+}